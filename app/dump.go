@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// dumpProgress is the checkpoint of a dump import, the entries of the files before File and the entries
+// of File up to Entry are imported. The last progress is printed as the report
+type dumpProgress struct {
+	File     string        `json:"file"`
+	Entry    int           `json:"entry"`
+	Resumed  bool          `json:"resumed"`
+	Total    int           `json:"total"`
+	Created  int           `json:"created"`
+	Updated  int           `json:"updated"`
+	Failed   int           `json:"failed"`
+	Failures []dumpFailure `json:"failures"`
+}
+
+type dumpFailure struct {
+	File       string   `json:"file"`
+	Entry      int      `json:"entry,omitempty"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Errors     []string `json:"errors"`
+}
+
+// done tells whether the entry was imported by the run the progress was saved by
+func (progress dumpProgress) done(file string, entry int) bool {
+	return progress.Entry > 0 && (file < progress.File || (file == progress.File && entry <= progress.Entry))
+}
+
+// save replaces the checkpoint file at once, an interrupted save leaves the previous checkpoint
+func (progress dumpProgress) save(checkpoint string) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(checkpoint+".tmp", data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(checkpoint+".tmp", checkpoint)
+}
+
+// runImportDump imports the TMDB or OMDb dump file, or every dump file of the directory, and prints the counts.
+// The progress is saved to checkpoint after every entry, a run stopped by a failure or an interruption
+// resumes after the last imported entry. The checkpoint is removed once every entry is imported
+func runImportDump(ctx context.Context, movieUseCase domain.MovieUseCase, root string, images string, checkpoint string) (err error) {
+	if root == "" {
+		return errors.New("-file is required")
+	}
+	if checkpoint == "" {
+		checkpoint = strings.TrimRight(root, `/\`) + ".checkpoint"
+	}
+
+	progress := dumpProgress{Failures: []dumpFailure{}}
+	data, err := os.ReadFile(checkpoint)
+	if err == nil {
+		err = json.Unmarshal(data, &progress)
+		if err != nil {
+			return errors.New("checkpoint " + checkpoint + " is invalid: " + err.Error())
+		}
+		progress.Resumed = true
+		log.Infof("Resuming after entry %d of %s", progress.Entry, filepath.Join(root, progress.File))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	files, err := helper.DumpFiles(root)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if progress.Entry > 0 && file < progress.File {
+			continue
+		}
+
+		var storageErr error
+		err = func() error {
+			src, err := os.Open(filepath.Join(root, file))
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+
+			return helper.EachDumpEntry(src, filepath.Join(root, file), func(entry int, raw json.RawMessage) error {
+				if progress.done(file, entry) {
+					return nil
+				}
+
+				row, err := movieUseCase.ImportDumpMovie(ctx, domain.RequestMovieDump{Entry: raw, Images: images})
+				if err != nil {
+					storageErr = err
+					return err
+				}
+
+				progress.Total++
+				switch row.Status {
+				case domain.ImportStatusCreated:
+					progress.Created++
+				case domain.ImportStatusUpdated:
+					progress.Updated++
+				default:
+					progress.Failed++
+					progress.Failures = append(progress.Failures, dumpFailure{File: file, Entry: entry, ExternalID: row.ExternalID, Errors: row.Errors})
+				}
+				progress.File, progress.Entry = file, entry
+				storageErr = progress.save(checkpoint)
+				return storageErr
+			})
+		}()
+		if storageErr != nil {
+			return storageErr
+		}
+		// An unreadable file is reported and the next files are imported
+		if err != nil {
+			log.Warnf("%s: %v", filepath.Join(root, file), err)
+			progress.Failures = append(progress.Failures, dumpFailure{File: file, Errors: []string{"file could not be read: " + err.Error()}})
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(progress)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(checkpoint)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/movie/usecase"
+
+	"github.com/spf13/viper"
+)
+
+const dumpFixtures = "../fixtures/dump"
+
+// dumpRepo stores the movies by external ID like the upsert of the movie repository, the call numbered
+// failAt fails like a lost database connection
+type dumpRepo struct {
+	domain.MovieMySQLRepo
+	ids    map[string]int
+	calls  []string
+	failAt int
+}
+
+func (repo *dumpRepo) ImportDumpMovie(ctx context.Context, movie domain.MovieDump, user *domain.AuthUser) (id int, created bool, err error) {
+	repo.calls = append(repo.calls, movie.Movie.ExternalID)
+	if len(repo.calls) == repo.failAt {
+		return 0, false, errors.New("driver: bad connection")
+	}
+
+	id, ok := repo.ids[movie.Movie.ExternalID]
+	if !ok {
+		id = len(repo.ids) + 1
+		repo.ids[movie.Movie.ExternalID] = id
+	}
+	return id, !ok, nil
+}
+
+// importDump runs the import of the fixtures and returns the printed report
+func importDump(t *testing.T, repo *dumpRepo, checkpoint string) (progress dumpProgress, err error) {
+	t.Helper()
+	viper.Set("dump.region", "ID")
+	viper.Set("dump.download_images", false)
+	t.Cleanup(viper.Reset)
+
+	stdout := os.Stdout
+	report, err := os.Create(filepath.Join(t.TempDir(), "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer report.Close()
+	os.Stdout = report
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	movieUseCase := usecase.NewMovieUsecase(repo, nil, nil, nil, nil, nil, nil)
+	err = runImportDump(context.Background(), movieUseCase, dumpFixtures, "", checkpoint)
+	if err != nil {
+		return progress, err
+	}
+
+	data, err := os.ReadFile(report.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		t.Fatal(err)
+	}
+	return progress, nil
+}
+
+func TestRunImportDumpTwice(t *testing.T) {
+	repo := &dumpRepo{ids: map[string]int{}}
+	checkpoint := filepath.Join(t.TempDir(), "dump.checkpoint")
+
+	// Fight Club is in 550.json and again in updates.ndjson, the entry without a title fails
+	first, err := importDump(t, repo, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Total != 7 || first.Created != 5 || first.Updated != 1 || first.Failed != 1 {
+		t.Fatalf("got %+v, want 5 created, 1 updated and 1 failed", first)
+	}
+	if len(first.Failures) != 1 || first.Failures[0].File != "tmdb/popular.json" || first.Failures[0].Entry != 3 {
+		t.Fatalf("got failures %+v, want the third entry of tmdb/popular.json", first.Failures)
+	}
+
+	// A second run updates the same movies instead of creating new ones
+	second, err := importDump(t, repo, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Total != 7 || second.Created != 0 || second.Updated != 6 || second.Failed != 1 {
+		t.Fatalf("got %+v, want 6 updated and 1 failed", second)
+	}
+	if len(repo.ids) != 5 {
+		t.Fatalf("got %d movies, want 5", len(repo.ids))
+	}
+	if _, err := os.Stat(checkpoint); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got checkpoint %v, want it removed once every entry is imported", err)
+	}
+}
+
+func TestRunImportDumpResume(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "dump.checkpoint")
+
+	// The fourth movie written, Inception, is interrupted by a storage failure
+	repo := &dumpRepo{ids: map[string]int{}, failAt: 4}
+	_, err := importDump(t, repo, checkpoint)
+	if err == nil {
+		t.Fatal("got no error, want the storage failure")
+	}
+
+	var saved dumpProgress
+	data, err := os.ReadFile(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.File != "tmdb/popular.json" || saved.Entry != 1 || saved.Created != 3 {
+		t.Fatalf("got checkpoint %+v, want the first entry of tmdb/popular.json", saved)
+	}
+
+	// The next run starts at the interrupted entry and keeps the counts of the first run
+	repo.calls, repo.failAt = nil, 0
+	progress, err := importDump(t, repo, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"tmdb:27205", "tmdb:550", "tmdb:496243"}
+	if len(repo.calls) != len(want) {
+		t.Fatalf("got calls %v, want %v", repo.calls, want)
+	}
+	for idx := range want {
+		if repo.calls[idx] != want[idx] {
+			t.Fatalf("got calls %v, want %v", repo.calls, want)
+		}
+	}
+	if !progress.Resumed || progress.Total != 7 || progress.Created != 5 || progress.Updated != 1 || progress.Failed != 1 {
+		t.Fatalf("got %+v, want the counts of a single run", progress)
+	}
+	if _, err := os.Stat(checkpoint); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got checkpoint %v, want it removed once every entry is imported", err)
+	}
+}
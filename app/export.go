@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+)
+
+// runExport writes the movies matching query, formatted like the list query string, to out or the standard
+// output, in format or the one of the out extension. The job sees every status like an admin
+func runExport(ctx context.Context, movieUseCase domain.MovieUseCase, out string, format string, query string) (err error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return err
+	}
+	request, err := helper.MovieFilter(func(key string, defaultValue ...string) string {
+		if value := values.Get(key); value != "" {
+			return value
+		}
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return ""
+	})
+	if err != nil {
+		return err
+	}
+	request.User = &domain.AuthUser{Role: domain.RoleAdmin}
+
+	format = helper.RecordFormat(format, out)
+	if format == "" {
+		format = domain.ImportFormatCSV
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		dst, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+		w = dst
+	}
+	buffer := bufio.NewWriter(w)
+	err = movieUseCase.ExportMovie(ctx, request, format, buffer)
+	if err != nil {
+		return err
+	}
+	return buffer.Flush()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+)
+
+// runImport imports a movie file with its optional images ZIP and prints the report
+func runImport(ctx context.Context, movieUseCase domain.MovieUseCase, file string, images string, format string, mode string) (err error) {
+	if file == "" {
+		return errors.New("-file is required")
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	request := domain.RequestMovieImport{
+		File:   src,
+		Format: helper.RecordFormat(format, file),
+		Mode:   mode,
+	}
+
+	if images != "" {
+		zipFile, err := zip.OpenReader(images)
+		if err != nil {
+			return err
+		}
+		defer zipFile.Close()
+		request.Images = &zipFile.Reader
+	}
+
+	report, err := movieUseCase.ImportMovie(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		return err
+	}
+
+	if !report.Committed {
+		return errors.New("nothing was imported")
+	}
+	return nil
+}
@@ -8,11 +8,67 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"time"
 	"xsis-academy-test-service-movie/config"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
 
+	_DeliveryHTTPAudit "xsis-academy-test-service-movie/audit/delivery/http"
+	_RepoMySQLAudit "xsis-academy-test-service-movie/audit/repository/mysql"
+	_UsecaseAudit "xsis-academy-test-service-movie/audit/usecase"
+	_DeliveryHTTPBooking "xsis-academy-test-service-movie/booking/delivery/http"
+	_PaymentBooking "xsis-academy-test-service-movie/booking/payment"
+	_RepoMySQLBooking "xsis-academy-test-service-movie/booking/repository/mysql"
+	_UsecaseBooking "xsis-academy-test-service-movie/booking/usecase"
+	_DeliveryHTTPCalendar "xsis-academy-test-service-movie/calendar/delivery/http"
+	_UsecaseCalendar "xsis-academy-test-service-movie/calendar/usecase"
+	_DeliveryHTTPCollection "xsis-academy-test-service-movie/collection/delivery/http"
+	_RepoMySQLCollection "xsis-academy-test-service-movie/collection/repository/mysql"
+	_UsecaseCollection "xsis-academy-test-service-movie/collection/usecase"
 	_DeliveryHTTP "xsis-academy-test-service-movie/movie/delivery/http"
 	_RepoMySQLMovie "xsis-academy-test-service-movie/movie/repository/mysql"
+	_RepoRedisMovie "xsis-academy-test-service-movie/movie/repository/redis"
 	_UsecaseMovie "xsis-academy-test-service-movie/movie/usecase"
+	_DeliveryHTTPPromo "xsis-academy-test-service-movie/promo/delivery/http"
+	_RepoMySQLPromo "xsis-academy-test-service-movie/promo/repository/mysql"
+	_UsecasePromo "xsis-academy-test-service-movie/promo/usecase"
+	_DeliveryHTTPRecommendation "xsis-academy-test-service-movie/recommendation/delivery/http"
+	_RepoMySQLRecommendation "xsis-academy-test-service-movie/recommendation/repository/mysql"
+	_UsecaseRecommendation "xsis-academy-test-service-movie/recommendation/usecase"
+	_DeliveryHTTPRelation "xsis-academy-test-service-movie/relation/delivery/http"
+	_RepoMySQLRelation "xsis-academy-test-service-movie/relation/repository/mysql"
+	_UsecaseRelation "xsis-academy-test-service-movie/relation/usecase"
+	_DeliveryHTTPReview "xsis-academy-test-service-movie/review/delivery/http"
+	_RepoMySQLReview "xsis-academy-test-service-movie/review/repository/mysql"
+	_UsecaseReview "xsis-academy-test-service-movie/review/usecase"
+	_DeliveryHTTPSeat "xsis-academy-test-service-movie/seat/delivery/http"
+	_RepoMySQLSeat "xsis-academy-test-service-movie/seat/repository/mysql"
+	_RepoRedisSeat "xsis-academy-test-service-movie/seat/repository/redis"
+	_UsecaseSeat "xsis-academy-test-service-movie/seat/usecase"
+	_DeliveryHTTPShowtime "xsis-academy-test-service-movie/showtime/delivery/http"
+	_RepoMySQLShowtime "xsis-academy-test-service-movie/showtime/repository/mysql"
+	_UsecaseShowtime "xsis-academy-test-service-movie/showtime/usecase"
+	_DeliveryHTTPSimilar "xsis-academy-test-service-movie/similar/delivery/http"
+	_RepoMySQLSimilar "xsis-academy-test-service-movie/similar/repository/mysql"
+	_UsecaseSimilar "xsis-academy-test-service-movie/similar/usecase"
+	_DeliveryHTTPTag "xsis-academy-test-service-movie/tag/delivery/http"
+	_RepoMySQLTag "xsis-academy-test-service-movie/tag/repository/mysql"
+	_UsecaseTag "xsis-academy-test-service-movie/tag/usecase"
+	_DeliveryHTTPTheater "xsis-academy-test-service-movie/theater/delivery/http"
+	_RepoMySQLTheater "xsis-academy-test-service-movie/theater/repository/mysql"
+	_UsecaseTheater "xsis-academy-test-service-movie/theater/usecase"
+	_DeliveryHTTPTicket "xsis-academy-test-service-movie/ticket/delivery/http"
+	_RepoMySQLTicket "xsis-academy-test-service-movie/ticket/repository/mysql"
+	_UsecaseTicket "xsis-academy-test-service-movie/ticket/usecase"
+	_DeliveryHTTPUserList "xsis-academy-test-service-movie/userlist/delivery/http"
+	_RepoMySQLUserList "xsis-academy-test-service-movie/userlist/repository/mysql"
+	_UsecaseUserList "xsis-academy-test-service-movie/userlist/usecase"
+	_DeliveryHTTPWatchlist "xsis-academy-test-service-movie/watchlist/delivery/http"
+	_RepoMySQLWatchlist "xsis-academy-test-service-movie/watchlist/repository/mysql"
+	_UsecaseWatchlist "xsis-academy-test-service-movie/watchlist/usecase"
+	_DeliveryHTTPWatchProvider "xsis-academy-test-service-movie/watchprovider/delivery/http"
+	_RepoMySQLWatchProvider "xsis-academy-test-service-movie/watchprovider/repository/mysql"
+	_UsecaseWatchProvider "xsis-academy-test-service-movie/watchprovider/usecase"
 
 	"github.com/go-redis/redis/v8"
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
@@ -34,6 +90,14 @@ const (
 func main() {
 	// CLI options parse
 	configFile := flag.String("c", "config.yaml", "Config file")
+	job := flag.String("job", "", "Run a job and exit: similar, recommendation, popularity, booking, publish, import, export, import-tmdb")
+	file := flag.String("file", "", "File read by the import job, dump file or directory read by the import-tmdb job")
+	images := flag.String("images", "", "ZIP of the images of the import job, directory of the posters of the import-tmdb job")
+	format := flag.String("format", "", "File format of the import job (csv, json or ndjson) or the export job (csv, ndjson or xlsx), guessed from the file extension when empty")
+	mode := flag.String("mode", "", "Import mode: transaction or best_effort")
+	out := flag.String("out", "", "File written by the export job, the standard output when empty")
+	checkpoint := flag.String("checkpoint", "", "Progress file of the import-tmdb job, the dump path followed by .checkpoint when empty")
+	query := flag.String("query", "", "Movie list filters of the export job as a query string, e.g. status=draft&tag=action")
 	flag.Parse()
 
 	// Config file
@@ -122,10 +186,137 @@ func main() {
 	}
 	log.Info("Redis connection established")
 
+	// Load review content filter word lists
+	contentFilter, err := helper.NewContentFilter([]string{viper.GetString("moderation.wordlist_en"), viper.GetString("moderation.wordlist_id")}, viper.GetInt("moderation.max_links"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Payment providers, webhooks are routed by provider name
+	paymentFake := _PaymentBooking.NewFakeProvider(viper.GetString("payment.fake.secret"), time.Duration(viper.GetInt("payment.webhook_tolerance"))*time.Second)
+
 	// Register repository & usecase public API
 	repoMySQLMovie := _RepoMySQLMovie.NewMySQLMovieRepository(dbConn)
+	repoRedisMovie := _RepoRedisMovie.NewRedisMovieRepository(dbRedis)
+	repoMySQLCollection := _RepoMySQLCollection.NewMySQLCollectionRepository(dbConn)
+	repoMySQLRelation := _RepoMySQLRelation.NewMySQLRelationRepository(dbConn)
+	repoMySQLTag := _RepoMySQLTag.NewMySQLTagRepository(dbConn)
+	repoMySQLReview := _RepoMySQLReview.NewMySQLReviewRepository(dbConn)
+	repoMySQLWatchlist := _RepoMySQLWatchlist.NewMySQLWatchlistRepository(dbConn)
+	repoMySQLUserList := _RepoMySQLUserList.NewMySQLUserListRepository(dbConn)
+	repoMySQLSimilar := _RepoMySQLSimilar.NewMySQLSimilarRepository(dbConn)
+	repoMySQLRecommendation := _RepoMySQLRecommendation.NewMySQLRecommendationRepository(dbConn)
+	repoMySQLTheater := _RepoMySQLTheater.NewMySQLTheaterRepository(dbConn)
+	repoMySQLShowtime := _RepoMySQLShowtime.NewMySQLShowtimeRepository(dbConn)
+	repoMySQLSeat := _RepoMySQLSeat.NewMySQLSeatRepository(dbConn)
+	repoRedisSeat := _RepoRedisSeat.NewRedisSeatRepository(dbRedis)
+	repoMySQLBooking := _RepoMySQLBooking.NewMySQLBookingRepository(dbConn)
+	repoMySQLTicket := _RepoMySQLTicket.NewMySQLTicketRepository(dbConn)
+	repoMySQLPromo := _RepoMySQLPromo.NewMySQLPromoRepository(dbConn)
+	repoMySQLWatchProvider := _RepoMySQLWatchProvider.NewMySQLWatchProviderRepository(dbConn)
+	repoMySQLAudit := _RepoMySQLAudit.NewMySQLAuditRepository(dbConn)
+
+	usecaseMovie := _UsecaseMovie.NewMovieUsecase(repoMySQLMovie, repoRedisMovie, repoMySQLCollection, repoMySQLTag, repoMySQLReview, repoMySQLWatchlist, repoMySQLUserList)
+	usecaseCollection := _UsecaseCollection.NewCollectionUsecase(repoMySQLCollection, repoMySQLMovie)
+	usecaseRelation := _UsecaseRelation.NewRelationUsecase(repoMySQLRelation, repoMySQLMovie)
+	usecaseTag := _UsecaseTag.NewTagUsecase(repoMySQLTag, repoMySQLMovie)
+	usecaseReview := _UsecaseReview.NewReviewUsecase(repoMySQLReview, repoMySQLMovie, contentFilter)
+	usecaseWatchlist := _UsecaseWatchlist.NewWatchlistUsecase(repoMySQLWatchlist, repoMySQLMovie, usecaseMovie)
+	usecaseUserList := _UsecaseUserList.NewUserListUsecase(repoMySQLUserList, repoMySQLMovie)
+	usecaseSimilar := _UsecaseSimilar.NewSimilarUsecase(repoMySQLSimilar, repoMySQLMovie)
+	usecaseRecommendation := _UsecaseRecommendation.NewRecommendationUsecase(repoMySQLRecommendation)
+	usecaseTheater := _UsecaseTheater.NewTheaterUsecase(repoMySQLTheater)
+	usecaseShowtime := _UsecaseShowtime.NewShowtimeUsecase(repoMySQLShowtime, repoMySQLMovie, repoMySQLTheater)
+	usecaseSeat := _UsecaseSeat.NewSeatUsecase(repoMySQLSeat, repoRedisSeat, repoMySQLTheater, repoMySQLShowtime)
+	usecaseWatchProvider := _UsecaseWatchProvider.NewWatchProviderUsecase(repoMySQLWatchProvider, repoMySQLMovie)
+	usecaseAudit := _UsecaseAudit.NewAuditUsecase(repoMySQLAudit)
+	usecaseCalendar := _UsecaseCalendar.NewCalendarUsecase(repoMySQLMovie, repoMySQLTheater, repoMySQLShowtime)
+	usecasePromo := _UsecasePromo.NewPromoUsecase(repoMySQLPromo, repoMySQLMovie, repoMySQLTheater)
+	usecaseBooking := _UsecaseBooking.NewBookingUsecase(repoMySQLBooking, repoMySQLSeat, repoRedisSeat, repoMySQLShowtime, usecasePromo, paymentFake)
+	usecaseTicket := _UsecaseTicket.NewTicketUsecase(repoMySQLTicket)
+
+	// Offline jobs run from the CLI and exit
+	if *job != "" {
+		switch *job {
+		case "similar":
+			err = usecaseSimilar.ComputeSimilar(ctx)
+		case "recommendation":
+			err = usecaseRecommendation.ComputeItemSimilarity(ctx)
+		case "popularity":
+			err = usecaseMovie.FlushMovieView(ctx)
+		case "booking":
+			err = usecaseBooking.ExpireBooking(ctx)
+		case "publish":
+			err = usecaseMovie.PublishScheduledMovie(ctx)
+		case "import":
+			err = runImport(ctx, usecaseMovie, *file, *images, *format, *mode)
+		case "import-tmdb":
+			err = runImportDump(ctx, usecaseMovie, *file, *images, *checkpoint)
+		case "export":
+			err = runExport(ctx, usecaseMovie, *out, *format, *query)
+		default:
+			err = fmt.Errorf("unknown job %s", *job)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Job " + *job + " done")
+		return
+	}
+
+	// Background job computing similar movies
+	if interval := viper.GetInt("similar.interval"); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+			defer ticker.Stop()
+			for {
+				if err := usecaseSimilar.ComputeSimilar(context.Background()); err != nil {
+					log.Error(err)
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
+	// Background job flushing movie views to MySQL
+	if interval := viper.GetInt("popularity.flush_interval"); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := usecaseMovie.FlushMovieView(context.Background()); err != nil {
+					log.Error(err)
+				}
+			}
+		}()
+	}
+
+	// Background job cancelling unpaid bookings
+	if interval := viper.GetInt("payment.expire_interval"); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := usecaseBooking.ExpireBooking(context.Background()); err != nil {
+					log.Error(err)
+				}
+			}
+		}()
+	}
+
+	// Background job publishing scheduled movies
+	if interval := viper.GetInt("movie.publish_interval"); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				if err := usecaseMovie.PublishScheduledMovie(context.Background()); err != nil {
+					log.Error(err)
+				}
+			}
+		}()
+	}
 
-	usecaseMovie := _UsecaseMovie.NewMovieUsecase(repoMySQLMovie)
 	// Initialize gRPC server
 	go func() {
 		listen, err := net.Listen("tcp", ":"+viper.GetString("server.grpc_port"))
@@ -151,6 +342,8 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: viper.GetString("middleware.allows_origin"),
 	}))
+	// Every mutating call of the routers below is recorded in the audit log
+	app.Use(middleware.Audit(usecaseAudit))
 
 	// HTTP routing
 	app.Get(viper.GetString("server.base_path")+"/", func(c *fiber.Ctx) error {
@@ -158,6 +351,23 @@ func main() {
 	})
 
 	_DeliveryHTTP.RouterAPI(app, usecaseMovie)
+	_DeliveryHTTPCollection.RouterAPI(app, usecaseCollection)
+	_DeliveryHTTPRelation.RouterAPI(app, usecaseRelation)
+	_DeliveryHTTPTag.RouterAPI(app, usecaseTag)
+	_DeliveryHTTPReview.RouterAPI(app, usecaseReview)
+	_DeliveryHTTPWatchlist.RouterAPI(app, usecaseWatchlist)
+	_DeliveryHTTPUserList.RouterAPI(app, usecaseUserList)
+	_DeliveryHTTPSimilar.RouterAPI(app, usecaseSimilar)
+	_DeliveryHTTPRecommendation.RouterAPI(app, usecaseRecommendation)
+	_DeliveryHTTPTheater.RouterAPI(app, usecaseTheater)
+	_DeliveryHTTPShowtime.RouterAPI(app, usecaseShowtime)
+	_DeliveryHTTPSeat.RouterAPI(app, usecaseSeat)
+	_DeliveryHTTPBooking.RouterAPI(app, usecaseBooking)
+	_DeliveryHTTPTicket.RouterAPI(app, usecaseTicket)
+	_DeliveryHTTPPromo.RouterAPI(app, usecasePromo)
+	_DeliveryHTTPCalendar.RouterAPI(app, usecaseCalendar)
+	_DeliveryHTTPWatchProvider.RouterAPI(app, usecaseWatchProvider)
+	_DeliveryHTTPAudit.RouterAPI(app, usecaseAudit)
 
 	// Start Fiber HTTP server
 	if err := app.Listen(":" + viper.GetString("server.port")); err != nil {
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/audit/delivery/http/handler"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for the audit log REST API, the log is read by admins
+func RouterAPI(app *fiber.App, AuditUseCase domain.AuditUseCase) {
+	handlerAudit := &handler.AuditHandler{AuditUseCase: AuditUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	audit := app.Group(basePath+"/audit", middleware.Auth, middleware.Role(domain.RoleAdmin))
+
+	audit.Get("", handlerAudit.GetAllAudit)
+	audit.Get("/export", handlerAudit.ExportAudit)
+}
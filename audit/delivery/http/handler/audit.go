@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type AuditHandler struct {
+	AuditUseCase domain.AuditUseCase
+}
+
+// parseFilter reads the audit log filters shared by the list and the export
+func parseFilter(c *fiber.Ctx) (input domain.RequestParamAudit, err error) {
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.Atoi(value)
+		if err != nil {
+			return input, err
+		}
+		input.UserID = &userID
+	}
+
+	if value := c.Query("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return input, err
+		}
+		input.Status = &status
+	}
+
+	if value := c.Query("method"); value != "" {
+		method := strings.ToUpper(value)
+		input.Method = &method
+	}
+
+	if value := c.Query("route"); value != "" {
+		input.Route = &value
+	}
+
+	if value := c.Query("path"); value != "" {
+		input.Path = &value
+	}
+
+	if value := c.Query("entity_id"); value != "" {
+		input.EntityID = &value
+	}
+
+	if value := c.Query("from"); value != "" {
+		input.From = &value
+	}
+
+	if value := c.Query("to"); value != "" {
+		input.To = &value
+	}
+
+	return input, nil
+}
+
+func (ah *AuditHandler) GetAllAudit(c *fiber.Ctx) error {
+	input, err := parseFilter(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := ah.AuditUseCase.GetAllAudit(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+// ExportAudit streams the matching logs as CSV, the filters are validated before the first byte is sent
+func (ah *AuditHandler) ExportAudit(c *fiber.Ctx) error {
+	input, err := parseFilter(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ah.AuditUseCase.ValidateAudit(input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="audit.csv"`)
+	c.Status(fasthttp.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := ah.AuditUseCase.ExportAudit(context.Background(), input, w); err != nil {
+			log.Error(err)
+		}
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,235 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlAuditRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLAuditRepository(Conn *sql.DB) domain.AuditMySQLRepo {
+	return &mysqlAuditRepository{Conn}
+}
+
+const auditQuery = `SELECT id, user_id, role, ip, user_agent, method, route, path, entity_id, status, payload, response,
+                  snapshot_before, snapshot_after, dtm_crt
+              FROM audit_log`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAudit(row rowScanner) (response domain.AuditLog, err error) {
+	var userID sql.NullInt32
+	var role, entityID, payload, body, before, after sql.NullString
+	var dtmCrt time.Time
+	err = row.Scan(
+		&response.ID,
+		&userID,
+		&role,
+		&response.IP,
+		&response.UserAgent,
+		&response.Method,
+		&response.Route,
+		&response.Path,
+		&entityID,
+		&response.Status,
+		&payload,
+		&body,
+		&before,
+		&after,
+		&dtmCrt,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	if userID.Valid {
+		id := int(userID.Int32)
+		response.UserID = &id
+	}
+	response.Role = nullString(role)
+	response.EntityID = nullString(entityID)
+	response.Payload = nullString(payload)
+	response.Response = nullString(body)
+	response.Before = nullString(before)
+	response.After = nullString(after)
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+func nullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func (db *mysqlAuditRepository) PostAudit(ctx context.Context, request domain.AuditLog) (err error) {
+	query := `INSERT INTO audit_log (user_id, role, ip, user_agent, method, route, path, entity_id, status, payload, response,
+                  snapshot_before, snapshot_after, dtm_crt)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.UserID, request.Role, request.IP, request.UserAgent, request.Method,
+		request.Route, request.Path, request.EntityID, request.Status, request.Payload, request.Response,
+		request.Before, request.After)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func filterAudit(request domain.RequestParamAudit) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.UserID != nil {
+		query += " AND user_id = ?"
+		args = append(args, *request.UserID)
+	}
+	if request.Method != nil {
+		query += " AND method = ?"
+		args = append(args, *request.Method)
+	}
+	if request.Route != nil {
+		query += " AND route = ?"
+		args = append(args, *request.Route)
+	}
+	if request.Path != nil {
+		query += " AND path LIKE ?"
+		args = append(args, *request.Path+"%")
+	}
+	if request.EntityID != nil {
+		query += " AND entity_id = ?"
+		args = append(args, *request.EntityID)
+	}
+	if request.Status != nil {
+		query += " AND status = ?"
+		args = append(args, *request.Status)
+	}
+	if request.From != nil {
+		query += " AND dtm_crt >= ?"
+		args = append(args, *request.From)
+	}
+	if request.To != nil {
+		query += " AND dtm_crt <= ?"
+		args = append(args, *request.To)
+	}
+
+	return query, args
+}
+
+func (db *mysqlAuditRepository) CountDataAudit(ctx context.Context, request domain.RequestParamAudit) (response domain.MetaData, err error) {
+	filter, args := filterAudit(request)
+	query := "SELECT COUNT(id) as total FROM audit_log" + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+// GetAllAudit returns the matching logs, newest first
+func (db *mysqlAuditRepository) GetAllAudit(ctx context.Context, request domain.RequestParamAudit) (response []domain.AuditLog, err error) {
+	filter, args := filterAudit(request)
+	query := auditQuery + filter + ` ORDER BY id DESC`
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanAudit(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlAuditRepository) EachAudit(ctx context.Context, request domain.RequestParamAudit, fn func(domain.AuditLog) error) (err error) {
+	filter, args := filterAudit(request)
+	query := auditQuery + filter + ` ORDER BY id`
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanAudit(rows)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"unicode/utf8"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type auditUseCase struct {
+	auditMySQLRepo domain.AuditMySQLRepo
+}
+
+func NewAuditUsecase(AuditMySQLRepo domain.AuditMySQLRepo) domain.AuditUseCase {
+	return &auditUseCase{
+		auditMySQLRepo: AuditMySQLRepo,
+	}
+}
+
+// cut shortens a value to at most limit bytes without splitting a character
+func cut(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
+
+// truncate cuts a payload to the configured size, 0 keeps it whole
+func truncate(value *string) *string {
+	limit := viper.GetInt("audit.max_payload")
+	if value == nil || limit <= 0 {
+		return value
+	}
+	short := cut(*value, limit)
+	return &short
+}
+
+func (adu *auditUseCase) PostAudit(ctx context.Context, request domain.AuditLog) (err error) {
+	request.Payload = truncate(request.Payload)
+	request.Response = truncate(request.Response)
+	request.Before = truncate(request.Before)
+	request.After = truncate(request.After)
+	request.UserAgent = cut(request.UserAgent, 512)
+	request.Path = cut(request.Path, 1024)
+
+	err = adu.auditMySQLRepo.PostAudit(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (adu *auditUseCase) ValidateAudit(request domain.RequestParamAudit) (err error) {
+	if request.Method != nil {
+		switch *request.Method {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("method must be POST, PUT, PATCH or DELETE")}
+		}
+	}
+
+	for _, value := range []*string{request.From, request.To} {
+		if value == nil {
+			continue
+		}
+		if _, err := helper.LocalTime(*value); err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("from and to must be formatted as YYYY-MM-DD HH:MM:SS")}
+		}
+	}
+	return nil
+}
+
+func (adu *auditUseCase) GetAllAudit(ctx context.Context, request domain.RequestParamAudit) (response domain.ResponseGetAllAudit, err error) {
+	err = adu.ValidateAudit(request)
+	if err != nil {
+		return response, err
+	}
+
+	response.MetaData, err = adu.auditMySQLRepo.CountDataAudit(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data, err = adu.auditMySQLRepo.GetAllAudit(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// csvCell keeps spreadsheet apps from reading a value as a formula
+func csvCell(value string) string {
+	if value != "" && (value[0] == '=' || value[0] == '+' || value[0] == '-' || value[0] == '@') {
+		return "'" + value
+	}
+	return value
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// ExportAudit writes the matching logs as CSV, oldest first, the filters are validated beforehand
+func (adu *auditUseCase) ExportAudit(ctx context.Context, request domain.RequestParamAudit, w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{"id", "dtm_crt", "user_id", "role", "ip", "user_agent", "method", "route", "path", "entity_id", "status", "payload", "response", "before", "after"})
+	if err != nil {
+		return err
+	}
+
+	err = adu.auditMySQLRepo.EachAudit(ctx, request, func(audit domain.AuditLog) error {
+		userID := ""
+		if audit.UserID != nil {
+			userID = strconv.Itoa(*audit.UserID)
+		}
+		return writer.Write([]string{
+			strconv.FormatUint(uint64(audit.ID), 10),
+			audit.DtmCrt,
+			userID,
+			csvCell(optional(audit.Role)),
+			csvCell(audit.IP),
+			csvCell(audit.UserAgent),
+			audit.Method,
+			csvCell(audit.Route),
+			csvCell(audit.Path),
+			csvCell(optional(audit.EntityID)),
+			strconv.Itoa(audit.Status),
+			csvCell(optional(audit.Payload)),
+			csvCell(optional(audit.Response)),
+			csvCell(optional(audit.Before)),
+			csvCell(optional(audit.After)),
+		})
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
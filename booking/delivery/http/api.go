@@ -0,0 +1,27 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/booking/delivery/http/handler"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for the user booking and payment webhook REST API
+func RouterAPI(app *fiber.App, BookingUseCase domain.BookingUseCase) {
+	handlerBooking := &handler.BookingHandler{BookingUseCase: BookingUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	booking := app.Group(basePath)
+
+	booking.Post("/payment/:provider/webhook", handlerBooking.PaymentWebhook)
+
+	// Authenticated user API Route
+	booking.Post("/showtime/:id/booking/preview", middleware.Auth, handlerBooking.PreviewBooking)
+	booking.Post("/showtime/:id/booking", middleware.Auth, handlerBooking.PostBooking)
+	booking.Get("/me/booking", middleware.Auth, handlerBooking.GetAllBooking)
+	booking.Get("/booking/:id", middleware.Auth, handlerBooking.GetDetailBooking)
+	booking.Post("/booking/:id/cancel", middleware.Auth, handlerBooking.CancelBooking)
+}
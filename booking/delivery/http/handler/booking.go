@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type BookingHandler struct {
+	BookingUseCase domain.BookingUseCase
+}
+
+func (bh *BookingHandler) PostBooking(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestBooking
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := bh.BookingUseCase.PostBooking(c.Context(), int(id), user, input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(res)
+}
+
+func (bh *BookingHandler) PreviewBooking(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestBooking
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := bh.BookingUseCase.PreviewBooking(c.Context(), int(id), user, input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (bh *BookingHandler) GetAllBooking(c *fiber.Ctx) error {
+	var input domain.RequestParamBooking
+	var err error
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := bh.BookingUseCase.GetAllBooking(c.Context(), user, input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (bh *BookingHandler) GetDetailBooking(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := bh.BookingUseCase.GetDetailBooking(c.Context(), int(id), user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (bh *BookingHandler) CancelBooking(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = bh.BookingUseCase.CancelBooking(c.Context(), int(id), user)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+// PaymentWebhook receives payment notifications, the raw body is kept for the signature check
+func (bh *BookingHandler) PaymentWebhook(c *fiber.Ctx) (err error) {
+	header := func(key string) string {
+		return c.Get(key)
+	}
+
+	err = bh.BookingUseCase.HandlePaymentWebhook(c.Context(), c.Params("provider"), header, c.Body())
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("OK")
+}
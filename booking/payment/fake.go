@@ -0,0 +1,95 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+)
+
+const (
+	FakeProviderName = "fake"
+
+	fakeHeaderSignature = "X-Fake-Signature"
+	fakeHeaderTimestamp = "X-Fake-Timestamp"
+)
+
+// fakeProvider is a payment provider for local testing, payments are never charged and are
+// settled by posting a signed webhook to the service
+type fakeProvider struct {
+	secret    []byte
+	tolerance time.Duration
+}
+
+// NewFakeProvider creates the fake provider, webhooks are signed with the secret and rejected
+// when their timestamp is older than the tolerance or while the secret is empty
+func NewFakeProvider(secret string, tolerance time.Duration) domain.PaymentProvider {
+	return &fakeProvider{secret: []byte(secret), tolerance: tolerance}
+}
+
+func (fp *fakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (fp *fakeProvider) CreatePayment(ctx context.Context, request domain.RequestPayment) (response domain.ResponsePayment, err error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return response, err
+	}
+
+	response.Reference = FakeProviderName + "_" + hex.EncodeToString(buf)
+	return response, nil
+}
+
+func (fp *fakeProvider) Refund(ctx context.Context, reference string, amount int64) (err error) {
+	return nil
+}
+
+// sign returns the signature of a webhook body, the hex HMAC-SHA256 of "timestamp.body"
+func (fp *fakeProvider) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, fp.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (fp *fakeProvider) VerifyWebhook(header func(key string) string, body []byte) (event domain.PaymentEvent, err error) {
+	if len(fp.secret) == 0 {
+		return event, errors.New("payment.fake.secret is not configured")
+	}
+
+	timestamp := header(fakeHeaderTimestamp)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return event, errors.New("invalid timestamp")
+	}
+	if age := time.Since(time.Unix(unix, 0)); fp.tolerance > 0 && (age > fp.tolerance || age < -fp.tolerance) {
+		return event, errors.New("timestamp is outside the tolerance")
+	}
+
+	signature, err := hex.DecodeString(header(fakeHeaderSignature))
+	if err != nil {
+		return event, errors.New("invalid signature")
+	}
+	expected, _ := hex.DecodeString(fp.sign(timestamp, body))
+	if !hmac.Equal(signature, expected) {
+		return event, errors.New("invalid signature")
+	}
+
+	err = json.Unmarshal(body, &event)
+	if err != nil {
+		return event, err
+	}
+	if event.ID == "" || event.Reference == "" {
+		return event, errors.New("id and reference are required")
+	}
+
+	event.Provider = FakeProviderName
+	return event, nil
+}
@@ -0,0 +1,435 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlBookingRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLBookingRepository(Conn *sql.DB) domain.BookingMySQLRepo {
+	return &mysqlBookingRepository{Conn}
+}
+
+const bookingQuery = `SELECT b.id, b.user_id, b.showtime_id, m.title, t.name, s.start_time, b.status, b.discount, b.total_price,
+                  b.payment_provider, b.payment_ref, b.payment_url, b.expires_at, b.dtm_crt, b.dtm_upd
+              FROM booking b
+              JOIN showtime s ON s.id = b.showtime_id
+              JOIN movie m ON m.id = s.movie_id
+              JOIN auditorium a ON a.id = s.auditorium_id
+              JOIN theater t ON t.id = a.theater_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBooking(row rowScanner) (response domain.ResponseBooking, err error) {
+	var startTime, dtmCrt, dtmUpd time.Time
+	var provider, reference, paymentURL sql.NullString
+	var expiresAt sql.NullTime
+	err = row.Scan(
+		&response.ID,
+		&response.UserID,
+		&response.ShowtimeID,
+		&response.MovieTitle,
+		&response.TheaterName,
+		&startTime,
+		&response.Status,
+		&response.Discount,
+		&response.TotalPrice,
+		&provider,
+		&reference,
+		&paymentURL,
+		&expiresAt,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	response.StartTime = startTime.Format(domain.ShowtimeLayout)
+	response.PaymentProvider = provider.String
+	response.PaymentRef = reference.String
+	response.PaymentURL = paymentURL.String
+	if expiresAt.Valid && response.Status == domain.BookingStatusPending {
+		expires := expiresAt.Time.Format("2006-01-02 15:04:05")
+		response.ExpiresAt = &expires
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// PostBooking stores a pending booking with its seats and promos in one transaction, the booking
+// expires after timeout minutes. The unique key on showtime and seat rejects a seat booked twice
+// with "Exists", a promo reaching its usage limit meanwhile is rejected with "Limit"
+func (db *mysqlBookingRepository) PostBooking(ctx context.Context, userID int, showtimeID int, seats []domain.ResponseBookingSeat, promos []domain.ResponsePromoResult, timeout int) (id int, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var subtotal, discount int64
+	for _, seat := range seats {
+		subtotal += seat.Price
+	}
+	for _, promo := range promos {
+		discount += promo.Discount
+	}
+
+	query := `INSERT INTO booking (user_id, showtime_id, status, discount, total_price, expires_at, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, NOW() + INTERVAL ? MINUTE, NOW(), NOW())`
+	res, err := tx.ExecContext(ctx, query, userID, showtimeID, domain.BookingStatusPending, discount, subtotal-discount, timeout)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, seat := range seats {
+		res, err := tx.ExecContext(ctx, `INSERT IGNORE INTO booking_seat (booking_id, showtime_id, seat_id, price) VALUES (?, ?, ?, ?)`, lastID, showtimeID, seat.SeatID, seat.Price)
+		if err != nil {
+			log.Error(err)
+			return 0, err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if affected == 0 {
+			return 0, errors.New("Exists")
+		}
+	}
+
+	for _, promo := range promos {
+		err = usePromo(ctx, tx, int(lastID), userID, promo)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int(lastID), tx.Commit()
+}
+
+// usePromo counts a promo use, the promo row lock serializes concurrent bookings so the global and
+// per user limits hold
+func usePromo(ctx context.Context, tx *sql.Tx, bookingID int, userID int, promo domain.ResponsePromoResult) (err error) {
+	query := `UPDATE promo SET used_count = used_count + 1
+              WHERE id = ? AND active = 1 AND (usage_limit IS NULL OR used_count < usage_limit)`
+	res, err := tx.ExecContext(ctx, query, promo.PromoID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Limit")
+	}
+
+	if promo.PerUserLimit != nil {
+		query := `SELECT COUNT(*) FROM booking_promo bp
+                  JOIN booking b ON b.id = bp.booking_id
+                  WHERE bp.promo_id = ? AND bp.user_id = ? AND b.status IN (?, ?)`
+
+		var used int
+		err = tx.QueryRowContext(ctx, query, promo.PromoID, userID, domain.BookingStatusPending, domain.BookingStatusPaid).Scan(&used)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if used >= *promo.PerUserLimit {
+			return errors.New("Limit")
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO booking_promo (booking_id, promo_id, user_id, discount) VALUES (?, ?, ?, ?)`, bookingID, promo.PromoID, userID, promo.Discount)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (db *mysqlBookingRepository) UpdateBookingPayment(ctx context.Context, id int, provider string, payment domain.ResponsePayment) (err error) {
+	query := `UPDATE booking SET payment_provider = ?, payment_ref = ?, payment_url = ?, dtm_upd = NOW() WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, provider, payment.Reference, payment.PaymentURL, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// insertPaymentEvent records a webhook event once, a delivered event is rejected with "Exists"
+func insertPaymentEvent(ctx context.Context, tx *sql.Tx, bookingID int, event domain.PaymentEvent) (err error) {
+	query := `INSERT IGNORE INTO payment_event (provider, event_id, booking_id, type, dtm_crt) VALUES (?, ?, ?, ?, NOW())`
+
+	res, err := tx.ExecContext(ctx, query, event.Provider, event.ID, bookingID, event.Type)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Exists")
+	}
+	return nil
+}
+
+// UpdateBookingStatus moves the booking from one status to another together with the payment event
+// causing it, "Conflict" is returned when the booking is no longer in the from status.
+// Cancelled and refunded bookings release their seats and give back their promo uses
+func (db *mysqlBookingRepository) UpdateBookingStatus(ctx context.Context, id int, from string, to string, event *domain.PaymentEvent) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if event != nil {
+		err = insertPaymentEvent(ctx, tx, id, *event)
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE booking SET status = ?, dtm_upd = NOW() WHERE id = ? AND status = ?`, to, id, from)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Conflict")
+	}
+
+	if to == domain.BookingStatusCancelled || to == domain.BookingStatusRefunded {
+		_, err = tx.ExecContext(ctx, `UPDATE booking_seat SET active = NULL WHERE booking_id = ?`, id)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+
+		query := `UPDATE promo p JOIN booking_promo bp ON bp.promo_id = p.id
+                  SET p.used_count = GREATEST(p.used_count - 1, 0)
+                  WHERE bp.booking_id = ?`
+		_, err = tx.ExecContext(ctx, query, id)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PostPaymentEvent records a payment event not changing the booking status
+func (db *mysqlBookingRepository) PostPaymentEvent(ctx context.Context, bookingID int, event domain.PaymentEvent) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = insertPaymentEvent(ctx, tx, bookingID, event)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlBookingRepository) CountDataBooking(ctx context.Context, request domain.RequestParamBooking) (response domain.MetaData, err error) {
+	query := `SELECT COUNT(id) as total FROM booking WHERE user_id = ?`
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, request.UserID).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlBookingRepository) GetAllBooking(ctx context.Context, request domain.RequestParamBooking) (response []domain.ResponseBooking, err error) {
+	query := bookingQuery + ` WHERE b.user_id = ? ORDER BY b.dtm_crt DESC, b.id DESC`
+	args := []interface{}{request.UserID}
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanBooking(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlBookingRepository) GetDetailBooking(ctx context.Context, id int) (response domain.ResponseBooking, err error) {
+	response, err = scanBooking(db.Conn.QueryRowContext(ctx, bookingQuery+` WHERE b.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseBooking{}, err
+		}
+		log.Error(err)
+		return domain.ResponseBooking{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlBookingRepository) GetBookingByPayment(ctx context.Context, provider string, reference string) (response domain.ResponseBooking, err error) {
+	response, err = scanBooking(db.Conn.QueryRowContext(ctx, bookingQuery+` WHERE b.payment_provider = ? AND b.payment_ref = ?`, provider, reference))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseBooking{}, err
+		}
+		log.Error(err)
+		return domain.ResponseBooking{}, err
+	}
+
+	return response, nil
+}
+
+// GetExpiredBooking returns the pending bookings not paid before they expire
+func (db *mysqlBookingRepository) GetExpiredBooking(ctx context.Context) (response []int, err error) {
+	rows, err := db.Conn.QueryContext(ctx, `SELECT id FROM booking WHERE status = ? AND expires_at < NOW()`, domain.BookingStatusPending)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, id)
+	}
+
+	return response, nil
+}
+
+// GetBookingSeat returns the seats of every booking keyed by the booking ID
+func (db *mysqlBookingRepository) GetBookingSeat(ctx context.Context, bookingIDs []int) (response map[int][]domain.ResponseBookingSeat, err error) {
+	response = map[int][]domain.ResponseBookingSeat{}
+	if len(bookingIDs) == 0 {
+		return response, nil
+	}
+
+	args := make([]interface{}, len(bookingIDs))
+	for idx, id := range bookingIDs {
+		args[idx] = id
+	}
+
+	query := `SELECT bs.booking_id, s.id, s.row_label, s.number, s.type, bs.price
+              FROM booking_seat bs
+              JOIN seat s ON s.id = bs.seat_id
+              WHERE bs.booking_id IN (?` + strings.Repeat(", ?", len(bookingIDs)-1) + `)
+              ORDER BY LENGTH(s.row_label), s.row_label, s.number`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookingID int
+		var i domain.ResponseBookingSeat
+		if err := rows.Scan(&bookingID, &i.SeatID, &i.Row, &i.Number, &i.Type, &i.Price); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[bookingID] = append(response[bookingID], i)
+	}
+
+	return response, nil
+}
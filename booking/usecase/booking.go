@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type bookingUseCase struct {
+	bookingMySQLRepo  domain.BookingMySQLRepo
+	seatMySQLRepo     domain.SeatMySQLRepo
+	seatRedisRepo     domain.SeatRedisRepo
+	showtimeMySQLRepo domain.ShowtimeMySQLRepo
+	promoUseCase      domain.PromoUseCase
+	paymentProviders  map[string]domain.PaymentProvider
+}
+
+func NewBookingUsecase(BookingMySQLRepo domain.BookingMySQLRepo, SeatMySQLRepo domain.SeatMySQLRepo, SeatRedisRepo domain.SeatRedisRepo, ShowtimeMySQLRepo domain.ShowtimeMySQLRepo, PromoUseCase domain.PromoUseCase, PaymentProviders ...domain.PaymentProvider) domain.BookingUseCase {
+	providers := map[string]domain.PaymentProvider{}
+	for _, provider := range PaymentProviders {
+		providers[provider.Name()] = provider
+	}
+
+	return &bookingUseCase{
+		bookingMySQLRepo:  BookingMySQLRepo,
+		seatMySQLRepo:     SeatMySQLRepo,
+		seatRedisRepo:     SeatRedisRepo,
+		showtimeMySQLRepo: ShowtimeMySQLRepo,
+		promoUseCase:      PromoUseCase,
+		paymentProviders:  providers,
+	}
+}
+
+func canTransition(from string, to string) bool {
+	for _, status := range domain.BookingTransition[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// quote prices the requested seats of an open showtime by the showtime base price, the seat class
+// and the day, then applies the promo codes
+func (bku *bookingUseCase) quote(ctx context.Context, showtimeID int, user domain.AuthUser, request domain.RequestBooking) (response domain.ResponseBookingPreview, showtime domain.ResponseShowtime, err error) {
+	if len(request.SeatIDs) == 0 {
+		return response, showtime, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("seat_ids is required")}
+	}
+
+	seen := map[int]bool{}
+	for _, seatID := range request.SeatIDs {
+		if seen[seatID] {
+			return response, showtime, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("seat_ids must be unique")}
+		}
+		seen[seatID] = true
+	}
+
+	_, open, err := bku.seatMySQLRepo.GetShowtimeAuditorium(ctx, showtimeID)
+	if err != nil {
+		return response, showtime, err
+	}
+	if !open {
+		return response, showtime, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("showtime already started")}
+	}
+
+	showtime, err = bku.showtimeMySQLRepo.GetDetailShowtime(ctx, showtimeID)
+	if err != nil {
+		return response, showtime, err
+	}
+	startTime, err := time.Parse(domain.ShowtimeLayout, showtime.StartTime)
+	if err != nil {
+		return response, showtime, err
+	}
+
+	layout, err := bku.seatMySQLRepo.GetSeatByAuditorium(ctx, int(showtime.AuditoriumID))
+	if err != nil {
+		return response, showtime, err
+	}
+	seatByID := map[int]domain.ResponseSeat{}
+	for _, seat := range layout {
+		seatByID[int(seat.ID)] = seat
+	}
+
+	response.Seats = make([]domain.ResponseBookingSeat, len(request.SeatIDs))
+	for idx, seatID := range request.SeatIDs {
+		seat, ok := seatByID[seatID]
+		if !ok {
+			return response, showtime, constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: fmt.Errorf("seat %d is not exists in this auditorium", seatID)}
+		}
+		response.Seats[idx] = domain.ResponseBookingSeat{
+			SeatID: seat.ID,
+			Row:    seat.Row,
+			Number: seat.Number,
+			Type:   seat.Type,
+			Price:  helper.SeatPrice(showtime.BasePrice, seat.Type, startTime),
+		}
+		response.Subtotal += response.Seats[idx].Price
+	}
+
+	promo, err := bku.promoUseCase.ApplyPromo(ctx, domain.RequestApplyPromo{
+		UserID:    user.ID,
+		MovieID:   int(showtime.MovieID),
+		TheaterID: int(showtime.TheaterID),
+		Amount:    response.Subtotal,
+		Codes:     request.PromoCodes,
+	})
+	if err != nil {
+		return response, showtime, err
+	}
+
+	response.Promos = promo.Promos
+	response.Discount = promo.Discount
+	response.Total = response.Subtotal - response.Discount
+	return response, showtime, nil
+}
+
+// PreviewBooking returns the price of the seats with the promo codes applied, nothing is reserved
+func (bku *bookingUseCase) PreviewBooking(ctx context.Context, showtimeID int, user domain.AuthUser, request domain.RequestBooking) (response domain.ResponseBookingPreview, err error) {
+	response, _, err = bku.quote(ctx, showtimeID, user, request)
+	return response, err
+}
+
+// PostBooking turns seats the user holds into a pending booking at the quoted price, then opens a
+// payment with the configured provider. Free bookings are paid at once
+func (bku *bookingUseCase) PostBooking(ctx context.Context, showtimeID int, user domain.AuthUser, request domain.RequestBooking) (response domain.ResponseBooking, err error) {
+	provider, ok := bku.paymentProviders[viper.GetString("payment.provider")]
+	if !ok {
+		return response, fmt.Errorf("payment provider %s is not configured", viper.GetString("payment.provider"))
+	}
+
+	quote, showtime, err := bku.quote(ctx, showtimeID, user, request)
+	if err != nil {
+		return response, err
+	}
+	for _, promo := range quote.Promos {
+		if !promo.Applied {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("%s: %s", promo.Code, promo.Reason)}
+		}
+	}
+
+	owner := strconv.Itoa(user.ID)
+	missing, err := bku.seatRedisRepo.CheckHold(ctx, showtimeID, owner, request.SeatIDs)
+	if err != nil {
+		return response, err
+	}
+	if missing != 0 {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("seat %d is not held by you", missing)}
+	}
+
+	id, err := bku.bookingMySQLRepo.PostBooking(ctx, user.ID, showtimeID, quote.Seats, quote.Promos, viper.GetInt("payment.timeout"))
+	if err != nil {
+		log.Error(err)
+		switch err.Error() {
+		case "Exists":
+			return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("seat already booked")}
+		case "Limit":
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("promo usage limit reached")}
+		}
+		return response, err
+	}
+
+	// The booking now reserves the seats, a failed release only lets the holds run out their TTL
+	if err := bku.seatRedisRepo.ReleaseSeat(ctx, showtimeID, owner, request.SeatIDs); err != nil {
+		log.Warn(err)
+	}
+
+	if quote.Total == 0 {
+		err = bku.bookingMySQLRepo.UpdateBookingStatus(ctx, id, domain.BookingStatusPending, domain.BookingStatusPaid, nil)
+		if err != nil {
+			return response, err
+		}
+		return bku.GetDetailBooking(ctx, id, user)
+	}
+
+	payment, err := provider.CreatePayment(ctx, domain.RequestPayment{
+		BookingID:   id,
+		Amount:      quote.Total,
+		Description: showtime.MovieTitle + " " + showtime.StartTime,
+	})
+	if err != nil {
+		log.Error(err)
+		if err := bku.bookingMySQLRepo.UpdateBookingStatus(ctx, id, domain.BookingStatusPending, domain.BookingStatusCancelled, nil); err != nil {
+			log.Error(err)
+		}
+		return response, err
+	}
+
+	err = bku.bookingMySQLRepo.UpdateBookingPayment(ctx, id, provider.Name(), payment)
+	if err != nil {
+		return response, err
+	}
+
+	return bku.GetDetailBooking(ctx, id, user)
+}
+
+func (bku *bookingUseCase) GetAllBooking(ctx context.Context, user domain.AuthUser, request domain.RequestParamBooking) (response domain.ResponseGetAllBooking, err error) {
+	request.UserID = user.ID
+
+	resCount, err := bku.bookingMySQLRepo.CountDataBooking(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllBooking{}, err
+	}
+
+	resBooking, err := bku.bookingMySQLRepo.GetAllBooking(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	bookingIDs := make([]int, len(resBooking))
+	for idx, booking := range resBooking {
+		bookingIDs[idx] = int(booking.ID)
+	}
+	seats, err := bku.bookingMySQLRepo.GetBookingSeat(ctx, bookingIDs)
+	if err != nil {
+		return response, err
+	}
+	for idx := range resBooking {
+		resBooking[idx].Seats = seats[int(resBooking[idx].ID)]
+	}
+
+	response = domain.ResponseGetAllBooking{
+		MetaData: resCount,
+		Data:     resBooking,
+	}
+	return
+}
+
+// GetDetailBooking returns a booking of the user, admins can read any booking
+func (bku *bookingUseCase) GetDetailBooking(ctx context.Context, id int, user domain.AuthUser) (response domain.ResponseBooking, err error) {
+	response, err = bku.bookingMySQLRepo.GetDetailBooking(ctx, id)
+	if err != nil {
+		return response, err
+	}
+	if int(response.UserID) != user.ID && user.Role != domain.RoleAdmin {
+		return domain.ResponseBooking{}, errors.New("Not found")
+	}
+
+	seats, err := bku.bookingMySQLRepo.GetBookingSeat(ctx, []int{id})
+	if err != nil {
+		return response, err
+	}
+	response.Seats = seats[id]
+	return response, nil
+}
+
+// CancelBooking cancels a pending booking, a paid booking is refunded when the showtime has not started
+func (bku *bookingUseCase) CancelBooking(ctx context.Context, id int, user domain.AuthUser) (err error) {
+	booking, err := bku.GetDetailBooking(ctx, id, user)
+	if err != nil {
+		return err
+	}
+
+	to := domain.BookingStatusCancelled
+	if booking.Status == domain.BookingStatusPaid {
+		to = domain.BookingStatusRefunded
+	}
+	if !canTransition(booking.Status, to) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("booking is already %s", booking.Status)}
+	}
+
+	if to == domain.BookingStatusRefunded {
+		_, open, err := bku.seatMySQLRepo.GetShowtimeAuditorium(ctx, int(booking.ShowtimeID))
+		if err != nil {
+			return err
+		}
+		if !open {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("showtime already started")}
+		}
+
+		if provider, ok := bku.paymentProviders[booking.PaymentProvider]; ok && booking.TotalPrice > 0 {
+			err = provider.Refund(ctx, booking.PaymentRef, booking.TotalPrice)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+	}
+
+	err = bku.bookingMySQLRepo.UpdateBookingStatus(ctx, id, booking.Status, to, nil)
+	if err != nil {
+		if err.Error() == "Conflict" {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("booking status has changed, please try again")}
+		}
+		return err
+	}
+	return nil
+}
+
+// HandlePaymentWebhook verifies a provider notification and applies it to the booking. Events are
+// recorded once, so a webhook delivered again or out of order is acknowledged without effect
+func (bku *bookingUseCase) HandlePaymentWebhook(ctx context.Context, providerName string, header func(key string) string, body []byte) (err error) {
+	provider, ok := bku.paymentProviders[providerName]
+	if !ok {
+		return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("payment provider is not exists")}
+	}
+
+	event, err := provider.VerifyWebhook(header, body)
+	if err != nil {
+		return constant.ResultError{Code: constant.StatusUnauthorizedInvalidSignature, Err: err}
+	}
+
+	booking, err := bku.bookingMySQLRepo.GetBookingByPayment(ctx, provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+
+	var from, to string
+	switch event.Type {
+	case domain.PaymentEventPaid:
+		if event.Amount != booking.TotalPrice {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("amount does not match the booking")}
+		}
+
+		// The payment arrived after the booking expired and its seats were released. The event is recorded
+		// first, so a redelivered webhook never refunds twice
+		if booking.Status == domain.BookingStatusCancelled {
+			err = bku.bookingMySQLRepo.PostPaymentEvent(ctx, int(booking.ID), event)
+			if err != nil {
+				if err.Error() == "Exists" {
+					return nil
+				}
+				return err
+			}
+
+			log.Warnf("booking %d paid after it was cancelled, refunding", booking.ID)
+			err = provider.Refund(ctx, event.Reference, event.Amount)
+			if err != nil {
+				log.Errorf("refund of booking %d failed, refund payment %s by hand: %v", booking.ID, event.Reference, err)
+				return err
+			}
+			return nil
+		}
+		from, to = domain.BookingStatusPending, domain.BookingStatusPaid
+	case domain.PaymentEventFailed:
+		from, to = domain.BookingStatusPending, domain.BookingStatusCancelled
+	case domain.PaymentEventRefunded:
+		from, to = domain.BookingStatusPaid, domain.BookingStatusRefunded
+	default:
+		log.Infof("ignoring payment event %s of type %s", event.ID, event.Type)
+		return nil
+	}
+
+	if booking.Status != from {
+		log.Warnf("ignoring payment event %s, booking %d is %s", event.ID, booking.ID, booking.Status)
+		return nil
+	}
+
+	err = bku.bookingMySQLRepo.UpdateBookingStatus(ctx, int(booking.ID), from, to, &event)
+	if err != nil {
+		if err.Error() == "Exists" || err.Error() == "Conflict" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ExpireBooking cancels pending bookings not paid within payment.timeout minutes and frees their seats
+func (bku *bookingUseCase) ExpireBooking(ctx context.Context) (err error) {
+	bookingIDs, err := bku.bookingMySQLRepo.GetExpiredBooking(ctx)
+	if err != nil {
+		return err
+	}
+
+	var total int
+	for _, id := range bookingIDs {
+		err = bku.bookingMySQLRepo.UpdateBookingStatus(ctx, id, domain.BookingStatusPending, domain.BookingStatusCancelled, nil)
+		if err != nil {
+			if err.Error() == "Conflict" {
+				continue
+			}
+			return err
+		}
+		total++
+	}
+
+	if total > 0 {
+		log.Infof("expired %d bookings", total)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"xsis-academy-test-service-movie/booking/usecase"
+	"xsis-academy-test-service-movie/domain"
+)
+
+// paymentProvider verifies every webhook as the event and counts the refunds
+type paymentProvider struct {
+	domain.PaymentProvider
+	event   domain.PaymentEvent
+	refunds int
+}
+
+func (provider *paymentProvider) Name() string {
+	return "fake"
+}
+
+func (provider *paymentProvider) VerifyWebhook(header func(key string) string, body []byte) (domain.PaymentEvent, error) {
+	return provider.event, nil
+}
+
+func (provider *paymentProvider) Refund(ctx context.Context, reference string, amount int64) error {
+	provider.refunds++
+	return nil
+}
+
+// bookingRepo records each payment event once like the unique key of payment_event
+type bookingRepo struct {
+	domain.BookingMySQLRepo
+	booking domain.ResponseBooking
+	events  map[string]bool
+}
+
+func (repo *bookingRepo) GetBookingByPayment(ctx context.Context, provider string, reference string) (domain.ResponseBooking, error) {
+	return repo.booking, nil
+}
+
+func (repo *bookingRepo) PostPaymentEvent(ctx context.Context, bookingID int, event domain.PaymentEvent) error {
+	if repo.events[event.ID] {
+		return errors.New("Exists")
+	}
+	repo.events[event.ID] = true
+	return nil
+}
+
+func (repo *bookingRepo) UpdateBookingStatus(ctx context.Context, id int, from string, to string, event *domain.PaymentEvent) error {
+	if repo.booking.Status != from {
+		return errors.New("Conflict")
+	}
+	if event != nil {
+		if err := repo.PostPaymentEvent(ctx, id, *event); err != nil {
+			return err
+		}
+	}
+	repo.booking.Status = to
+	return nil
+}
+
+func header(key string) string {
+	return ""
+}
+
+func TestHandlePaymentWebhookRedeliveredAfterCancel(t *testing.T) {
+	repo := &bookingRepo{booking: domain.ResponseBooking{ID: 4, Status: domain.BookingStatusCancelled, TotalPrice: 90000}, events: map[string]bool{}}
+	provider := &paymentProvider{event: domain.PaymentEvent{ID: "evt_1", Type: domain.PaymentEventPaid, Reference: "fake_1", Amount: 90000}}
+	bookingUseCase := usecase.NewBookingUsecase(repo, nil, nil, nil, nil, provider)
+
+	// The provider delivers the same paid event three times for an expired booking
+	for delivery := 0; delivery < 3; delivery++ {
+		if err := bookingUseCase.HandlePaymentWebhook(context.Background(), "fake", header, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.refunds != 1 || repo.booking.Status != domain.BookingStatusCancelled {
+		t.Fatalf("got %d refunds and status %s, want one refund of the cancelled booking", provider.refunds, repo.booking.Status)
+	}
+}
+
+func TestHandlePaymentWebhookRedeliveredPaid(t *testing.T) {
+	repo := &bookingRepo{booking: domain.ResponseBooking{ID: 4, Status: domain.BookingStatusPending, TotalPrice: 90000}, events: map[string]bool{}}
+	provider := &paymentProvider{event: domain.PaymentEvent{ID: "evt_1", Type: domain.PaymentEventPaid, Reference: "fake_1", Amount: 90000}}
+	bookingUseCase := usecase.NewBookingUsecase(repo, nil, nil, nil, nil, provider)
+
+	for delivery := 0; delivery < 2; delivery++ {
+		if err := bookingUseCase.HandlePaymentWebhook(context.Background(), "fake", header, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.refunds != 0 || repo.booking.Status != domain.BookingStatusPaid || len(repo.events) != 1 {
+		t.Fatalf("got %d refunds, status %s and %d events, want the booking paid once", provider.refunds, repo.booking.Status, len(repo.events))
+	}
+}
+
+func TestHandlePaymentWebhookAmountMismatch(t *testing.T) {
+	repo := &bookingRepo{booking: domain.ResponseBooking{ID: 4, Status: domain.BookingStatusCancelled, TotalPrice: 90000}, events: map[string]bool{}}
+	provider := &paymentProvider{event: domain.PaymentEvent{ID: "evt_1", Type: domain.PaymentEventPaid, Reference: "fake_1", Amount: 1000}}
+	bookingUseCase := usecase.NewBookingUsecase(repo, nil, nil, nil, nil, provider)
+
+	err := bookingUseCase.HandlePaymentWebhook(context.Background(), "fake", header, nil)
+	if err == nil || provider.refunds != 0 || len(repo.events) != 0 {
+		t.Fatalf("got %v with %d refunds, want the event rejected", err, provider.refunds)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/calendar/delivery/http/handler"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for the iCalendar feeds, feeds are public so calendar apps can subscribe
+func RouterAPI(app *fiber.App, CalendarUseCase domain.CalendarUseCase) {
+	handlerCalendar := &handler.CalendarHandler{CalendarUseCase: CalendarUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	// The feeds live under /calendar, /movie/:id and /theater/:id would match /movie/5.ics first
+	calendar := app.Group(basePath + "/calendar")
+
+	calendar.Get("/release.ics", handlerCalendar.GetReleaseCalendar)
+	calendar.Get("/movie/:id.ics", handlerCalendar.GetMovieCalendar)
+	calendar.Get("/theater/:id.ics", handlerCalendar.GetTheaterCalendar)
+}
@@ -0,0 +1,88 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	_DeliveryHTTPCalendar "xsis-academy-test-service-movie/calendar/delivery/http"
+	"xsis-academy-test-service-movie/domain"
+	_DeliveryHTTP "xsis-academy-test-service-movie/movie/delivery/http"
+	_DeliveryHTTPShowtime "xsis-academy-test-service-movie/showtime/delivery/http"
+	_DeliveryHTTPTheater "xsis-academy-test-service-movie/theater/delivery/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The routers of main only implement what a route reaching them would call, a route landing on the wrong
+// router panics on the nil interface and fails the test
+type movieUseCase struct{ domain.MovieUseCase }
+type theaterUseCase struct{ domain.TheaterUseCase }
+type showtimeUseCase struct{ domain.ShowtimeUseCase }
+
+type calendarUseCase struct {
+	movieID   int
+	theaterID int
+}
+
+func (cu *calendarUseCase) GetMovieCalendar(ctx context.Context, movieID int) (domain.Calendar, error) {
+	cu.movieID = movieID
+	return domain.Calendar{Name: "Movie showtimes"}, nil
+}
+
+func (cu *calendarUseCase) GetTheaterCalendar(ctx context.Context, theaterID int) (domain.Calendar, error) {
+	cu.theaterID = theaterID
+	return domain.Calendar{Name: "Theater schedule"}, nil
+}
+
+func (cu *calendarUseCase) GetReleaseCalendar(ctx context.Context) (domain.Calendar, error) {
+	return domain.Calendar{Name: "Movie releases"}, nil
+}
+
+// newApp registers the routers in the order of main, the detail routes before the feeds
+func newApp(calendar *calendarUseCase) *fiber.App {
+	app := fiber.New()
+	_DeliveryHTTP.RouterAPI(app, &movieUseCase{})
+	_DeliveryHTTPTheater.RouterAPI(app, &theaterUseCase{})
+	_DeliveryHTTPShowtime.RouterAPI(app, &showtimeUseCase{})
+	_DeliveryHTTPCalendar.RouterAPI(app, calendar)
+	return app
+}
+
+func TestCalendarRoutes(t *testing.T) {
+	tests := []struct {
+		path      string
+		movieID   int
+		theaterID int
+		name      string
+	}{
+		{path: "/calendar/movie/5.ics", movieID: 5, name: "Movie showtimes"},
+		{path: "/calendar/theater/7.ics", theaterID: 7, name: "Theater schedule"},
+		{path: "/calendar/release.ics", name: "Movie releases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			calendar := &calendarUseCase{}
+			res, err := newApp(calendar).Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want %d", res.StatusCode, fiber.StatusOK)
+			}
+			if contentType := res.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(contentType, "text/calendar") {
+				t.Errorf("content type = %q, want text/calendar", contentType)
+			}
+			body, _ := io.ReadAll(res.Body)
+			if !strings.Contains(string(body), "X-WR-CALNAME:"+tt.name) {
+				t.Errorf("feed %q is not the %s feed", body, tt.name)
+			}
+			if calendar.movieID != tt.movieID || calendar.theaterID != tt.theaterID {
+				t.Errorf("feed got movie %d theater %d, want movie %d theater %d", calendar.movieID, calendar.theaterID, tt.movieID, tt.theaterID)
+			}
+		})
+	}
+}
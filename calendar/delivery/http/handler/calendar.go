@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type CalendarHandler struct {
+	CalendarUseCase domain.CalendarUseCase
+}
+
+func sendCalendar(c *fiber.Ctx, calendar domain.Calendar, filename string) error {
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="`+filename+`"`)
+	return c.Status(fasthttp.StatusOK).Send(helper.ICalendar(calendar))
+}
+
+func (ch *CalendarHandler) GetMovieCalendar(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := ch.CalendarUseCase.GetMovieCalendar(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return sendCalendar(c, res, "movie-"+c.Params("id")+".ics")
+}
+
+func (ch *CalendarHandler) GetTheaterCalendar(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := ch.CalendarUseCase.GetTheaterCalendar(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return sendCalendar(c, res, "theater-"+c.Params("id")+".ics")
+}
+
+func (ch *CalendarHandler) GetReleaseCalendar(c *fiber.Ctx) (err error) {
+	res, err := ch.CalendarUseCase.GetReleaseCalendar(c.Context())
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return sendCalendar(c, res, "release.ics")
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/spf13/viper"
+)
+
+type calendarUseCase struct {
+	movieMySQLRepo    domain.MovieMySQLRepo
+	theaterMySQLRepo  domain.TheaterMySQLRepo
+	showtimeMySQLRepo domain.ShowtimeMySQLRepo
+}
+
+func NewCalendarUsecase(MovieMySQLRepo domain.MovieMySQLRepo, TheaterMySQLRepo domain.TheaterMySQLRepo, ShowtimeMySQLRepo domain.ShowtimeMySQLRepo) domain.CalendarUseCase {
+	return &calendarUseCase{
+		movieMySQLRepo:    MovieMySQLRepo,
+		theaterMySQLRepo:  TheaterMySQLRepo,
+		showtimeMySQLRepo: ShowtimeMySQLRepo,
+	}
+}
+
+// eventUID is the stable UID of a calendar event, the same showtime has the same UID in every feed
+func eventUID(kind string, id uint) string {
+	return fmt.Sprintf("%s-%d@%s", kind, id, viper.GetString("calendar.uid_domain"))
+}
+
+// window returns the range of days a feed covers, from calendar.past_days ago to calendar.future_days ahead
+func window() (from time.Time, to time.Time, err error) {
+	location, err := time.LoadLocation(constant.TimeLocation)
+	if err != nil {
+		return from, to, err
+	}
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	from = today.AddDate(0, 0, -viper.GetInt("calendar.past_days"))
+	to = today.AddDate(0, 0, viper.GetInt("calendar.future_days")+1)
+	return from, to, nil
+}
+
+func theaterLocation(theater domain.ResponseTheater) string {
+	parts := []string{theater.Name}
+	if theater.Address != "" {
+		parts = append(parts, theater.Address)
+	}
+	parts = append(parts, theater.City)
+	return strings.Join(parts, ", ")
+}
+
+// showtimeCalendar lists the showtimes of the feed window as events located at their theater
+func (clu *calendarUseCase) showtimeCalendar(ctx context.Context, request domain.RequestParamShowtime) (response []domain.CalendarEvent, err error) {
+	from, to, err := window()
+	if err != nil {
+		return nil, err
+	}
+	fromText := from.Format(domain.ShowtimeLayout)
+	toText := to.Format(domain.ShowtimeLayout)
+	request.From = &fromText
+	request.To = &toText
+	// Feeds are public, showtimes of unpublished movies are left out
+	request.Published = true
+
+	showtimes, err := clu.showtimeMySQLRepo.GetAllShowtime(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	theaters := map[uint]domain.ResponseTheater{}
+	response = []domain.CalendarEvent{}
+	for _, showtime := range showtimes {
+		theater, ok := theaters[showtime.TheaterID]
+		if !ok {
+			theater, err = clu.theaterMySQLRepo.GetDetailTheater(ctx, int(showtime.TheaterID))
+			if err != nil {
+				return nil, err
+			}
+			theaters[showtime.TheaterID] = theater
+		}
+
+		start, err := helper.LocalTime(showtime.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := helper.LocalTime(showtime.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		modified, err := helper.LocalTime(showtime.DtmUpd)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, domain.CalendarEvent{
+			UID:         eventUID("showtime", showtime.ID),
+			Summary:     showtime.MovieTitle,
+			Description: fmt.Sprintf("%s, %s", showtime.TheaterName, showtime.AuditoriumName),
+			Location:    theaterLocation(theater),
+			Start:       start,
+			End:         end,
+			Modified:    modified,
+		})
+	}
+	return response, nil
+}
+
+// GetMovieCalendar returns the showtimes of the movie in every theater
+func (clu *calendarUseCase) GetMovieCalendar(ctx context.Context, movieID int) (response domain.Calendar, err error) {
+	movie, err := clu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		return response, err
+	}
+	if movie.Status != domain.MovieStatusPublished {
+		return response, errors.New("Not found")
+	}
+
+	response.Name = movie.Title + " showtimes"
+	response.Events, err = clu.showtimeCalendar(ctx, domain.RequestParamShowtime{MovieID: &movieID})
+	return response, err
+}
+
+// GetTheaterCalendar returns the schedule of every auditorium of the theater
+func (clu *calendarUseCase) GetTheaterCalendar(ctx context.Context, theaterID int) (response domain.Calendar, err error) {
+	theater, err := clu.theaterMySQLRepo.GetDetailTheater(ctx, theaterID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Name = theater.Name + " schedule"
+	response.Events, err = clu.showtimeCalendar(ctx, domain.RequestParamShowtime{TheaterID: &theaterID})
+	return response, err
+}
+
+// GetReleaseCalendar returns the release dates of the feed window as all-day events
+func (clu *calendarUseCase) GetReleaseCalendar(ctx context.Context) (response domain.Calendar, err error) {
+	from, to, err := window()
+	if err != nil {
+		return response, err
+	}
+
+	movies, err := clu.movieMySQLRepo.GetMovieByRelease(ctx, from.Format(domain.ReleaseDateLayout), to.Format(domain.ReleaseDateLayout))
+	if err != nil {
+		return response, err
+	}
+
+	response.Name = "Movie releases"
+	response.Events = []domain.CalendarEvent{}
+	for _, movie := range movies {
+		release, err := time.Parse(domain.ReleaseDateLayout, *movie.ReleaseDate)
+		if err != nil {
+			return response, err
+		}
+		modified, err := helper.LocalTime(movie.DtmUpd)
+		if err != nil {
+			return response, err
+		}
+
+		response.Events = append(response.Events, domain.CalendarEvent{
+			UID:         eventUID("release", movie.ID),
+			Summary:     movie.Title,
+			Description: movie.Description,
+			Start:       release,
+			End:         release.AddDate(0, 0, 1),
+			AllDay:      true,
+			Modified:    modified,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/collection/delivery/http/handler"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for collection REST API
+func RouterAPI(app *fiber.App, CollectionUseCase domain.CollectionUseCase) {
+	handlerCollection := &handler.CollectionHandler{CollectionUseCase: CollectionUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	collection := app.Group(basePath)
+	auditCollection := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		return CollectionUseCase.GetDetailCollection(c.Context(), id)
+	})
+
+	collection.Get("/collection", handlerCollection.GetAllCollection)
+	collection.Post("/collection", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditCollection, handlerCollection.PostCollection)
+	collection.Get("/collection/:id", handlerCollection.GetDetailCollection)
+	collection.Patch("/collection/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditCollection, handlerCollection.UpdateCollection)
+	collection.Delete("/collection/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditCollection, handlerCollection.DeleteCollection)
+	collection.Post("/collection/:id/movie", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditCollection, handlerCollection.AddCollectionMovie)
+	collection.Delete("/collection/:id/movie/:movie_id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditCollection, handlerCollection.DeleteCollectionMovie)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type CollectionHandler struct {
+	CollectionUseCase domain.CollectionUseCase
+}
+
+func (ch *CollectionHandler) GetAllCollection(c *fiber.Ctx) error {
+	var input domain.RequestParamCollection
+	var err error
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	collectionType := c.Query("type")
+	if collectionType != "" {
+		input.Type = &collectionType
+	}
+
+	res, err := ch.CollectionUseCase.GetAllCollection(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (ch *CollectionHandler) PostCollection(c *fiber.Ctx) (err error) {
+	var input domain.RequestCollection
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	id, err := ch.CollectionUseCase.PostCollection(c.Context(), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+func (ch *CollectionHandler) GetDetailCollection(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := ch.CollectionUseCase.GetDetailCollection(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (ch *CollectionHandler) UpdateCollection(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestCollection
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ch.CollectionUseCase.UpdateCollection(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (ch *CollectionHandler) DeleteCollection(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ch.CollectionUseCase.DeleteCollection(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
+
+func (ch *CollectionHandler) AddCollectionMovie(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestCollectionMovie
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ch.CollectionUseCase.AddCollectionMovie(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (ch *CollectionHandler) DeleteCollectionMovie(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	movieID, err := strconv.ParseInt(c.Params("movie_id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ch.CollectionUseCase.DeleteCollectionMovie(c.Context(), int(id), int(movieID))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
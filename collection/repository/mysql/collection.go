@@ -0,0 +1,350 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlCollectionRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLCollectionRepository(Conn *sql.DB) domain.CollectionMySQLRepo {
+	return &mysqlCollectionRepository{Conn}
+}
+
+const collectionMovieInsert = `INSERT INTO collection_movie (collection_id, movie_id, position) VALUES (?, ?, ?)`
+
+// PostCollection creates the collection with its movies in one transaction
+func (db *mysqlCollectionRepository) PostCollection(ctx context.Context, request domain.RequestCollection) (id int, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO collection (name, type, description, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, NOW(), NOW())`
+
+	res, err := tx.ExecContext(ctx, query, request.Name, request.Type, request.Description)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertCollectionMovie(ctx, tx, int(lastID), request.MovieIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), tx.Commit()
+}
+
+// insertCollectionMovie adds the movies to an empty collection, the order of movieIDs becomes the position
+func insertCollectionMovie(ctx context.Context, tx *sql.Tx, id int, movieIDs []int) (err error) {
+	for idx, movieID := range movieIDs {
+		_, err = tx.ExecContext(ctx, collectionMovieInsert, id, movieID, idx+1)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *mysqlCollectionRepository) CountDataCollection(ctx context.Context, request domain.RequestParamCollection) (response domain.MetaData, err error) {
+	query := "SELECT COUNT(id) as total FROM collection WHERE 1=1"
+	var args []interface{}
+
+	if request.Type != nil {
+		query += " AND type = ?"
+		args = append(args, *request.Type)
+	}
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlCollectionRepository) GetAllCollection(ctx context.Context, request domain.RequestParamCollection) (response []domain.ResponseCollection, err error) {
+	query := `SELECT id, name, type, description, dtm_crt, dtm_upd FROM collection WHERE 1=1`
+	var limit, page int
+	var args []interface{}
+
+	if request.Type != nil {
+		query += " AND type = ?"
+		args = append(args, *request.Type)
+	}
+
+	query += " ORDER BY id"
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseCollection
+		var dtmCrt, dtmUpd time.Time
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.Description,
+			&dtmCrt,
+			&dtmUpd,
+		); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+
+		i.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+		i.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlCollectionRepository) GetDetailCollection(ctx context.Context, id int) (response domain.ResponseCollection, err error) {
+	query := `SELECT id, name, type, description, dtm_crt, dtm_upd FROM collection WHERE id = ?`
+
+	row := db.Conn.QueryRowContext(ctx, query, id)
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.Name,
+		&response.Type,
+		&response.Description,
+		&dtmCrt,
+		&dtmUpd,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseCollection{}, err
+		}
+		log.Error(err)
+		return domain.ResponseCollection{}, err
+	}
+
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+
+	return response, nil
+}
+
+// UpdateCollection updates the collection and, when movie_ids is sent, replaces its movies in the same
+// transaction, the order of movie_ids becomes the position
+func (db *mysqlCollectionRepository) UpdateCollection(ctx context.Context, id int, request domain.RequestCollection) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE collection
+              SET name = ?, type = ?, description = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = tx.ExecContext(ctx, query, request.Name, request.Type, request.Description, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if request.MovieIDs != nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM collection_movie WHERE collection_id = ?`, id)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+
+		err = insertCollectionMovie(ctx, tx, id, request.MovieIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlCollectionRepository) DeleteCollection(ctx context.Context, id int) (err error) {
+	query := `DELETE FROM collection WHERE id = ?`
+	_, err = db.Conn.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+// GetCollectionMovie returns the movies of the collection by position, only the published ones when published is set
+func (db *mysqlCollectionRepository) GetCollectionMovie(ctx context.Context, id int, published bool) (response []domain.ResponseCollectionMovie, err error) {
+	query := `SELECT m.id, m.title, m.rating, m.image, cm.position
+              FROM collection_movie cm
+              JOIN movie m ON m.id = cm.movie_id
+              WHERE cm.collection_id = ?`
+	args := []interface{}{id}
+
+	if published {
+		query += " AND m.status = ?"
+		args = append(args, domain.MovieStatusPublished)
+	}
+	query += " ORDER BY cm.position, m.id"
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseCollectionMovie
+		if err := rows.Scan(&i.ID, &i.Title, &i.Rating, &i.Image, &i.Position); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+// AddCollectionMovie adds or moves a movie to the given position, the movies are renumbered without gaps
+func (db *mysqlCollectionRepository) AddCollectionMovie(ctx context.Context, id int, movieID int, position int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = helper.PlaceMovie(ctx, tx, "collection_movie", "collection_id", id, movieID, position, collectionMovieInsert)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteCollectionMovie removes a movie, the movies after it move up
+func (db *mysqlCollectionRepository) DeleteCollectionMovie(ctx context.Context, id int, movieID int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM collection_movie WHERE collection_id = ? AND movie_id = ?`, id, movieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Not found")
+	}
+
+	err = helper.CompactMovie(ctx, tx, "collection_movie", "collection_id", id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlCollectionRepository) GetCollectionByMovie(ctx context.Context, movieID int) (response []domain.ResponseMovieCollection, err error) {
+	query := `SELECT c.id, c.name, c.type, cm.position
+              FROM collection_movie cm
+              JOIN collection c ON c.id = cm.collection_id
+              WHERE cm.movie_id = ?
+              ORDER BY c.id`
+
+	rows, err := db.Conn.QueryContext(ctx, query, movieID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseMovieCollection
+		if err := rows.Scan(&i.ID, &i.Name, &i.Type, &i.Position); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
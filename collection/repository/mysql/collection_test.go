@@ -0,0 +1,41 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/collection/repository/mysql"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper/sqltest"
+)
+
+func TestPostCollectionWithMovies(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	repo := mysql.NewMySQLCollectionRepository(sqltest.Open(recorder))
+
+	id, err := repo.PostCollection(context.Background(), domain.RequestCollection{Name: "Heists", MovieIDs: []int{8, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inserts := recorder.Find("INSERT INTO collection_movie")
+	if id != 1 || len(inserts) != 2 || inserts[0].Count(8) != 1 || inserts[1].Count(4) != 1 || inserts[1].Count(2) != 1 {
+		t.Fatalf("got %d and %+v, want the movies inserted in order with the collection", id, inserts)
+	}
+}
+
+func TestUpdateCollectionWithMovies(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	repo := mysql.NewMySQLCollectionRepository(sqltest.Open(recorder))
+
+	err := repo.UpdateCollection(context.Background(), 3, domain.RequestCollection{Name: "Heists", MovieIDs: []int{8, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The collection and its movies are written in a single transaction
+	queries := recorder.Queries()
+	if len(queries) != 6 || queries[0] != "BEGIN" || !strings.Contains(queries[1], "UPDATE collection") || !strings.Contains(queries[2], "DELETE FROM collection_movie") || queries[5] != "COMMIT" {
+		t.Fatalf("got %q, want the update and the movies in one transaction", queries)
+	}
+}
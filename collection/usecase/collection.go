@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type collectionUseCase struct {
+	collectionMySQLRepo domain.CollectionMySQLRepo
+	movieMySQLRepo      domain.MovieMySQLRepo
+}
+
+func NewCollectionUsecase(CollectionMySQLRepo domain.CollectionMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.CollectionUseCase {
+	return &collectionUseCase{
+		collectionMySQLRepo: CollectionMySQLRepo,
+		movieMySQLRepo:      MovieMySQLRepo,
+	}
+}
+
+func (clu *collectionUseCase) validate(ctx context.Context, request *domain.RequestCollection) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+
+	if request.Type == "" {
+		request.Type = domain.CollectionTypeFranchise
+	}
+	if request.Type != domain.CollectionTypeFranchise && request.Type != domain.CollectionTypeCurated {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("type must be franchise or curated")}
+	}
+
+	seen := map[int]bool{}
+	for _, movieID := range request.MovieIDs {
+		if seen[movieID] {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("movie_ids must be unique")}
+		}
+		seen[movieID] = true
+
+		err = clu.checkMovie(ctx, movieID)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func (clu *collectionUseCase) checkMovie(ctx context.Context, movieID int) (err error) {
+	_, err = clu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		if err.Error() == "Not found" {
+			return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+		}
+		return err
+	}
+	return
+}
+
+func (clu *collectionUseCase) PostCollection(ctx context.Context, request domain.RequestCollection) (id int, err error) {
+	err = clu.validate(ctx, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = clu.collectionMySQLRepo.PostCollection(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (clu *collectionUseCase) GetAllCollection(ctx context.Context, request domain.RequestParamCollection) (response domain.ResponseGetAllCollection, err error) {
+	resCount, err := clu.collectionMySQLRepo.CountDataCollection(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllCollection{}, err
+	}
+
+	resCollection, err := clu.collectionMySQLRepo.GetAllCollection(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllCollection{
+		MetaData: resCount,
+		Data:     resCollection,
+	}
+	return
+}
+
+func (clu *collectionUseCase) GetDetailCollection(ctx context.Context, id int) (response domain.ResponseCollection, err error) {
+	response, err = clu.collectionMySQLRepo.GetDetailCollection(ctx, id)
+	if err != nil {
+		return response, err
+	}
+
+	// The collection is public, unpublished movies are left out
+	response.Movies, err = clu.collectionMySQLRepo.GetCollectionMovie(ctx, id, true)
+	if err != nil {
+		return domain.ResponseCollection{}, err
+	}
+	return
+}
+
+// UpdateCollection updates the collection, membership is only replaced when movie_ids is sent
+func (clu *collectionUseCase) UpdateCollection(ctx context.Context, id int, request domain.RequestCollection) (err error) {
+	_, err = clu.collectionMySQLRepo.GetDetailCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = clu.validate(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	err = clu.collectionMySQLRepo.UpdateCollection(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (clu *collectionUseCase) DeleteCollection(ctx context.Context, id int) (err error) {
+	_, err = clu.collectionMySQLRepo.GetDetailCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return clu.collectionMySQLRepo.DeleteCollection(ctx, id)
+}
+
+func (clu *collectionUseCase) AddCollectionMovie(ctx context.Context, id int, request domain.RequestCollectionMovie) (err error) {
+	_, err = clu.collectionMySQLRepo.GetDetailCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = clu.checkMovie(ctx, request.MovieID)
+	if err != nil {
+		return err
+	}
+
+	movies, err := clu.collectionMySQLRepo.GetCollectionMovie(ctx, id, false)
+	if err != nil {
+		return err
+	}
+
+	movieIDs := make([]int, len(movies))
+	for idx, movie := range movies {
+		movieIDs[idx] = int(movie.ID)
+	}
+	position, err := helper.MoviePosition(movieIDs, request.MovieID, request.Position)
+	if err != nil {
+		return err
+	}
+
+	return clu.collectionMySQLRepo.AddCollectionMovie(ctx, id, request.MovieID, position)
+}
+
+func (clu *collectionUseCase) DeleteCollectionMovie(ctx context.Context, id int, movieID int) (err error) {
+	return clu.collectionMySQLRepo.DeleteCollectionMovie(ctx, id, movieID)
+}
@@ -18,6 +18,25 @@ type Config struct {
 	Database Database `yaml:"database"`
 	Redis    Redis    `yaml:"redis"`
 	GRPC     GRPC     `yaml:"grpc"`
+	Auth     Auth     `yaml:"auth"`
+	Review   Review   `yaml:"review"`
+
+	Moderation Moderation `yaml:"moderation"`
+	Similar    Similar    `yaml:"similar"`
+
+	Recommendation Recommendation `yaml:"recommendation"`
+	Popularity     Popularity     `yaml:"popularity"`
+	Theater        Theater        `yaml:"theater"`
+	Showtime       Showtime       `yaml:"showtime"`
+	Seat           Seat           `yaml:"seat"`
+	Pricing        Pricing        `yaml:"pricing"`
+	Payment        Payment        `yaml:"payment"`
+	Ticket         Ticket         `yaml:"ticket"`
+	Calendar       Calendar       `yaml:"calendar"`
+	Movie          Movie          `yaml:"movie"`
+	Audit          Audit          `yaml:"audit"`
+	Import         Import         `yaml:"import"`
+	Dump           Dump           `yaml:"dump"`
 }
 
 type GRPC struct {
@@ -130,6 +149,208 @@ type Redis struct {
 	Database uint64 `yaml:"database"`
 }
 
+// Auth is bearer token related config
+type Auth struct {
+	// Secret is the HS256 key shared with the auth service, tokens are rejected when it is empty
+	Secret string `yaml:"secret"`
+}
+
+// Review is user review related config
+type Review struct {
+	// BayesianMinVotes is the number of reviews needed before the movie mean outweighs the global mean
+	BayesianMinVotes float64 `yaml:"bayesian_min_votes"`
+
+	// PriorMean is the global mean used while there is no review at all
+	PriorMean float64 `yaml:"prior_mean"`
+}
+
+// Moderation is user review moderation related config
+type Moderation struct {
+	// WordListEn and WordListId are the profanity word list files, one word per line
+	WordListEn string `yaml:"wordlist_en"`
+	WordListId string `yaml:"wordlist_id"`
+
+	// AutoApprove publishes reviews passing the filter without waiting for a moderator
+	AutoApprove bool `yaml:"auto_approve"`
+
+	// MaxLinks is the maximum links allowed in a review before it is flagged as spam
+	MaxLinks int `yaml:"max_links"`
+
+	// ReportThreshold is the number of abuse reports sending an approved review back to the queue
+	ReportThreshold int `yaml:"report_threshold"`
+}
+
+// Similar is content based similar movie related config
+type Similar struct {
+	// Interval is the number of minutes between similarity job runs, 0 disables the job
+	Interval int `yaml:"interval"`
+
+	// TopK is the number of similar movies stored per movie
+	TopK int `yaml:"top_k"`
+
+	// WeightDescription, WeightTag, WeightCredit and WeightCollection weight the description TF-IDF
+	// cosine, the tag (genre) overlap, the credited people overlap and the shared collection signals,
+	// they are normalized by their sum
+	WeightDescription float64 `yaml:"weight_description"`
+	WeightTag         float64 `yaml:"weight_tag"`
+	WeightCredit      float64 `yaml:"weight_credit"`
+	WeightCollection  float64 `yaml:"weight_collection"`
+}
+
+// Recommendation is collaborative filtering related config
+type Recommendation struct {
+	// WeightReview, WeightFavorite and WeightWatchlist are the implicit preference of each signal,
+	// a review weight is scaled by its score
+	WeightReview    float64 `yaml:"weight_review"`
+	WeightFavorite  float64 `yaml:"weight_favorite"`
+	WeightWatchlist float64 `yaml:"weight_watchlist"`
+
+	// MinCommonUsers is the number of users two movies need in common to be neighbors
+	MinCommonUsers int `yaml:"min_common_users"`
+
+	// Neighbors is the number of similar movies stored per movie
+	Neighbors int `yaml:"neighbors"`
+
+	// Limit is the default number of recommended movies
+	Limit int `yaml:"limit"`
+}
+
+// Popularity is movie view counting and trending related config
+type Popularity struct {
+	// DedupeWindow is the number of minutes a client is counted once per movie
+	DedupeWindow int `yaml:"dedupe_window"`
+
+	// HalfLifeDay and HalfLifeWeek are the hours after which a view counts half in the day and week trending
+	HalfLifeDay  float64 `yaml:"half_life_day"`
+	HalfLifeWeek float64 `yaml:"half_life_week"`
+
+	// FlushInterval is the number of minutes between flushing view counts to MySQL, 0 disables it
+	FlushInterval int `yaml:"flush_interval"`
+
+	// TrendingCache is the number of seconds a computed trending list is reused
+	TrendingCache int `yaml:"trending_cache"`
+
+	// TrendingLimit is the default number of trending movies
+	TrendingLimit int `yaml:"trending_limit"`
+}
+
+// Theater is theater geo search related config
+type Theater struct {
+	// NearbyRadius is the default kilometers searched around a point
+	NearbyRadius float64 `yaml:"nearby_radius"`
+	// MaxNearbyRadius is the largest radius a search may ask for, 0 disables the limit
+	MaxNearbyRadius float64 `yaml:"max_nearby_radius"`
+	// NearbyLimit is the default number of theaters returned by a search, 0 returns every theater
+	NearbyLimit int `yaml:"nearby_limit"`
+}
+
+// Showtime is cinema scheduling related config
+type Showtime struct {
+	// CleaningTime is the minutes added after the movie runtime before the auditorium is free again
+	CleaningTime int `yaml:"cleaning_time"`
+}
+
+// Seat is seat hold related config
+type Seat struct {
+	// HoldTTL is the seconds a held seat stays reserved before it is released
+	HoldTTL int `yaml:"hold_ttl"`
+	// MaxHold is the maximum number of seats a user holds on a showtime, 0 disables the limit
+	MaxHold int `yaml:"max_hold"`
+}
+
+// Pricing multiplies the showtime base price into the seat price
+type Pricing struct {
+	// SeatVIP is the multiplier of vip seats
+	SeatVIP float64 `yaml:"seat_vip"`
+	// SeatWheelchair is the multiplier of wheelchair seats
+	SeatWheelchair float64 `yaml:"seat_wheelchair"`
+	// Weekend is the multiplier of showtimes starting on Saturday or Sunday
+	Weekend float64 `yaml:"weekend"`
+}
+
+// Payment is booking payment related config
+type Payment struct {
+	// Provider is the payment provider of new bookings
+	Provider string `yaml:"provider"`
+	// Timeout is the minutes a pending booking waits for its payment before it is cancelled
+	Timeout int `yaml:"timeout"`
+	// ExpireInterval is the minutes between two runs of the job cancelling expired bookings, 0 disables it
+	ExpireInterval int `yaml:"expire_interval"`
+	// WebhookTolerance is the seconds a signed webhook timestamp may differ from the server time
+	WebhookTolerance int `yaml:"webhook_tolerance"`
+	// Fake is the provider for local testing
+	Fake PaymentFake `yaml:"fake"`
+}
+
+type PaymentFake struct {
+	// Secret signs the fake provider webhooks
+	Secret string `yaml:"secret"`
+}
+
+// Ticket is booking ticket related config
+type Ticket struct {
+	// Secret signs the ticket QR code tokens
+	Secret string `yaml:"secret"`
+	// EntryBefore is the minutes before the showtime start a ticket is admitted
+	EntryBefore int `yaml:"entry_before"`
+}
+
+// Calendar is iCalendar feed related config
+type Calendar struct {
+	// UIDDomain is the domain part of the event UIDs, changing it duplicates the events of subscribed calendars
+	UIDDomain string `yaml:"uid_domain"`
+	// PastDays is the number of past days kept in the feeds
+	PastDays int `yaml:"past_days"`
+	// FutureDays is the number of days ahead listed in the feeds
+	FutureDays int `yaml:"future_days"`
+	// RefreshInterval is the minutes calendar apps are asked to wait before fetching a feed again
+	RefreshInterval int `yaml:"refresh_interval"`
+}
+
+// Movie is movie publishing related config
+type Movie struct {
+	// PublishInterval is the minutes between two runs of the job publishing scheduled movies, 0 disables it
+	PublishInterval int `yaml:"publish_interval"`
+	// BatchMaxOperations is the largest number of operations of a batch, 0 disables the limit
+	BatchMaxOperations int `yaml:"batch_max_operations"`
+	// ExportTimeout is the seconds an export may stream before it is stopped, 0 disables the limit
+	ExportTimeout int `yaml:"export_timeout"`
+}
+
+// Audit is mutating API call logging related config
+type Audit struct {
+	// MaxPayload is the bytes kept of a request or response body, 0 keeps it whole
+	MaxPayload int `yaml:"max_payload"`
+	// Redact lists the request fields stored as [REDACTED]
+	Redact []string `yaml:"redact"`
+}
+
+// Import is movie bulk import related config
+type Import struct {
+	// MaxRows is the largest number of rows of an imported file, 0 disables the limit
+	MaxRows int `yaml:"max_rows"`
+	// MaxImageSize is the largest image in bytes, 0 disables the limit
+	MaxImageSize int64 `yaml:"max_image_size"`
+	// ImageTimeout is the seconds allowed to download an image URL
+	ImageTimeout int `yaml:"image_timeout"`
+	// AllowPrivateHosts lets image URLs reach loopback, private and link-local addresses, for local testing only
+	AllowPrivateHosts bool `yaml:"allow_private_hosts"`
+}
+
+// Dump is offline TMDB and OMDb dump import related config
+type Dump struct {
+	// Region is the ISO 3166-1 alpha-2 country code whose TMDB release date is kept
+	Region string `yaml:"region"`
+	// MaxCast is the number of billed actors kept per movie, 0 keeps the whole cast
+	MaxCast int `yaml:"max_cast"`
+	// CrewJobs lists the TMDB crew jobs kept, empty keeps the whole crew
+	CrewJobs []string `yaml:"crew_jobs"`
+	// DownloadImages fetches the posters missing from the images directory
+	DownloadImages bool `yaml:"download_images"`
+	// ImageBaseURL is prepended to TMDB poster paths to download them
+	ImageBaseURL string `yaml:"image_base_url"`
+}
+
 var defaultConfig = &Config{
 	Server: Server{
 		Port:          "8887",
@@ -169,6 +390,107 @@ var defaultConfig = &Config{
 		Password:      "",
 		Database:      0,
 	},
+
+	Auth: Auth{
+		Secret: "",
+	},
+
+	Review: Review{
+		BayesianMinVotes: 10,
+		PriorMean:        6,
+	},
+
+	Moderation: Moderation{
+		WordListEn:      "../wordlist/en.txt",
+		WordListId:      "../wordlist/id.txt",
+		AutoApprove:     true,
+		MaxLinks:        1,
+		ReportThreshold: 3,
+	},
+
+	Similar: Similar{
+		Interval:          60,
+		TopK:              10,
+		WeightDescription: 0.4,
+		WeightTag:         0.4,
+		WeightCollection:  0.2,
+	},
+
+	Recommendation: Recommendation{
+		WeightReview:    1,
+		WeightFavorite:  1,
+		WeightWatchlist: 0.5,
+		MinCommonUsers:  2,
+		Neighbors:       20,
+		Limit:           20,
+	},
+
+	Popularity: Popularity{
+		DedupeWindow:  30,
+		HalfLifeDay:   6,
+		HalfLifeWeek:  48,
+		FlushInterval: 5,
+		TrendingCache: 60,
+		TrendingLimit: 20,
+	},
+
+	Theater: Theater{
+		NearbyRadius:    10,
+		MaxNearbyRadius: 100,
+		NearbyLimit:     20,
+	},
+	Showtime: Showtime{
+		CleaningTime: 15,
+	},
+	Seat: Seat{
+		HoldTTL: 300,
+		MaxHold: 10,
+	},
+	Pricing: Pricing{
+		SeatVIP:        1.5,
+		SeatWheelchair: 1,
+		Weekend:        1.25,
+	},
+	Payment: Payment{
+		Provider:         "fake",
+		Timeout:          15,
+		ExpireInterval:   1,
+		WebhookTolerance: 300,
+		Fake: PaymentFake{
+			Secret: "fake-webhook-secret",
+		},
+	},
+	Ticket: Ticket{
+		EntryBefore: 60,
+	},
+	Calendar: Calendar{
+		UIDDomain:       "service-movie.xsis-academy",
+		PastDays:        7,
+		FutureDays:      90,
+		RefreshInterval: 60,
+	},
+	Movie: Movie{
+		PublishInterval:    1,
+		BatchMaxOperations: 500,
+		ExportTimeout:      600,
+	},
+	Audit: Audit{
+		MaxPayload: 65536,
+		Redact:     []string{"password", "secret", "token"},
+	},
+	Import: Import{
+		MaxRows:           10000,
+		MaxImageSize:      5 * 1024 * 1024,
+		ImageTimeout:      30,
+		AllowPrivateHosts: false,
+	},
+	Dump: Dump{
+		Region:         "ID",
+		MaxCast:        20,
+		CrewJobs:       []string{"Director", "Screenplay", "Writer", "Producer", "Original Music Composer"},
+		DownloadImages: false,
+		ImageBaseURL:   "https://image.tmdb.org/t/p/original",
+	},
 }
 
 func lookupEnv(parent string, rt reflect.Type, rv reflect.Value) {
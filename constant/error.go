@@ -17,6 +17,7 @@ const (
 	StatusUnauthorizedMemberIsNotRegistered    = 4011
 	StatusUnauthorizedBacalegIsRegistered      = 4012
 	StatusUnauthorizedTokenExpired             = 4013
+	StatusUnauthorizedInvalidSignature         = 4014
 	StatusForbiddenInvalidToken                = 4031
 	StatusNotFound                             = 4041
 	StatusMethodNotAllowed                     = 4051
@@ -63,6 +64,14 @@ var constantError = map[InternalError]errorInfo{
 			Id: "Token sudah kadaluarsa, silahkan lakukan login",
 		},
 	},
+	StatusUnauthorizedInvalidSignature: {
+		HttpCode: fiber.StatusUnauthorized,
+		Title:    "Invalid signature",
+		UserMessage: UserMessage{
+			En: "The request signature is invalid",
+			Id: "Signature request tidak sah",
+		},
+	},
 	StatusForbiddenInvalidToken: {
 		HttpCode: fiber.StatusForbidden,
 		Title:    "Invalid token",
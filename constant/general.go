@@ -9,3 +9,7 @@ type ResultError struct {
 	Code InternalError
 	Err  error
 }
+
+func (r ResultError) Error() string {
+	return r.Err.Error()
+}
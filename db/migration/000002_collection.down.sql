@@ -0,0 +1,2 @@
+DROP TABLE collection_movie;
+DROP TABLE collection;
@@ -0,0 +1,18 @@
+CREATE TABLE collection (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type ENUM('franchise', 'curated') NOT NULL DEFAULT 'franchise',
+    description TEXT NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE collection_movie (
+    collection_id INT NOT NULL,
+    movie_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, movie_id),
+    INDEX idx_collection_movie_movie (movie_id),
+    CONSTRAINT fk_collection_movie_collection FOREIGN KEY (collection_id) REFERENCES collection (id) ON DELETE CASCADE,
+    CONSTRAINT fk_collection_movie_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
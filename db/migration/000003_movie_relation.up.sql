@@ -0,0 +1,10 @@
+CREATE TABLE movie_relation (
+    movie_id INT NOT NULL,
+    related_movie_id INT NOT NULL,
+    type ENUM('sequel_of', 'remake_of', 'spin_off_of') NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, related_movie_id, type),
+    INDEX idx_movie_relation_related (related_movie_id),
+    CONSTRAINT fk_movie_relation_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_relation_related FOREIGN KEY (related_movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
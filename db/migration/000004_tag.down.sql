@@ -0,0 +1,2 @@
+DROP TABLE movie_tag;
+DROP TABLE tag;
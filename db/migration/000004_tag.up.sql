@@ -0,0 +1,17 @@
+CREATE TABLE tag (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_tag_slug (slug)
+);
+
+CREATE TABLE movie_tag (
+    movie_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (movie_id, tag_id),
+    INDEX idx_movie_tag_tag (tag_id),
+    CONSTRAINT fk_movie_tag_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_tag_tag FOREIGN KEY (tag_id) REFERENCES tag (id) ON DELETE CASCADE
+);
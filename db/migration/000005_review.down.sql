@@ -0,0 +1,2 @@
+DROP TABLE movie_rating;
+DROP TABLE review;
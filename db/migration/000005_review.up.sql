@@ -0,0 +1,30 @@
+CREATE TABLE review (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    movie_id INT NOT NULL,
+    user_id INT NOT NULL,
+    score TINYINT NOT NULL,
+    text TEXT NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_review_movie_user (movie_id, user_id),
+    INDEX idx_review_user (user_id),
+    CONSTRAINT fk_review_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
+
+CREATE TABLE movie_rating (
+    movie_id INT PRIMARY KEY,
+    review_count INT NOT NULL DEFAULT 0,
+    score_sum INT NOT NULL DEFAULT 0,
+    score_1 INT NOT NULL DEFAULT 0,
+    score_2 INT NOT NULL DEFAULT 0,
+    score_3 INT NOT NULL DEFAULT 0,
+    score_4 INT NOT NULL DEFAULT 0,
+    score_5 INT NOT NULL DEFAULT 0,
+    score_6 INT NOT NULL DEFAULT 0,
+    score_7 INT NOT NULL DEFAULT 0,
+    score_8 INT NOT NULL DEFAULT 0,
+    score_9 INT NOT NULL DEFAULT 0,
+    score_10 INT NOT NULL DEFAULT 0,
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_movie_rating_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
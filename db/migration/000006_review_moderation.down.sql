@@ -0,0 +1,10 @@
+DROP TABLE review_report;
+
+ALTER TABLE review
+    DROP INDEX idx_review_status,
+    DROP COLUMN dtm_moderated,
+    DROP COLUMN moderation_note,
+    DROP COLUMN moderated_by,
+    DROP COLUMN report_count,
+    DROP COLUMN flag_reason,
+    DROP COLUMN status;
@@ -0,0 +1,20 @@
+ALTER TABLE review
+    ADD COLUMN status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'approved' AFTER text,
+    ADD COLUMN flag_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER status,
+    ADD COLUMN report_count INT NOT NULL DEFAULT 0 AFTER flag_reason,
+    ADD COLUMN moderated_by INT NULL AFTER report_count,
+    ADD COLUMN moderation_note TEXT NULL AFTER moderated_by,
+    ADD COLUMN dtm_moderated TIMESTAMP NULL AFTER moderation_note,
+    ADD INDEX idx_review_status (status);
+
+ALTER TABLE review ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE TABLE review_report (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    review_id INT NOT NULL,
+    user_id INT NOT NULL,
+    reason TEXT NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_review_report_user (review_id, user_id),
+    CONSTRAINT fk_review_report_review FOREIGN KEY (review_id) REFERENCES review (id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE user_movie (
+    user_id INT NOT NULL,
+    movie_id INT NOT NULL,
+    type ENUM('watchlist', 'favorite') NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, type, movie_id),
+    INDEX idx_user_movie_movie (movie_id),
+    CONSTRAINT fk_user_movie_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE user_list_movie;
+DROP TABLE user_list;
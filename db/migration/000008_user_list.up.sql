@@ -0,0 +1,23 @@
+CREATE TABLE user_list (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_user_list_slug (slug),
+    INDEX idx_user_list_user (user_id)
+);
+
+CREATE TABLE user_list_movie (
+    list_id INT NOT NULL,
+    movie_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, movie_id),
+    INDEX idx_user_list_movie_movie (movie_id),
+    CONSTRAINT fk_user_list_movie_list FOREIGN KEY (list_id) REFERENCES user_list (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_list_movie_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE movie_similar;
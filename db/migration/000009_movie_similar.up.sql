@@ -0,0 +1,10 @@
+CREATE TABLE movie_similar (
+    movie_id INT NOT NULL,
+    similar_movie_id INT NOT NULL,
+    score FLOAT NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, similar_movie_id),
+    INDEX idx_movie_similar_score (movie_id, score),
+    CONSTRAINT fk_movie_similar_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_similar_similar FOREIGN KEY (similar_movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
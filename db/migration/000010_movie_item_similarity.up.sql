@@ -0,0 +1,9 @@
+CREATE TABLE movie_item_similarity (
+    movie_id INT NOT NULL,
+    similar_movie_id INT NOT NULL,
+    score FLOAT NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, similar_movie_id),
+    CONSTRAINT fk_movie_item_similarity_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_item_similarity_similar FOREIGN KEY (similar_movie_id) REFERENCES movie (id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE movie
+    DROP INDEX idx_movie_popularity,
+    DROP COLUMN popularity,
+    DROP COLUMN view_count;
@@ -0,0 +1,4 @@
+ALTER TABLE movie
+    ADD COLUMN view_count BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN popularity DOUBLE NOT NULL DEFAULT 0,
+    ADD INDEX idx_movie_popularity (popularity);
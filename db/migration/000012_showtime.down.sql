@@ -0,0 +1,4 @@
+DROP TABLE showtime;
+DROP TABLE auditorium;
+DROP TABLE theater;
+ALTER TABLE movie DROP COLUMN runtime;
@@ -0,0 +1,37 @@
+ALTER TABLE movie ADD COLUMN runtime INT NOT NULL DEFAULT 0;
+
+CREATE TABLE theater (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    INDEX idx_theater_city (city)
+);
+
+CREATE TABLE auditorium (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    theater_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    capacity INT NOT NULL DEFAULT 0,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_auditorium_theater_name (theater_id, name),
+    CONSTRAINT fk_auditorium_theater FOREIGN KEY (theater_id) REFERENCES theater (id) ON DELETE CASCADE
+);
+
+CREATE TABLE showtime (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    movie_id INT NOT NULL,
+    auditorium_id INT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    INDEX idx_showtime_auditorium (auditorium_id, start_time),
+    INDEX idx_showtime_movie (movie_id, start_time),
+    INDEX idx_showtime_start (start_time),
+    CONSTRAINT fk_showtime_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_showtime_auditorium FOREIGN KEY (auditorium_id) REFERENCES auditorium (id) ON DELETE CASCADE
+);
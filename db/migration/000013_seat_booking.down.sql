@@ -0,0 +1,3 @@
+DROP TABLE booking_seat;
+DROP TABLE booking;
+DROP TABLE seat;
@@ -0,0 +1,30 @@
+CREATE TABLE seat (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    auditorium_id INT NOT NULL,
+    row_label VARCHAR(5) NOT NULL,
+    number INT NOT NULL,
+    type ENUM('regular', 'vip', 'wheelchair') NOT NULL DEFAULT 'regular',
+    UNIQUE INDEX uq_seat_auditorium_position (auditorium_id, row_label, number),
+    CONSTRAINT fk_seat_auditorium FOREIGN KEY (auditorium_id) REFERENCES auditorium (id) ON DELETE CASCADE
+);
+
+CREATE TABLE booking (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    showtime_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    INDEX idx_booking_user (user_id, dtm_crt),
+    CONSTRAINT fk_booking_showtime FOREIGN KEY (showtime_id) REFERENCES showtime (id) ON DELETE CASCADE
+);
+
+CREATE TABLE booking_seat (
+    booking_id INT NOT NULL,
+    showtime_id INT NOT NULL,
+    seat_id INT NOT NULL,
+    PRIMARY KEY (booking_id, seat_id),
+    UNIQUE INDEX uq_booking_seat_showtime (showtime_id, seat_id),
+    CONSTRAINT fk_booking_seat_booking FOREIGN KEY (booking_id) REFERENCES booking (id) ON DELETE CASCADE,
+    CONSTRAINT fk_booking_seat_seat FOREIGN KEY (seat_id) REFERENCES seat (id)
+);
@@ -0,0 +1,23 @@
+DROP TABLE payment_event;
+
+DELETE FROM booking_seat WHERE active IS NULL;
+
+ALTER TABLE booking_seat
+    DROP INDEX uq_booking_seat_active,
+    ADD UNIQUE INDEX uq_booking_seat_showtime (showtime_id, seat_id),
+    DROP COLUMN active,
+    DROP COLUMN price;
+
+UPDATE booking SET status = 'confirmed' WHERE status = 'paid';
+
+ALTER TABLE booking
+    DROP INDEX idx_booking_expire,
+    DROP INDEX uq_booking_payment,
+    DROP COLUMN expires_at,
+    DROP COLUMN payment_url,
+    DROP COLUMN payment_ref,
+    DROP COLUMN payment_provider,
+    DROP COLUMN total_price,
+    MODIFY status VARCHAR(20) NOT NULL DEFAULT 'confirmed';
+
+ALTER TABLE showtime DROP COLUMN base_price;
@@ -0,0 +1,32 @@
+ALTER TABLE showtime ADD COLUMN base_price INT UNSIGNED NOT NULL DEFAULT 0 AFTER end_time;
+
+ALTER TABLE booking
+    MODIFY status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN total_price INT UNSIGNED NOT NULL DEFAULT 0 AFTER status,
+    ADD COLUMN payment_provider VARCHAR(30) NULL AFTER total_price,
+    ADD COLUMN payment_ref VARCHAR(100) NULL AFTER payment_provider,
+    ADD COLUMN payment_url VARCHAR(255) NULL AFTER payment_ref,
+    ADD COLUMN expires_at DATETIME NULL AFTER payment_url,
+    ADD UNIQUE INDEX uq_booking_payment (payment_provider, payment_ref),
+    ADD INDEX idx_booking_expire (status, expires_at);
+
+UPDATE booking SET status = 'paid' WHERE status = 'confirmed';
+
+-- active is set to NULL when the booking is cancelled or refunded, the unique key ignores NULL
+-- so the seat can be booked again
+ALTER TABLE booking_seat
+    ADD COLUMN price INT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN active TINYINT NULL DEFAULT 1,
+    DROP INDEX uq_booking_seat_showtime,
+    ADD UNIQUE INDEX uq_booking_seat_active (showtime_id, seat_id, active);
+
+CREATE TABLE payment_event (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    booking_id INT NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_payment_event (provider, event_id),
+    CONSTRAINT fk_payment_event_booking FOREIGN KEY (booking_id) REFERENCES booking (id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE booking_seat
+    DROP COLUMN used_by,
+    DROP COLUMN used_at;
@@ -0,0 +1,3 @@
+ALTER TABLE booking_seat
+    ADD COLUMN used_at DATETIME NULL,
+    ADD COLUMN used_by INT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE booking DROP COLUMN discount;
+DROP TABLE booking_promo;
+DROP TABLE promo;
@@ -0,0 +1,36 @@
+CREATE TABLE promo (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    discount_type ENUM('percentage', 'fixed') NOT NULL,
+    discount_value INT UNSIGNED NOT NULL,
+    max_discount INT UNSIGNED NULL,
+    movie_id INT NULL,
+    theater_id INT NULL,
+    starts_at DATETIME NULL,
+    ends_at DATETIME NULL,
+    usage_limit INT NULL,
+    per_user_limit INT NULL,
+    used_count INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    stackable TINYINT(1) NOT NULL DEFAULT 0,
+    active TINYINT(1) NOT NULL DEFAULT 1,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_promo_code (code),
+    CONSTRAINT fk_promo_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_promo_theater FOREIGN KEY (theater_id) REFERENCES theater (id) ON DELETE CASCADE
+);
+
+CREATE TABLE booking_promo (
+    booking_id INT NOT NULL,
+    promo_id INT NOT NULL,
+    user_id INT NOT NULL,
+    discount INT UNSIGNED NOT NULL,
+    PRIMARY KEY (booking_id, promo_id),
+    INDEX idx_booking_promo_user (promo_id, user_id),
+    CONSTRAINT fk_booking_promo_booking FOREIGN KEY (booking_id) REFERENCES booking (id) ON DELETE CASCADE,
+    CONSTRAINT fk_booking_promo_promo FOREIGN KEY (promo_id) REFERENCES promo (id) ON DELETE CASCADE
+);
+
+ALTER TABLE booking ADD COLUMN discount INT UNSIGNED NOT NULL DEFAULT 0 AFTER total_price;
@@ -0,0 +1,4 @@
+ALTER TABLE theater
+    DROP INDEX idx_theater_geo,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude;
@@ -0,0 +1,4 @@
+ALTER TABLE theater
+    ADD COLUMN latitude DECIMAL(9,6) NULL AFTER city,
+    ADD COLUMN longitude DECIMAL(9,6) NULL AFTER latitude,
+    ADD INDEX idx_theater_geo (latitude, longitude);
@@ -0,0 +1,3 @@
+ALTER TABLE movie
+    DROP INDEX idx_movie_release,
+    DROP COLUMN release_date;
@@ -0,0 +1,3 @@
+ALTER TABLE movie
+    ADD COLUMN release_date DATE NULL AFTER runtime,
+    ADD INDEX idx_movie_release (release_date);
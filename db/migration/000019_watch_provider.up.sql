@@ -0,0 +1,29 @@
+CREATE TABLE watch_provider (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    logo_url VARCHAR(500) NOT NULL DEFAULT '',
+    website VARCHAR(500) NOT NULL DEFAULT '',
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_watch_provider_slug (slug)
+);
+
+CREATE TABLE movie_availability (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    movie_id INT NOT NULL,
+    provider_id INT NOT NULL,
+    region CHAR(2) NOT NULL,
+    type ENUM('stream', 'rent', 'buy', 'free') NOT NULL,
+    price BIGINT NULL,
+    currency CHAR(3) NULL,
+    link VARCHAR(1000) NOT NULL,
+    valid_from DATE NULL,
+    valid_to DATE NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_movie_availability (movie_id, provider_id, region, type),
+    INDEX idx_movie_availability_provider (provider_id, region),
+    CONSTRAINT fk_movie_availability_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_availability_provider FOREIGN KEY (provider_id) REFERENCES watch_provider (id) ON DELETE CASCADE
+);
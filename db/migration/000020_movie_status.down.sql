@@ -0,0 +1,4 @@
+ALTER TABLE movie
+    DROP INDEX idx_movie_status,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
@@ -0,0 +1,7 @@
+ALTER TABLE movie
+    ADD COLUMN status ENUM('draft', 'review', 'published', 'archived') NOT NULL DEFAULT 'draft' AFTER release_date,
+    ADD COLUMN publish_at DATETIME NULL AFTER status,
+    ADD INDEX idx_movie_status (status, publish_at);
+
+-- Movies created before the workflow are already public
+UPDATE movie SET status = 'published';
@@ -0,0 +1,10 @@
+CREATE TABLE movie_revision (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    movie_id INT NOT NULL,
+    revision INT NOT NULL,
+    action ENUM('create', 'update', 'delete', 'status', 'publish', 'restore') NOT NULL,
+    user_id INT NULL,
+    snapshot JSON NOT NULL,
+    dtm_crt DATETIME NOT NULL DEFAULT NOW(),
+    UNIQUE KEY uq_movie_revision (movie_id, revision)
+);
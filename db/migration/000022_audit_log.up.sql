@@ -0,0 +1,25 @@
+CREATE TABLE audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL,
+    role VARCHAR(50) NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    entity_id VARCHAR(64) NULL,
+    status SMALLINT NOT NULL,
+    payload MEDIUMTEXT NULL,
+    response MEDIUMTEXT NULL,
+    dtm_crt DATETIME NOT NULL DEFAULT NOW(),
+    INDEX idx_audit_log_dtm (dtm_crt),
+    INDEX idx_audit_log_user (user_id, dtm_crt),
+    INDEX idx_audit_log_entity (route, entity_id)
+);
+
+-- The audit log is append only
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append only';
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append only';
@@ -0,0 +1,3 @@
+ALTER TABLE movie
+    DROP INDEX uq_movie_external_id,
+    DROP COLUMN external_id;
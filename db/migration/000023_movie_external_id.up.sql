@@ -0,0 +1,3 @@
+ALTER TABLE movie
+    ADD COLUMN external_id VARCHAR(100) NULL AFTER id,
+    ADD UNIQUE INDEX uq_movie_external_id (external_id);
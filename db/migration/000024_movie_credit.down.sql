@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS movie_credit;
+DROP TABLE IF EXISTS person;
@@ -0,0 +1,22 @@
+CREATE TABLE person (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    external_id VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    dtm_crt TIMESTAMP NOT NULL DEFAULT NOW(),
+    dtm_upd TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE INDEX uq_person_external_id (external_id)
+);
+
+CREATE TABLE movie_credit (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    movie_id INT NOT NULL,
+    person_id INT NOT NULL,
+    kind ENUM('cast', 'crew') NOT NULL,
+    role VARCHAR(255) NOT NULL DEFAULT '',
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    INDEX idx_movie_credit_movie (movie_id, kind, position),
+    INDEX idx_movie_credit_person (person_id),
+    CONSTRAINT fk_movie_credit_movie FOREIGN KEY (movie_id) REFERENCES movie (id) ON DELETE CASCADE,
+    CONSTRAINT fk_movie_credit_person FOREIGN KEY (person_id) REFERENCES person (id) ON DELETE CASCADE
+);
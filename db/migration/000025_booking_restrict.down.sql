@@ -0,0 +1,5 @@
+ALTER TABLE payment_event DROP FOREIGN KEY fk_payment_event_booking;
+ALTER TABLE payment_event ADD CONSTRAINT fk_payment_event_booking FOREIGN KEY (booking_id) REFERENCES booking (id) ON DELETE CASCADE;
+
+ALTER TABLE booking DROP FOREIGN KEY fk_booking_showtime;
+ALTER TABLE booking ADD CONSTRAINT fk_booking_showtime FOREIGN KEY (showtime_id) REFERENCES showtime (id) ON DELETE CASCADE;
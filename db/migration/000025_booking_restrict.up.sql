@@ -0,0 +1,7 @@
+-- Bookings and payment events are the sales history, deleting a showtime or a booking must not remove them.
+-- Deleting a movie, a theater or an auditorium still cascades to its showtimes and stops at their bookings
+ALTER TABLE booking DROP FOREIGN KEY fk_booking_showtime;
+ALTER TABLE booking ADD CONSTRAINT fk_booking_showtime FOREIGN KEY (showtime_id) REFERENCES showtime (id) ON DELETE RESTRICT;
+
+ALTER TABLE payment_event DROP FOREIGN KEY fk_payment_event_booking;
+ALTER TABLE payment_event ADD CONSTRAINT fk_payment_event_booking FOREIGN KEY (booking_id) REFERENCES booking (id) ON DELETE RESTRICT;
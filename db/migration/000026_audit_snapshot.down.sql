@@ -0,0 +1,3 @@
+ALTER TABLE audit_log
+    DROP COLUMN snapshot_after,
+    DROP COLUMN snapshot_before;
@@ -0,0 +1,4 @@
+-- The entity as loaded before and after the call, BEFORE is a reserved word
+ALTER TABLE audit_log
+    ADD COLUMN snapshot_before MEDIUMTEXT NULL AFTER response,
+    ADD COLUMN snapshot_after MEDIUMTEXT NULL AFTER snapshot_before;
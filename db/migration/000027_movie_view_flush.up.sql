@@ -0,0 +1,6 @@
+-- View batches already added to movie.view_count, a batch replayed after a failed cleanup is not counted twice
+CREATE TABLE movie_view_flush (
+    flush_id CHAR(32) PRIMARY KEY,
+    dtm_crt DATETIME NOT NULL DEFAULT NOW(),
+    INDEX idx_movie_view_flush_dtm (dtm_crt)
+);
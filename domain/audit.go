@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// AuditLog is a mutating API call, Payload is the request body and Response the response body. Before and
+// After are the entity as loaded before and after the call on the routes that load it. The secret fields are
+// redacted and every body is cut to the configured size
+type AuditLog struct {
+	ID        uint    `json:"id"`
+	UserID    *int    `json:"user_id"`
+	Role      *string `json:"role"`
+	IP        string  `json:"ip"`
+	UserAgent string  `json:"user_agent"`
+	Method    string  `json:"method"`
+	Route     string  `json:"route"`
+	Path      string  `json:"path"`
+	EntityID  *string `json:"entity_id"`
+	Status    int     `json:"status"`
+	Payload   *string `json:"payload"`
+	Response  *string `json:"response"`
+	Before    *string `json:"before"`
+	After     *string `json:"after"`
+	DtmCrt    string  `json:"dtm_crt"`
+}
+
+// RequestParamAudit filters the audit log, Route is a route pattern such as /movie/:id, Path a path prefix
+// and From and To bound the call time as YYYY-MM-DD HH:MM:SS, both included
+type RequestParamAudit struct {
+	Page     *int    `json:"page"`
+	Limit    *int    `json:"limit"`
+	UserID   *int    `json:"user_id"`
+	Method   *string `json:"method"`
+	Route    *string `json:"route"`
+	Path     *string `json:"path"`
+	EntityID *string `json:"entity_id"`
+	Status   *int    `json:"status"`
+	From     *string `json:"from"`
+	To       *string `json:"to"`
+}
+
+type ResponseGetAllAudit struct {
+	MetaData MetaData   `json:"meta_data"`
+	Data     []AuditLog `json:"data"`
+}
+
+type AuditUseCase interface {
+	PostAudit(ctx context.Context, request AuditLog) (err error)
+	GetAllAudit(ctx context.Context, request RequestParamAudit) (response ResponseGetAllAudit, err error)
+	ExportAudit(ctx context.Context, request RequestParamAudit, w io.Writer) (err error)
+	ValidateAudit(request RequestParamAudit) (err error)
+}
+
+type AuditMySQLRepo interface {
+	PostAudit(ctx context.Context, request AuditLog) (err error)
+	CountDataAudit(ctx context.Context, request RequestParamAudit) (response MetaData, err error)
+	GetAllAudit(ctx context.Context, request RequestParamAudit) (response []AuditLog, err error)
+	// EachAudit calls fn with every matching log, oldest first, without loading them all
+	EachAudit(ctx context.Context, request RequestParamAudit, fn func(AuditLog) error) (err error)
+}
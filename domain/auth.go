@@ -0,0 +1,20 @@
+package domain
+
+const (
+	RoleUser      = "user"
+	RoleEditor    = "editor"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+	RoleStaff     = "staff"
+)
+
+// AuthUser is the user authenticated from the bearer token
+type AuthUser struct {
+	ID   int    `json:"id"`
+	Role string `json:"role"`
+}
+
+// CanManageMovie tells whether the user sees and moves movies of every status, other users only see published movies
+func CanManageMovie(user *AuthUser) bool {
+	return user != nil && (user.Role == RoleEditor || user.Role == RoleAdmin)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	BookingStatusPending   = "pending"
+	BookingStatusPaid      = "paid"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusRefunded  = "refunded"
+)
+
+// BookingTransition lists the statuses a booking moves to from each status,
+// cancelled and refunded are final and free the seats
+var BookingTransition = map[string][]string{
+	BookingStatusPending: {BookingStatusPaid, BookingStatusCancelled},
+	BookingStatusPaid:    {BookingStatusRefunded},
+}
+
+type RequestBooking struct {
+	SeatIDs    []int    `json:"seat_ids" form:"seat_ids"`
+	PromoCodes []string `json:"promo_codes" form:"promo_codes"`
+}
+
+type ResponseBooking struct {
+	ID              uint                  `json:"id"`
+	UserID          uint                  `json:"user_id"`
+	ShowtimeID      uint                  `json:"showtime_id"`
+	MovieTitle      string                `json:"movie_title"`
+	TheaterName     string                `json:"theater_name"`
+	StartTime       string                `json:"start_time"`
+	Status          string                `json:"status"`
+	Discount        int64                 `json:"discount"`
+	TotalPrice      int64                 `json:"total_price"`
+	PaymentProvider string                `json:"payment_provider,omitempty"`
+	PaymentRef      string                `json:"payment_ref,omitempty"`
+	PaymentURL      string                `json:"payment_url,omitempty"`
+	ExpiresAt       *string               `json:"expires_at,omitempty"`
+	Seats           []ResponseBookingSeat `json:"seats,omitempty"`
+	DtmCrt          string                `json:"dtm_crt"`
+	DtmUpd          string                `json:"dtm_upd"`
+}
+
+type ResponseBookingSeat struct {
+	SeatID uint   `json:"seat_id"`
+	Row    string `json:"row"`
+	Number int    `json:"number"`
+	Type   string `json:"type"`
+	Price  int64  `json:"price"`
+}
+
+// ResponseBookingPreview is the price of the requested seats before booking, Total is Subtotal minus Discount
+type ResponseBookingPreview struct {
+	Subtotal int64                 `json:"subtotal"`
+	Discount int64                 `json:"discount"`
+	Total    int64                 `json:"total"`
+	Seats    []ResponseBookingSeat `json:"seats"`
+	Promos   []ResponsePromoResult `json:"promos"`
+}
+
+type RequestParamBooking struct {
+	UserID int  `json:"-"`
+	Page   *int `json:"page"`
+	Limit  *int `json:"limit"`
+}
+
+type ResponseGetAllBooking struct {
+	MetaData MetaData          `json:"meta_data"`
+	Data     []ResponseBooking `json:"data"`
+}
+
+type BookingUseCase interface {
+	PreviewBooking(ctx context.Context, showtimeID int, user AuthUser, request RequestBooking) (response ResponseBookingPreview, err error)
+	PostBooking(ctx context.Context, showtimeID int, user AuthUser, request RequestBooking) (response ResponseBooking, err error)
+	GetAllBooking(ctx context.Context, user AuthUser, request RequestParamBooking) (response ResponseGetAllBooking, err error)
+	GetDetailBooking(ctx context.Context, id int, user AuthUser) (response ResponseBooking, err error)
+	CancelBooking(ctx context.Context, id int, user AuthUser) (err error)
+	HandlePaymentWebhook(ctx context.Context, provider string, header func(key string) string, body []byte) (err error)
+	ExpireBooking(ctx context.Context) (err error)
+}
+
+type BookingMySQLRepo interface {
+	PostBooking(ctx context.Context, userID int, showtimeID int, seats []ResponseBookingSeat, promos []ResponsePromoResult, timeout int) (id int, err error)
+	UpdateBookingPayment(ctx context.Context, id int, provider string, payment ResponsePayment) (err error)
+	UpdateBookingStatus(ctx context.Context, id int, from string, to string, event *PaymentEvent) (err error)
+	PostPaymentEvent(ctx context.Context, bookingID int, event PaymentEvent) (err error)
+	CountDataBooking(ctx context.Context, request RequestParamBooking) (response MetaData, err error)
+	GetAllBooking(ctx context.Context, request RequestParamBooking) (response []ResponseBooking, err error)
+	GetDetailBooking(ctx context.Context, id int) (response ResponseBooking, err error)
+	GetBookingByPayment(ctx context.Context, provider string, reference string) (response ResponseBooking, err error)
+	GetExpiredBooking(ctx context.Context) (response []int, err error)
+	GetBookingSeat(ctx context.Context, bookingIDs []int) (response map[int][]ResponseBookingSeat, err error)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CalendarEvent is an event of an iCalendar feed, UID stays the same across updates so
+// subscribed calendars replace the event instead of adding it again
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Modified    time.Time
+}
+
+type Calendar struct {
+	Name   string
+	Events []CalendarEvent
+}
+
+type CalendarUseCase interface {
+	GetMovieCalendar(ctx context.Context, movieID int) (response Calendar, err error)
+	GetTheaterCalendar(ctx context.Context, theaterID int) (response Calendar, err error)
+	GetReleaseCalendar(ctx context.Context) (response Calendar, err error)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	CollectionTypeFranchise = "franchise"
+	CollectionTypeCurated   = "curated"
+)
+
+type RequestCollection struct {
+	Name        string `json:"name" form:"name"`
+	Type        string `json:"type" form:"type"`
+	Description string `json:"description" form:"description"`
+	MovieIDs    []int  `json:"movie_ids" form:"movie_ids"`
+}
+
+type RequestCollectionMovie struct {
+	MovieID  int  `json:"movie_id" form:"movie_id"`
+	Position *int `json:"position" form:"position"`
+}
+
+type ResponseCollection struct {
+	ID          uint                      `json:"id"`
+	Name        string                    `json:"name"`
+	Type        string                    `json:"type"`
+	Description string                    `json:"description"`
+	Movies      []ResponseCollectionMovie `json:"movies,omitempty"`
+	DtmCrt      string                    `json:"dtm_crt"`
+	DtmUpd      string                    `json:"dtm_upd"`
+}
+
+type ResponseCollectionMovie struct {
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Rating   float64 `json:"rating"`
+	Image    string  `json:"image"`
+	Position int     `json:"position"`
+}
+
+// ResponseMovieCollection is a collection embedded in the movie detail
+type ResponseMovieCollection struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Position int    `json:"position"`
+}
+
+type RequestParamCollection struct {
+	Page  *int    `json:"page"`
+	Limit *int    `json:"limit"`
+	Type  *string `json:"type"`
+}
+
+type ResponseGetAllCollection struct {
+	MetaData MetaData             `json:"meta_data"`
+	Data     []ResponseCollection `json:"data"`
+}
+
+type CollectionUseCase interface {
+	PostCollection(ctx context.Context, request RequestCollection) (id int, err error)
+	GetAllCollection(ctx context.Context, request RequestParamCollection) (response ResponseGetAllCollection, err error)
+	GetDetailCollection(ctx context.Context, id int) (response ResponseCollection, err error)
+	UpdateCollection(ctx context.Context, id int, request RequestCollection) (err error)
+	DeleteCollection(ctx context.Context, id int) (err error)
+	AddCollectionMovie(ctx context.Context, id int, request RequestCollectionMovie) (err error)
+	DeleteCollectionMovie(ctx context.Context, id int, movieID int) (err error)
+}
+
+type CollectionMySQLRepo interface {
+	PostCollection(ctx context.Context, request RequestCollection) (id int, err error)
+	CountDataCollection(ctx context.Context, request RequestParamCollection) (response MetaData, err error)
+	GetAllCollection(ctx context.Context, request RequestParamCollection) (response []ResponseCollection, err error)
+	GetDetailCollection(ctx context.Context, id int) (response ResponseCollection, err error)
+	UpdateCollection(ctx context.Context, id int, request RequestCollection) (err error)
+	DeleteCollection(ctx context.Context, id int) (err error)
+	GetCollectionMovie(ctx context.Context, id int, published bool) (response []ResponseCollectionMovie, err error)
+	AddCollectionMovie(ctx context.Context, id int, movieID int, position int) (err error)
+	DeleteCollectionMovie(ctx context.Context, id int, movieID int) (err error)
+	GetCollectionByMovie(ctx context.Context, movieID int) (response []ResponseMovieCollection, err error)
+}
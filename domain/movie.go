@@ -2,26 +2,86 @@ package domain
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
+	"time"
 )
 
+const (
+	TrendingWindowDay  = "day"
+	TrendingWindowWeek = "week"
+
+	// MovieOrderPopularity is the order value sorting movies by time decayed popularity
+	MovieOrderPopularity = "popularity"
+	// MovieOrderAsc and MovieOrderDesc are the order values sorting movies by ID
+	MovieOrderAsc  = "asc"
+	MovieOrderDesc = "desc"
+
+	// ReleaseDateLayout is the layout of the movie release date
+	ReleaseDateLayout = "2006-01-02"
+	// PublishAtLayout is the layout of the movie publish time
+	PublishAtLayout = "2006-01-02 15:04:05"
+
+	MovieStatusDraft     = "draft"
+	MovieStatusReview    = "review"
+	MovieStatusPublished = "published"
+	MovieStatusArchived  = "archived"
+)
+
+// MovieTransition lists the statuses a movie moves to from each status, only published movies are public.
+// A movie in review having a publish time is published by the scheduler once the time is reached
+var MovieTransition = map[string][]string{
+	MovieStatusDraft:     {MovieStatusReview, MovieStatusArchived},
+	MovieStatusReview:    {MovieStatusDraft, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusPublished: {MovieStatusArchived},
+	MovieStatusArchived:  {MovieStatusDraft},
+}
+
 type RequestMovie struct {
 	Title       string               `json:"title" form:"title"`
 	Description string               `json:"description" form:"description"`
 	Rating      string               `json:"rating" form:"rating"`
+	Runtime     int                  `json:"runtime" form:"runtime"`
+	ReleaseDate string               `json:"release_date" form:"release_date"`
 	Image       multipart.FileHeader `json:"gambar" form:"gambar"`
 	ImagePath   string               `json:"image_path"`
 	FloatRating float64              `json:"float_rating"`
+
+	// User is the authenticated user making the change, recorded by the movie revision
+	User *AuthUser `json:"-" form:"-"`
+}
+
+// RequestMovieStatus moves a movie to another status, PublishAt schedules the publication of a movie in review
+type RequestMovieStatus struct {
+	Status    string  `json:"status" form:"status"`
+	PublishAt *string `json:"publish_at" form:"publish_at"`
+
+	User *AuthUser `json:"-" form:"-"`
 }
 
 type ResponseMovie struct {
-	ID          uint    `json:"id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Rating      float64 `json:"rating"`
-	Image       string  `json:"image"`
-	DtmCrt      string  `json:"dtm_crt"`
-	DtmUpd      string  `json:"dtm_upd"`
+	ID          uint                      `json:"id"`
+	ExternalID  *string                   `json:"external_id"`
+	Title       string                    `json:"title"`
+	Description string                    `json:"description"`
+	Rating      float64                   `json:"rating"`
+	Runtime     int                       `json:"runtime"`
+	ReleaseDate *string                   `json:"release_date"`
+	Status      string                    `json:"status"`
+	PublishAt   *string                   `json:"publish_at"`
+	Image       string                    `json:"image"`
+	Collections []ResponseMovieCollection `json:"collections,omitempty"`
+	Tags        []ResponseTag             `json:"tags,omitempty"`
+	Credits     []ResponseMovieCredit     `json:"credits,omitempty"`
+	UserRating  *ResponseRatingAggregate  `json:"user_rating,omitempty"`
+	InWatchlist *bool                     `json:"in_watchlist,omitempty"`
+	IsFavorite  *bool                     `json:"is_favorite,omitempty"`
+	ListCount   *uint                     `json:"list_count,omitempty"`
+	ViewCount   uint64                    `json:"view_count"`
+	Popularity  float64                   `json:"popularity"`
+	Trending    *float64                  `json:"trending_score,omitempty"`
+	DtmCrt      string                    `json:"dtm_crt"`
+	DtmUpd      string                    `json:"dtm_upd"`
 }
 
 type RequestParamMovie struct {
@@ -29,6 +89,21 @@ type RequestParamMovie struct {
 	Limit  *int    `json:"limit"`
 	Order  *string `json:"order"`
 	Search *string `json:"search"`
+
+	// Status is only honored for editors, other users always list the published movies
+	Status *string `json:"status"`
+
+	Collection *int    `json:"collection"`
+	Tag        *string `json:"tag"`
+
+	// AvailableOn is a watch provider slug, Region a country code, together they keep the movies
+	// currently available on the provider in the region
+	AvailableOn *string `json:"available_on"`
+	Region      *string `json:"region"`
+
+	// User is the authenticated user, UserMovie limits the list to the user watchlist or favorites
+	User      *AuthUser        `json:"-"`
+	UserMovie *UserMovieFilter `json:"-"`
 }
 
 type ResponseGetAllMovie struct {
@@ -45,21 +120,63 @@ type MetaData struct {
 	Order     string `json:"order"`
 }
 
+// MovieScore is a movie ranked by a score
+type MovieScore struct {
+	MovieID int
+	Score   float64
+}
+
 type MovieUseCase interface {
 	PostMovie(ctx context.Context, request RequestMovie) error
 	GetAllMovie(ctx context.Context, request RequestParamMovie) (response ResponseGetAllMovie, err error)
-	DeleteMovie(ctx context.Context, id int) (err error)
+	DeleteMovie(ctx context.Context, id int, user *AuthUser) (err error)
 	UpdateMovie(ctx context.Context, id int, request RequestMovie) (err error)
-	GetDetailMovie(ctx context.Context, id int) (response ResponseMovie, err error)
+	GetDetailMovie(ctx context.Context, id int, user *AuthUser, viewer string) (response ResponseMovie, err error)
+	GetTrendingMovie(ctx context.Context, window string, limit int) (response []ResponseMovie, err error)
+	FlushMovieView(ctx context.Context) (err error)
+	UpdateMovieStatus(ctx context.Context, id int, request RequestMovieStatus) (response ResponseMovie, err error)
+	PublishScheduledMovie(ctx context.Context) (err error)
+	GetAllMovieRevision(ctx context.Context, request RequestParamMovieRevision) (response ResponseGetAllMovieRevision, err error)
+	RestoreMovieRevision(ctx context.Context, movieID int, revision int, user *AuthUser) (response ResponseMovie, err error)
+	ImportMovie(ctx context.Context, request RequestMovieImport) (response ResponseMovieImport, err error)
+	ExportMovie(ctx context.Context, request RequestParamMovie, format string, w io.Writer) (err error)
+	// ImportDumpMovie maps a TMDB or OMDb entry onto the movie tables, an invalid entry is a failed row
+	// and only a storage failure is returned as an error
+	ImportDumpMovie(ctx context.Context, request RequestMovieDump) (response ResponseMovieImportRow, err error)
+	BatchMovie(ctx context.Context, request RequestMovieBatch) (response ResponseMovieBatch, err error)
 }
 
 type MovieMySQLRepo interface {
 	PostMovie(ctx context.Context, request RequestMovie) error
 	CountDataMovie(ctx context.Context, request RequestParamMovie) (response MetaData, err error)
 	GetAllMovie(ctx context.Context, request RequestParamMovie) (response []ResponseMovie, err error)
-	DeleteMovie(ctx context.Context, id int) (err error)
+	DeleteMovie(ctx context.Context, id int, user *AuthUser) (err error)
 	UpdateMovie(ctx context.Context, id int, request RequestMovie) (err error)
 	GetDetailMovie(ctx context.Context, id int) (response ResponseMovie, err error)
+	GetMovieByIDs(ctx context.Context, ids []int) (response map[int]ResponseMovie, err error)
+	GetMovieByRelease(ctx context.Context, from string, to string) (response []ResponseMovie, err error)
+	UpdateMovieView(ctx context.Context, flushID string, views map[int]int64, popularity []MovieScore) (err error)
+	UpdateMovieStatus(ctx context.Context, id int, from string, to string, publishAt *string, user *AuthUser) (err error)
+	PublishScheduledMovie(ctx context.Context) (published int64, err error)
+	CountDataMovieRevision(ctx context.Context, request RequestParamMovieRevision) (response MetaData, err error)
+	GetAllMovieRevision(ctx context.Context, request RequestParamMovieRevision) (response []ResponseMovieRevision, err error)
+	GetMovieRevision(ctx context.Context, movieID int, revision int) (response ResponseMovieRevision, err error)
+	RestoreMovie(ctx context.Context, id int, snapshot MovieSnapshot, user *AuthUser) (err error)
+	ImportMovie(ctx context.Context, movies []MovieImport, atomic bool, user *AuthUser) (response []ResponseMovieImportRow, err error)
+	// EachMovie calls fn with every movie matching the list filters without loading them all
+	EachMovie(ctx context.Context, request RequestParamMovie, fn func(ResponseMovie) error) (err error)
+	ImportDumpMovie(ctx context.Context, movie MovieDump, user *AuthUser) (id int, created bool, err error)
+	GetMovieCredit(ctx context.Context, movieID int) (response []ResponseMovieCredit, err error)
+	BatchMovie(ctx context.Context, operations []MovieBatchOperation, atomic bool, user *AuthUser) (response []ResponseMovieBatchResult, err error)
+}
+
+type MovieRedisRepo interface {
+	RecordView(ctx context.Context, movieID int, viewer string) (err error)
+	GetTrendingScore(ctx context.Context, window string, limit int) (response []MovieScore, err error)
+	PopPendingView(ctx context.Context) (flushID string, response map[int]int64, err error)
+	DeleteFlushedView(ctx context.Context) (err error)
+	LockViewFlush(ctx context.Context, owner string, ttl time.Duration) (ok bool, err error)
+	UnlockViewFlush(ctx context.Context, owner string) (err error)
 }
 
 type MovieGRPCRepo interface {
@@ -0,0 +1,62 @@
+package domain
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+
+	// BatchStatusDeleted is the status of a delete operation done, the other operations are reported with the
+	// import statuses
+	BatchStatusDeleted = "deleted"
+)
+
+// RequestMovieBatchOperation creates, updates or deletes the movie ID. An update only changes the fields
+// sent, Image is an image URL downloaded by the server
+type RequestMovieBatchOperation struct {
+	Op          string   `json:"op"`
+	ID          int      `json:"id"`
+	Title       *string  `json:"title"`
+	Description *string  `json:"description"`
+	Rating      *float64 `json:"rating"`
+	Runtime     *int     `json:"runtime"`
+	ReleaseDate *string  `json:"release_date"`
+	Image       *string  `json:"image"`
+}
+
+// RequestMovieBatch runs the operations in order, in one transaction with ImportModeTransaction or each
+// on its own with ImportModeBestEffort
+type RequestMovieBatch struct {
+	Mode       string                       `json:"mode"`
+	Operations []RequestMovieBatchOperation `json:"operations"`
+
+	User *AuthUser `json:"-"`
+}
+
+// MovieBatchOperation is a validated operation written by the batch, Index is its position in the request
+type MovieBatchOperation struct {
+	Index   int
+	Op      string
+	ID      int
+	Request RequestMovie
+}
+
+type ResponseMovieBatchResult struct {
+	Index   int      `json:"index"`
+	Op      string   `json:"op"`
+	MovieID *int     `json:"movie_id,omitempty"`
+	Status  string   `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// ResponseMovieBatch reports a batch, FailedIndex is the first failed operation of a rejected transaction
+type ResponseMovieBatch struct {
+	Mode        string                     `json:"mode"`
+	Committed   bool                       `json:"committed"`
+	FailedIndex *int                       `json:"failed_index,omitempty"`
+	Total       int                        `json:"total"`
+	Created     int                        `json:"created"`
+	Updated     int                        `json:"updated"`
+	Deleted     int                        `json:"deleted"`
+	Failed      int                        `json:"failed"`
+	Results     []ResponseMovieBatchResult `json:"results"`
+}
@@ -0,0 +1,121 @@
+package domain
+
+const (
+	// DumpFormatTMDB is a movie of /movie/{id} with credits, release_dates and images appended,
+	// DumpFormatOMDb a movie of the OMDb API. The format of an entry is told by its fields
+	DumpFormatTMDB = "tmdb"
+	DumpFormatOMDb = "omdb"
+
+	CreditKindCast = "cast"
+	CreditKindCrew = "crew"
+
+	// TMDBReleaseTheatrical is the TMDB release type of a theatrical release
+	TMDBReleaseTheatrical = 3
+)
+
+type TMDBGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TMDBCast struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	Order     int    `json:"order"`
+}
+
+type TMDBCrew struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Job        string `json:"job"`
+	Department string `json:"department"`
+}
+
+type TMDBReleaseDate struct {
+	ReleaseDate   string `json:"release_date"`
+	Type          int    `json:"type"`
+	Certification string `json:"certification"`
+}
+
+type TMDBCountryRelease struct {
+	Country      string            `json:"iso_3166_1"`
+	ReleaseDates []TMDBReleaseDate `json:"release_dates"`
+}
+
+type TMDBImage struct {
+	FilePath    string  `json:"file_path"`
+	VoteAverage float64 `json:"vote_average"`
+}
+
+type TMDBMovie struct {
+	ID          int         `json:"id"`
+	IMDbID      string      `json:"imdb_id"`
+	Title       string      `json:"title"`
+	Overview    string      `json:"overview"`
+	VoteAverage float64     `json:"vote_average"`
+	Runtime     int         `json:"runtime"`
+	ReleaseDate string      `json:"release_date"`
+	PosterPath  string      `json:"poster_path"`
+	Genres      []TMDBGenre `json:"genres"`
+	Credits     struct {
+		Cast []TMDBCast `json:"cast"`
+		Crew []TMDBCrew `json:"crew"`
+	} `json:"credits"`
+	ReleaseDates struct {
+		Results []TMDBCountryRelease `json:"results"`
+	} `json:"release_dates"`
+	Images struct {
+		Posters []TMDBImage `json:"posters"`
+	} `json:"images"`
+}
+
+// OMDbMovie is a movie of the OMDb API, every value is a string and a missing value is N/A
+type OMDbMovie struct {
+	IMDbID     string `json:"imdbID"`
+	Title      string `json:"Title"`
+	Plot       string `json:"Plot"`
+	IMDbRating string `json:"imdbRating"`
+	Runtime    string `json:"Runtime"`
+	Released   string `json:"Released"`
+	Genre      string `json:"Genre"`
+	Director   string `json:"Director"`
+	Writer     string `json:"Writer"`
+	Actors     string `json:"Actors"`
+	Poster     string `json:"Poster"`
+}
+
+// RequestMovieCredit is a person credited for a movie, Role is the character of the cast or the job of the crew.
+// The person is found by ExternalID
+type RequestMovieCredit struct {
+	ExternalID string
+	Name       string
+	Kind       string
+	Role       string
+	Department string
+	Position   int
+}
+
+type ResponseMovieCredit struct {
+	PersonID   uint   `json:"person_id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Role       string `json:"role"`
+	Department string `json:"department,omitempty"`
+	Position   int    `json:"position"`
+}
+
+// MovieDump is a movie of a dump mapped onto the movie tables. Tags are added to the movie, the credits
+// replace the credits of the movie
+type MovieDump struct {
+	Movie   MovieImport
+	Tags    []RequestTag
+	Credits []RequestMovieCredit
+}
+
+// RequestMovieDump is an entry of a dump, Images is a directory holding the posters by file name
+type RequestMovieDump struct {
+	Entry  []byte
+	Images string
+	User   *AuthUser
+}
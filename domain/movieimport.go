@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"archive/zip"
+	"io"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatJSON   = "json"
+	ImportFormatNDJSON = "ndjson"
+	// ExportFormatXLSX is only written by the export
+	ExportFormatXLSX = "xlsx"
+
+	// ImportModeTransaction writes every row or none, ImportModeBestEffort writes the valid rows
+	ImportModeTransaction = "transaction"
+	ImportModeBestEffort  = "best_effort"
+
+	ImportStatusCreated = "created"
+	ImportStatusUpdated = "updated"
+	ImportStatusFailed  = "failed"
+	// ImportStatusSkipped is a valid row not written because the transaction was rolled back
+	ImportStatusSkipped = "skipped"
+)
+
+// Record is a row read from an import file, Row is its line in a CSV or NDJSON file and its position
+// from 1 in a JSON array. Field names are lower case
+type Record struct {
+	Row    int
+	Fields map[string]string
+}
+
+// RequestMovieImport is a movie file in Format with the images it refers to by path, Images is nil
+// when no ZIP is sent. Image URLs are downloaded
+type RequestMovieImport struct {
+	File   io.Reader
+	Format string
+	Mode   string
+	Images *zip.Reader
+	User   *AuthUser
+}
+
+// MovieImport is a validated row written by the import, a movie with the same ExternalID is updated
+// and an empty ExternalID always creates a movie
+type MovieImport struct {
+	Row        int
+	ExternalID string
+	Request    RequestMovie
+}
+
+type ResponseMovieImportRow struct {
+	Row        int      `json:"row"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Status     string   `json:"status"`
+	MovieID    *int     `json:"movie_id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+type ResponseMovieImport struct {
+	Mode      string                   `json:"mode"`
+	Committed bool                     `json:"committed"`
+	Total     int                      `json:"total"`
+	Created   int                      `json:"created"`
+	Updated   int                      `json:"updated"`
+	Failed    int                      `json:"failed"`
+	Rows      []ResponseMovieImportRow `json:"rows"`
+}
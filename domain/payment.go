@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	PaymentEventPaid     = "payment.paid"
+	PaymentEventFailed   = "payment.failed"
+	PaymentEventRefunded = "payment.refunded"
+)
+
+type RequestPayment struct {
+	BookingID   int
+	Amount      int64
+	Description string
+}
+
+type ResponsePayment struct {
+	Reference  string
+	PaymentURL string
+}
+
+// PaymentEvent is a verified webhook notification, Reference is the provider payment reference
+type PaymentEvent struct {
+	Provider  string `json:"-"`
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+}
+
+// PaymentProvider is a payment gateway, header reads a request header of the webhook
+type PaymentProvider interface {
+	Name() string
+	CreatePayment(ctx context.Context, request RequestPayment) (response ResponsePayment, err error)
+	Refund(ctx context.Context, reference string, amount int64) (err error)
+	VerifyWebhook(header func(key string) string, body []byte) (event PaymentEvent, err error)
+}
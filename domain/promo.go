@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	PromoTypePercentage = "percentage"
+	PromoTypeFixed      = "fixed"
+
+	// PromoLayout is the layout of the promo validity window, in the theater local time
+	PromoLayout = "2006-01-02 15:04:05"
+)
+
+// RequestPromo is a promo code, DiscountValue is a percentage for percentage promos and an amount
+// for fixed promos. MovieID and TheaterID scope the promo, nil applies to every movie or theater
+type RequestPromo struct {
+	Code          string  `json:"code" form:"code"`
+	Description   string  `json:"description" form:"description"`
+	DiscountType  string  `json:"discount_type" form:"discount_type"`
+	DiscountValue int64   `json:"discount_value" form:"discount_value"`
+	MaxDiscount   *int64  `json:"max_discount" form:"max_discount"`
+	MovieID       *int    `json:"movie_id" form:"movie_id"`
+	TheaterID     *int    `json:"theater_id" form:"theater_id"`
+	StartsAt      *string `json:"starts_at" form:"starts_at"`
+	EndsAt        *string `json:"ends_at" form:"ends_at"`
+	UsageLimit    *int    `json:"usage_limit" form:"usage_limit"`
+	PerUserLimit  *int    `json:"per_user_limit" form:"per_user_limit"`
+	Priority      int     `json:"priority" form:"priority"`
+	Stackable     bool    `json:"stackable" form:"stackable"`
+	Active        *bool   `json:"active" form:"active"`
+}
+
+type ResponsePromo struct {
+	ID            uint    `json:"id"`
+	Code          string  `json:"code"`
+	Description   string  `json:"description"`
+	DiscountType  string  `json:"discount_type"`
+	DiscountValue int64   `json:"discount_value"`
+	MaxDiscount   *int64  `json:"max_discount"`
+	MovieID       *int    `json:"movie_id"`
+	TheaterID     *int    `json:"theater_id"`
+	StartsAt      *string `json:"starts_at"`
+	EndsAt        *string `json:"ends_at"`
+	UsageLimit    *int    `json:"usage_limit"`
+	PerUserLimit  *int    `json:"per_user_limit"`
+	UsedCount     int     `json:"used_count"`
+	Priority      int     `json:"priority"`
+	Stackable     bool    `json:"stackable"`
+	Active        bool    `json:"active"`
+	DtmCrt        string  `json:"dtm_crt"`
+	DtmUpd        string  `json:"dtm_upd"`
+}
+
+type RequestParamPromo struct {
+	Page   *int    `json:"page"`
+	Limit  *int    `json:"limit"`
+	Search *string `json:"search"`
+}
+
+type ResponseGetAllPromo struct {
+	MetaData MetaData        `json:"meta_data"`
+	Data     []ResponsePromo `json:"data"`
+}
+
+// RequestApplyPromo evaluates promo codes against an amount of a showtime for a user
+type RequestApplyPromo struct {
+	UserID    int
+	MovieID   int
+	TheaterID int
+	Amount    int64
+	Codes     []string
+}
+
+// ResponsePromoResult tells whether a requested code applies, Reason explains a code not applied
+type ResponsePromoResult struct {
+	PromoID      uint   `json:"-"`
+	PerUserLimit *int   `json:"-"`
+	Code         string `json:"code"`
+	Applied      bool   `json:"applied"`
+	Discount     int64  `json:"discount"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+type ResponseApplyPromo struct {
+	Discount int64                 `json:"discount"`
+	Promos   []ResponsePromoResult `json:"promos"`
+}
+
+type PromoUseCase interface {
+	PostPromo(ctx context.Context, request RequestPromo) (id int, err error)
+	GetAllPromo(ctx context.Context, request RequestParamPromo) (response ResponseGetAllPromo, err error)
+	GetDetailPromo(ctx context.Context, id int) (response ResponsePromo, err error)
+	UpdatePromo(ctx context.Context, id int, request RequestPromo) (err error)
+	DeletePromo(ctx context.Context, id int) (err error)
+	ApplyPromo(ctx context.Context, request RequestApplyPromo) (response ResponseApplyPromo, err error)
+}
+
+type PromoMySQLRepo interface {
+	PostPromo(ctx context.Context, request RequestPromo) (id int, err error)
+	CountDataPromo(ctx context.Context, request RequestParamPromo) (response MetaData, err error)
+	GetAllPromo(ctx context.Context, request RequestParamPromo) (response []ResponsePromo, err error)
+	GetDetailPromo(ctx context.Context, id int) (response ResponsePromo, err error)
+	GetPromoByCode(ctx context.Context, codes []string) (response []ResponsePromo, err error)
+	CountUserUsage(ctx context.Context, promoIDs []int, userID int) (response map[int]int, err error)
+	UpdatePromo(ctx context.Context, id int, request RequestPromo) (err error)
+	DeletePromo(ctx context.Context, id int) (err error)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	InteractionReview    = "review"
+	InteractionWatchlist = "watchlist"
+	InteractionFavorite  = "favorite"
+
+	RecommendationReasonSimilar = "similar"
+	RecommendationReasonPopular = "popular"
+)
+
+// Interaction is a signal of a user about a movie, Score is only set for reviews
+type Interaction struct {
+	UserID  int
+	MovieID int
+	Type    string
+	Score   int
+}
+
+type ResponseRecommendation struct {
+	ID     uint    `json:"id"`
+	Title  string  `json:"title"`
+	Rating float64 `json:"rating"`
+	Image  string  `json:"image"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+type RecommendationUseCase interface {
+	GetRecommendation(ctx context.Context, user AuthUser, limit int) (response []ResponseRecommendation, err error)
+	ComputeItemSimilarity(ctx context.Context) (err error)
+}
+
+type RecommendationMySQLRepo interface {
+	GetAllInteraction(ctx context.Context) (response []Interaction, err error)
+	GetUserInteraction(ctx context.Context, userID int) (response []Interaction, err error)
+	ReplaceItemSimilarity(ctx context.Context, scores map[int][]SimilarScore) (err error)
+	GetItemNeighbor(ctx context.Context, movieIDs []int) (response map[int][]SimilarScore, err error)
+	GetRecommendedMovie(ctx context.Context, movieIDs []int) (response map[int]ResponseRecommendation, err error)
+	GetPopularMovie(ctx context.Context, excludeIDs []int, limit int) (response []ResponseRecommendation, err error)
+}
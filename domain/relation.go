@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+)
+
+// Relation types accepted when linking movies, prequel_of is stored as the inverse sequel_of
+const (
+	RelationSequelOf  = "sequel_of"
+	RelationPrequelOf = "prequel_of"
+	RelationRemakeOf  = "remake_of"
+	RelationSpinOffOf = "spin_off_of"
+
+	// Inferred only, read from the other side of a stored relation
+	RelationRemadeAs  = "remade_as"
+	RelationSpunOffAs = "spun_off_as"
+)
+
+// RelationInverse maps every relation type to the type seen from the related movie
+var RelationInverse = map[string]string{
+	RelationSequelOf:  RelationPrequelOf,
+	RelationPrequelOf: RelationSequelOf,
+	RelationRemakeOf:  RelationRemadeAs,
+	RelationRemadeAs:  RelationRemakeOf,
+	RelationSpinOffOf: RelationSpunOffAs,
+	RelationSpunOffAs: RelationSpinOffOf,
+}
+
+type RequestRelation struct {
+	RelatedMovieID int    `json:"related_movie_id" form:"related_movie_id"`
+	Type           string `json:"type" form:"type"`
+}
+
+type ResponseRelatedMovie struct {
+	ID     uint    `json:"id"`
+	Title  string  `json:"title"`
+	Rating float64 `json:"rating"`
+	Image  string  `json:"image"`
+}
+
+// ResponseMovieRelation is the related movies grouped by relation type seen from the requested movie
+type ResponseMovieRelation map[string][]ResponseRelatedMovie
+
+// MovieRelation is a stored relation read as "MovieID <Type> RelatedMovieID", RelatedMovie is the other movie
+type MovieRelation struct {
+	MovieID        int
+	RelatedMovieID int
+	Type           string
+	RelatedMovie   ResponseRelatedMovie
+}
+
+type RelationUseCase interface {
+	PostRelation(ctx context.Context, id int, request RequestRelation) (err error)
+	DeleteRelation(ctx context.Context, id int, request RequestRelation) (err error)
+	GetRelatedMovie(ctx context.Context, id int) (response ResponseMovieRelation, err error)
+}
+
+type RelationMySQLRepo interface {
+	PostRelation(ctx context.Context, relation MovieRelation) (err error)
+	DeleteRelation(ctx context.Context, relation MovieRelation) (err error)
+	GetRelationByMovie(ctx context.Context, id int) (response []MovieRelation, err error)
+	GetRelationTarget(ctx context.Context, id int, relationType string) (response []int, err error)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	ReviewScoreMin = 1
+	ReviewScoreMax = 10
+)
+
+// Only approved reviews are public and counted in the rating aggregate
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+type RequestReview struct {
+	Score      int    `json:"score" form:"score"`
+	Text       string `json:"text" form:"text"`
+	Status     string `json:"-"`
+	FlagReason string `json:"-"`
+}
+
+type RequestModerateReview struct {
+	Status string `json:"status" form:"status"`
+	Note   string `json:"note" form:"note"`
+}
+
+type RequestReportReview struct {
+	Reason string `json:"reason" form:"reason"`
+}
+
+type ResponseReview struct {
+	ID      uint   `json:"id"`
+	MovieID uint   `json:"movie_id"`
+	UserID  uint   `json:"user_id"`
+	Score   int    `json:"score"`
+	Text    string `json:"text"`
+	Status  string `json:"status"`
+	DtmCrt  string `json:"dtm_crt"`
+	DtmUpd  string `json:"dtm_upd"`
+
+	// Moderation detail, only shown in the moderation queue
+	FlagReason     string `json:"flag_reason,omitempty"`
+	ReportCount    uint   `json:"report_count,omitempty"`
+	ModerationNote string `json:"moderation_note,omitempty"`
+}
+
+// RequestParamReview lists reviews of MovieID, a zero MovieID lists every movie for the moderation queue
+type RequestParamReview struct {
+	MovieID int     `json:"movie_id"`
+	Page    *int    `json:"page"`
+	Limit   *int    `json:"limit"`
+	Status  *string `json:"status"`
+}
+
+type ResponseGetAllReview struct {
+	MetaData MetaData         `json:"meta_data"`
+	Data     []ResponseReview `json:"data"`
+}
+
+// RatingAggregate is the incrementally maintained review counter of a movie
+type RatingAggregate struct {
+	MovieID   int
+	Count     uint
+	Sum       uint
+	Histogram [ReviewScoreMax]uint
+}
+
+// ResponseRatingAggregate is the computed user rating shown next to the editorial rating
+type ResponseRatingAggregate struct {
+	Mean          float64      `json:"mean"`
+	Count         uint         `json:"count"`
+	Histogram     map[int]uint `json:"histogram"`
+	WeightedScore float64      `json:"weighted_score"`
+}
+
+type ReviewUseCase interface {
+	PostReview(ctx context.Context, movieID int, user AuthUser, request RequestReview) (err error)
+	UpdateReview(ctx context.Context, movieID int, user AuthUser, request RequestReview) (err error)
+	DeleteReview(ctx context.Context, movieID int, user AuthUser) (err error)
+	GetAllReview(ctx context.Context, request RequestParamReview) (response ResponseGetAllReview, err error)
+	GetRating(ctx context.Context, movieID int) (response ResponseRatingAggregate, err error)
+	GetModerationQueue(ctx context.Context, request RequestParamReview) (response ResponseGetAllReview, err error)
+	ModerateReview(ctx context.Context, id int, moderator AuthUser, request RequestModerateReview) (err error)
+	ReportReview(ctx context.Context, id int, user AuthUser, request RequestReportReview) (err error)
+}
+
+type ReviewMySQLRepo interface {
+	PostReview(ctx context.Context, movieID int, userID int, request RequestReview) (err error)
+	UpdateReview(ctx context.Context, review ResponseReview, request RequestReview) (err error)
+	DeleteReview(ctx context.Context, review ResponseReview) (err error)
+	GetReviewByUser(ctx context.Context, movieID int, userID int) (response ResponseReview, err error)
+	GetDetailReview(ctx context.Context, id int) (response ResponseReview, err error)
+	UpdateReviewStatus(ctx context.Context, review ResponseReview, moderatorID int, request RequestModerateReview) (err error)
+	PostReviewReport(ctx context.Context, review ResponseReview, userID int, request RequestReportReview, threshold int) (err error)
+	CountDataReview(ctx context.Context, request RequestParamReview) (response MetaData, err error)
+	GetAllReview(ctx context.Context, request RequestParamReview) (response []ResponseReview, err error)
+	GetRatingAggregate(ctx context.Context, movieIDs []int) (response map[int]RatingAggregate, err error)
+	GetGlobalMean(ctx context.Context) (mean float64, count uint, err error)
+}
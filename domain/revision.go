@@ -0,0 +1,53 @@
+package domain
+
+const (
+	MovieRevisionCreate  = "create"
+	MovieRevisionUpdate  = "update"
+	MovieRevisionDelete  = "delete"
+	MovieRevisionStatus  = "status"
+	MovieRevisionPublish = "publish"
+	MovieRevisionRestore = "restore"
+)
+
+// MovieSnapshot is the content of a movie stored by every revision, a delete revision keeps the
+// content the movie had before it was deleted
+type MovieSnapshot struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Rating      float64 `json:"rating"`
+	Runtime     int     `json:"runtime"`
+	ReleaseDate *string `json:"release_date"`
+	Status      string  `json:"status"`
+	PublishAt   *string `json:"publish_at"`
+	Image       string  `json:"image"`
+}
+
+// MovieFieldChange is a field changed by a revision
+type MovieFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type ResponseMovieRevision struct {
+	ID       uint          `json:"id"`
+	MovieID  uint          `json:"movie_id"`
+	Revision int           `json:"revision"`
+	Action   string        `json:"action"`
+	UserID   *int          `json:"user_id"`
+	Snapshot MovieSnapshot `json:"snapshot"`
+	// Changes compares the revision with the previous one, every field is listed by the first revision
+	Changes []MovieFieldChange `json:"changes"`
+	DtmCrt  string             `json:"dtm_crt"`
+}
+
+type RequestParamMovieRevision struct {
+	MovieID int  `json:"movie_id"`
+	Page    *int `json:"page"`
+	Limit   *int `json:"limit"`
+}
+
+type ResponseGetAllMovieRevision struct {
+	MetaData MetaData                `json:"meta_data"`
+	Data     []ResponseMovieRevision `json:"data"`
+}
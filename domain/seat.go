@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	SeatTypeRegular    = "regular"
+	SeatTypeVIP        = "vip"
+	SeatTypeWheelchair = "wheelchair"
+
+	SeatStatusAvailable = "available"
+	SeatStatusHeld      = "held"
+	SeatStatusBooked    = "booked"
+)
+
+// RequestSeatLayout replaces the seats of an auditorium, each row is numbered from 1 to Count
+type RequestSeatLayout struct {
+	Rows []RequestSeatRow `json:"rows" form:"rows"`
+}
+
+type RequestSeatRow struct {
+	Row   string `json:"row" form:"row"`
+	Count int    `json:"count" form:"count"`
+	Type  string `json:"type" form:"type"`
+}
+
+type ResponseSeat struct {
+	ID           uint   `json:"id"`
+	AuditoriumID uint   `json:"auditorium_id"`
+	Row          string `json:"row"`
+	Number       int    `json:"number"`
+	Type         string `json:"type"`
+}
+
+// ResponseShowtimeSeat is a seat of the showtime seat map
+type ResponseShowtimeSeat struct {
+	ID       uint   `json:"id"`
+	Row      string `json:"row"`
+	Number   int    `json:"number"`
+	Type     string `json:"type"`
+	Price    int64  `json:"price"`
+	Status   string `json:"status"`
+	HeldByMe bool   `json:"held_by_me,omitempty"`
+}
+
+type RequestSeatHold struct {
+	SeatIDs []int `json:"seat_ids" form:"seat_ids"`
+}
+
+type ResponseSeatHold struct {
+	ShowtimeID int    `json:"showtime_id"`
+	SeatIDs    []int  `json:"seat_ids"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type SeatUseCase interface {
+	SetSeatLayout(ctx context.Context, auditoriumID int, request RequestSeatLayout) (err error)
+	GetSeatLayout(ctx context.Context, auditoriumID int) (response []ResponseSeat, err error)
+	GetShowtimeSeat(ctx context.Context, showtimeID int, user *AuthUser) (response []ResponseShowtimeSeat, err error)
+	HoldSeat(ctx context.Context, showtimeID int, user AuthUser, request RequestSeatHold) (response ResponseSeatHold, err error)
+	ReleaseSeat(ctx context.Context, showtimeID int, user AuthUser) (err error)
+}
+
+type SeatMySQLRepo interface {
+	SetSeatLayout(ctx context.Context, auditoriumID int, request RequestSeatLayout) (err error)
+	GetSeatByAuditorium(ctx context.Context, auditoriumID int) (response []ResponseSeat, err error)
+	CountBookedSeatByAuditorium(ctx context.Context, auditoriumID int) (total int, err error)
+	GetBookedSeat(ctx context.Context, showtimeID int) (response []int, err error)
+	GetShowtimeAuditorium(ctx context.Context, showtimeID int) (auditoriumID int, open bool, err error)
+}
+
+// SeatRedisRepo keeps seat holds, owner identifies the holding user
+type SeatRedisRepo interface {
+	HoldSeat(ctx context.Context, showtimeID int, owner string, seatIDs []int, ttl time.Duration) (conflictSeatID int, err error)
+	CheckHold(ctx context.Context, showtimeID int, owner string, seatIDs []int) (missingSeatID int, err error)
+	ReleaseSeat(ctx context.Context, showtimeID int, owner string, seatIDs []int) (err error)
+	GetUserHold(ctx context.Context, showtimeID int, owner string) (response []int, err error)
+	GetHolder(ctx context.Context, showtimeID int, seatIDs []int) (response map[int]string, err error)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+)
+
+// ShowtimeLayout is the layout of showtime start and end time, in the theater local time
+const ShowtimeLayout = "2006-01-02 15:04:05"
+
+type RequestShowtime struct {
+	MovieID      int       `json:"movie_id" form:"movie_id"`
+	AuditoriumID int       `json:"auditorium_id" form:"auditorium_id"`
+	StartTime    string    `json:"start_time" form:"start_time"`
+	BasePrice    int64     `json:"base_price" form:"base_price"`
+	EndTime      string    `json:"-"`
+	User         *AuthUser `json:"-" form:"-"`
+}
+
+type ResponseShowtime struct {
+	ID             uint   `json:"id"`
+	MovieID        uint   `json:"movie_id"`
+	MovieTitle     string `json:"movie_title"`
+	AuditoriumID   uint   `json:"auditorium_id"`
+	AuditoriumName string `json:"auditorium_name"`
+	TheaterID      uint   `json:"theater_id"`
+	TheaterName    string `json:"theater_name"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	BasePrice      int64  `json:"base_price"`
+	DtmCrt         string `json:"dtm_crt"`
+	DtmUpd         string `json:"dtm_upd"`
+	MovieStatus    string `json:"-"`
+}
+
+// RequestParamShowtime filters showtimes, Date is a YYYY-MM-DD day of the start time, From and To
+// bound the start time, Upcoming leaves out showtimes already started and Published the showtimes of
+// unpublished movies
+type RequestParamShowtime struct {
+	Page       *int      `json:"page"`
+	Limit      *int      `json:"limit"`
+	MovieID    *int      `json:"movie_id"`
+	TheaterID  *int      `json:"theater_id"`
+	TheaterIDs []int     `json:"-"`
+	Date       *string   `json:"date"`
+	From       *string   `json:"-"`
+	To         *string   `json:"-"`
+	Upcoming   bool      `json:"-"`
+	Published  bool      `json:"-"`
+	User       *AuthUser `json:"-"`
+}
+
+// RequestParamNearbyShowtime searches upcoming showtimes of Date in the theaters near a point
+type RequestParamNearbyShowtime struct {
+	RequestParamNearby
+	MovieID *int      `json:"movie_id"`
+	Date    *string   `json:"date"`
+	User    *AuthUser `json:"-"`
+}
+
+// ResponseTheaterShowtime is a theater with its showtimes
+type ResponseTheaterShowtime struct {
+	Theater   ResponseTheater    `json:"theater"`
+	Showtimes []ResponseShowtime `json:"showtimes"`
+}
+
+type ResponseGetAllShowtime struct {
+	MetaData MetaData           `json:"meta_data"`
+	Data     []ResponseShowtime `json:"data"`
+}
+
+type ShowtimeUseCase interface {
+	PostShowtime(ctx context.Context, request RequestShowtime) (id int, err error)
+	GetAllShowtime(ctx context.Context, request RequestParamShowtime) (response ResponseGetAllShowtime, err error)
+	GetDetailShowtime(ctx context.Context, id int, user *AuthUser) (response ResponseShowtime, err error)
+	GetNearbyShowtime(ctx context.Context, request RequestParamNearbyShowtime) (response []ResponseTheaterShowtime, err error)
+	UpdateShowtime(ctx context.Context, id int, request RequestShowtime) (err error)
+	DeleteShowtime(ctx context.Context, id int) (err error)
+}
+
+type ShowtimeMySQLRepo interface {
+	PostShowtime(ctx context.Context, request RequestShowtime) (id int, err error)
+	CountDataShowtime(ctx context.Context, request RequestParamShowtime) (response MetaData, err error)
+	GetAllShowtime(ctx context.Context, request RequestParamShowtime) (response []ResponseShowtime, err error)
+	GetDetailShowtime(ctx context.Context, id int) (response ResponseShowtime, err error)
+	UpdateShowtime(ctx context.Context, id int, request RequestShowtime) (err error)
+	DeleteShowtime(ctx context.Context, id int) (err error)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+)
+
+// SimilarSource is the content of a movie used to compute similarity, the tags hold the genres
+type SimilarSource struct {
+	MovieID       int
+	Description   string
+	Status        string
+	TagIDs        []int
+	PersonIDs     []int
+	CollectionIDs []int
+}
+
+type SimilarScore struct {
+	SimilarMovieID int
+	Score          float64
+}
+
+type ResponseSimilarMovie struct {
+	ID     uint    `json:"id"`
+	Title  string  `json:"title"`
+	Rating float64 `json:"rating"`
+	Image  string  `json:"image"`
+	Score  float64 `json:"score"`
+}
+
+type SimilarUseCase interface {
+	GetSimilarMovie(ctx context.Context, id int, limit int) (response []ResponseSimilarMovie, err error)
+	ComputeSimilar(ctx context.Context) (err error)
+}
+
+type SimilarMySQLRepo interface {
+	GetSimilarSource(ctx context.Context) (response []SimilarSource, err error)
+	ReplaceSimilar(ctx context.Context, movieID int, scores []SimilarScore) (err error)
+	GetSimilarMovie(ctx context.Context, id int, limit int) (response []ResponseSimilarMovie, err error)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+)
+
+type RequestTag struct {
+	Name string `json:"name" form:"name"`
+	Slug string `json:"slug"`
+}
+
+type RequestMergeTag struct {
+	TargetID int `json:"target_id" form:"target_id"`
+}
+
+type RequestMovieTag struct {
+	Tags []string `json:"tags" form:"tags"`
+}
+
+type ResponseTag struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	TotalMovie *uint  `json:"total_movie,omitempty"`
+}
+
+type RequestParamTag struct {
+	Page   *int    `json:"page"`
+	Limit  *int    `json:"limit"`
+	Search *string `json:"search"`
+}
+
+type ResponseGetAllTag struct {
+	MetaData MetaData      `json:"meta_data"`
+	Data     []ResponseTag `json:"data"`
+}
+
+type TagUseCase interface {
+	PostTag(ctx context.Context, request RequestTag) (response ResponseTag, err error)
+	GetAllTag(ctx context.Context, request RequestParamTag) (response ResponseGetAllTag, err error)
+	GetCountTag(ctx context.Context, request RequestParamTag) (response ResponseGetAllTag, err error)
+	RenameTag(ctx context.Context, id int, request RequestTag) (err error)
+	MergeTag(ctx context.Context, id int, request RequestMergeTag) (err error)
+	DeleteTag(ctx context.Context, id int) (err error)
+	PostMovieTag(ctx context.Context, movieID int, request RequestMovieTag) (err error)
+	DeleteMovieTag(ctx context.Context, movieID int, tagID int) (err error)
+}
+
+type TagMySQLRepo interface {
+	PostTag(ctx context.Context, request RequestTag) (id int, err error)
+	CountDataTag(ctx context.Context, request RequestParamTag) (response MetaData, err error)
+	GetAllTag(ctx context.Context, request RequestParamTag, withCount bool) (response []ResponseTag, err error)
+	GetDetailTag(ctx context.Context, id int) (response ResponseTag, err error)
+	GetTagBySlug(ctx context.Context, slug string) (response ResponseTag, err error)
+	UpdateTag(ctx context.Context, id int, request RequestTag) (err error)
+	MergeTag(ctx context.Context, sourceID int, targetID int) (err error)
+	DeleteTag(ctx context.Context, id int) (err error)
+	PostMovieTag(ctx context.Context, movieID int, tagID int) (err error)
+	DeleteMovieTag(ctx context.Context, movieID int, tagID int) (err error)
+	GetTagByMovie(ctx context.Context, movieID int) (response []ResponseTag, err error)
+}
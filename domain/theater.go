@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+)
+
+// RequestTheater is a theater, Latitude and Longitude are set together or left out
+type RequestTheater struct {
+	Name      string   `json:"name" form:"name"`
+	Address   string   `json:"address" form:"address"`
+	City      string   `json:"city" form:"city"`
+	Latitude  *float64 `json:"latitude" form:"latitude"`
+	Longitude *float64 `json:"longitude" form:"longitude"`
+}
+
+type ResponseTheater struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Address     string               `json:"address"`
+	City        string               `json:"city"`
+	Latitude    *float64             `json:"latitude"`
+	Longitude   *float64             `json:"longitude"`
+	DistanceKm  *float64             `json:"distance_km,omitempty"`
+	Auditoriums []ResponseAuditorium `json:"auditoriums,omitempty"`
+	DtmCrt      string               `json:"dtm_crt"`
+	DtmUpd      string               `json:"dtm_upd"`
+}
+
+type RequestAuditorium struct {
+	Name     string `json:"name" form:"name"`
+	Capacity int    `json:"capacity" form:"capacity"`
+}
+
+type ResponseAuditorium struct {
+	ID        uint   `json:"id"`
+	TheaterID uint   `json:"theater_id"`
+	Name      string `json:"name"`
+	Capacity  int    `json:"capacity"`
+	DtmCrt    string `json:"dtm_crt"`
+	DtmUpd    string `json:"dtm_upd"`
+}
+
+type RequestParamTheater struct {
+	Page   *int    `json:"page"`
+	Limit  *int    `json:"limit"`
+	City   *string `json:"city"`
+	Search *string `json:"search"`
+}
+
+// RequestParamNearby searches theaters within RadiusKm kilometers of a point, closest first
+type RequestParamNearby struct {
+	Lat      float64 `json:"lat"`
+	Lng      float64 `json:"lng"`
+	RadiusKm float64 `json:"radius_km"`
+	Limit    int     `json:"limit"`
+}
+
+type ResponseGetAllTheater struct {
+	MetaData MetaData          `json:"meta_data"`
+	Data     []ResponseTheater `json:"data"`
+}
+
+type TheaterUseCase interface {
+	PostTheater(ctx context.Context, request RequestTheater) (id int, err error)
+	GetAllTheater(ctx context.Context, request RequestParamTheater) (response ResponseGetAllTheater, err error)
+	GetDetailTheater(ctx context.Context, id int) (response ResponseTheater, err error)
+	GetNearbyTheater(ctx context.Context, request RequestParamNearby) (response []ResponseTheater, err error)
+	UpdateTheater(ctx context.Context, id int, request RequestTheater) (err error)
+	DeleteTheater(ctx context.Context, id int) (err error)
+	PostAuditorium(ctx context.Context, theaterID int, request RequestAuditorium) (id int, err error)
+	UpdateAuditorium(ctx context.Context, id int, request RequestAuditorium) (err error)
+	DeleteAuditorium(ctx context.Context, id int) (err error)
+}
+
+type TheaterMySQLRepo interface {
+	PostTheater(ctx context.Context, request RequestTheater) (id int, err error)
+	CountDataTheater(ctx context.Context, request RequestParamTheater) (response MetaData, err error)
+	GetAllTheater(ctx context.Context, request RequestParamTheater) (response []ResponseTheater, err error)
+	GetDetailTheater(ctx context.Context, id int) (response ResponseTheater, err error)
+	GetNearbyTheater(ctx context.Context, request RequestParamNearby) (response []ResponseTheater, err error)
+	UpdateTheater(ctx context.Context, id int, request RequestTheater) (err error)
+	DeleteTheater(ctx context.Context, id int) (err error)
+	GetAuditoriumByTheater(ctx context.Context, theaterID int) (response []ResponseAuditorium, err error)
+	GetDetailAuditorium(ctx context.Context, id int) (response ResponseAuditorium, err error)
+	GetAuditoriumByName(ctx context.Context, theaterID int, name string) (response ResponseAuditorium, err error)
+	PostAuditorium(ctx context.Context, theaterID int, request RequestAuditorium) (id int, err error)
+	UpdateAuditorium(ctx context.Context, id int, request RequestAuditorium) (err error)
+	DeleteAuditorium(ctx context.Context, id int) (err error)
+}
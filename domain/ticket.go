@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+)
+
+type RequestVerifyTicket struct {
+	Token string `json:"token" form:"token"`
+}
+
+// ResponseTicket is the ticket of one booked seat, Token is the signed value of its QR code
+type ResponseTicket struct {
+	BookingID      uint    `json:"booking_id"`
+	SeatID         uint    `json:"seat_id"`
+	Row            string  `json:"row"`
+	Number         int     `json:"number"`
+	SeatType       string  `json:"seat_type"`
+	UserID         uint    `json:"user_id"`
+	Status         string  `json:"status"`
+	MovieTitle     string  `json:"movie_title"`
+	MovieImage     string  `json:"movie_image"`
+	TheaterName    string  `json:"theater_name"`
+	AuditoriumName string  `json:"auditorium_name"`
+	StartTime      string  `json:"start_time"`
+	EndTime        string  `json:"end_time"`
+	UsedAt         *string `json:"used_at"`
+	Token          string  `json:"token,omitempty"`
+}
+
+type TicketUseCase interface {
+	GetAllTicket(ctx context.Context, bookingID int, user AuthUser) (response []ResponseTicket, err error)
+	GetTicketPDF(ctx context.Context, bookingID int, user AuthUser) (response []byte, err error)
+	VerifyTicket(ctx context.Context, request RequestVerifyTicket, user AuthUser) (response ResponseTicket, err error)
+}
+
+type TicketMySQLRepo interface {
+	GetTicketByBooking(ctx context.Context, bookingID int) (response []ResponseTicket, err error)
+	GetDetailTicket(ctx context.Context, bookingID int, seatID int) (response ResponseTicket, err error)
+	UseTicket(ctx context.Context, bookingID int, seatID int, userID int) (err error)
+}
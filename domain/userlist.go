@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+)
+
+type RequestUserList struct {
+	Name        string `json:"name" form:"name"`
+	Description string `json:"description" form:"description"`
+	IsPublic    bool   `json:"is_public" form:"is_public"`
+	MovieIDs    []int  `json:"movie_ids" form:"movie_ids"`
+	Slug        string `json:"-"`
+}
+
+type RequestUserListMovie struct {
+	MovieID  int  `json:"movie_id" form:"movie_id"`
+	Position *int `json:"position" form:"position"`
+}
+
+type RequestUserListOrder struct {
+	MovieIDs []int `json:"movie_ids" form:"movie_ids"`
+}
+
+type ResponseUserList struct {
+	ID          uint                    `json:"id"`
+	UserID      uint                    `json:"user_id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Slug        string                  `json:"slug"`
+	IsPublic    bool                    `json:"is_public"`
+	TotalMovie  uint                    `json:"total_movie"`
+	Movies      []ResponseUserListMovie `json:"movies,omitempty"`
+	DtmCrt      string                  `json:"dtm_crt"`
+	DtmUpd      string                  `json:"dtm_upd"`
+}
+
+type ResponseUserListMovie struct {
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Rating   float64 `json:"rating"`
+	Image    string  `json:"image"`
+	Position int     `json:"position"`
+}
+
+type RequestParamUserList struct {
+	UserID int  `json:"user_id"`
+	Page   *int `json:"page"`
+	Limit  *int `json:"limit"`
+}
+
+type ResponseGetAllUserList struct {
+	MetaData MetaData           `json:"meta_data"`
+	Data     []ResponseUserList `json:"data"`
+}
+
+type UserListUseCase interface {
+	PostUserList(ctx context.Context, user AuthUser, request RequestUserList) (response ResponseUserList, err error)
+	GetAllUserList(ctx context.Context, request RequestParamUserList) (response ResponseGetAllUserList, err error)
+	GetDetailUserList(ctx context.Context, user AuthUser, id int) (response ResponseUserList, err error)
+	GetPublicUserList(ctx context.Context, user *AuthUser, slug string) (response ResponseUserList, err error)
+	UpdateUserList(ctx context.Context, user AuthUser, id int, request RequestUserList) (err error)
+	DeleteUserList(ctx context.Context, user AuthUser, id int) (err error)
+	AddUserListMovie(ctx context.Context, user AuthUser, id int, request RequestUserListMovie) (err error)
+	DeleteUserListMovie(ctx context.Context, user AuthUser, id int, movieID int) (err error)
+	ReorderUserList(ctx context.Context, user AuthUser, id int, request RequestUserListOrder) (err error)
+}
+
+type UserListMySQLRepo interface {
+	PostUserList(ctx context.Context, userID int, request RequestUserList) (id int, err error)
+	CountDataUserList(ctx context.Context, request RequestParamUserList) (response MetaData, err error)
+	GetAllUserList(ctx context.Context, request RequestParamUserList) (response []ResponseUserList, err error)
+	GetDetailUserList(ctx context.Context, id int) (response ResponseUserList, err error)
+	GetUserListBySlug(ctx context.Context, slug string) (response ResponseUserList, err error)
+	UpdateUserList(ctx context.Context, id int, request RequestUserList) (err error)
+	DeleteUserList(ctx context.Context, id int) (err error)
+	GetUserListMovie(ctx context.Context, id int, published bool) (response []ResponseUserListMovie, err error)
+	SetUserListMovie(ctx context.Context, id int, movieIDs []int) (err error)
+	AddUserListMovie(ctx context.Context, id int, movieID int, position int) (err error)
+	DeleteUserListMovie(ctx context.Context, id int, movieID int) (err error)
+	CountPublicUserListByMovie(ctx context.Context, movieID int) (total uint, err error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	UserMovieWatchlist = "watchlist"
+	UserMovieFavorite  = "favorite"
+)
+
+type RequestUserMovie struct {
+	MovieID int `json:"movie_id" form:"movie_id"`
+}
+
+// UserMovieFilter limits the movie list to the watchlist or favorites of a user
+type UserMovieFilter struct {
+	UserID int
+	Type   string
+}
+
+// UserMovieFlag tells whether a movie is in the watchlist or favorites of a user
+type UserMovieFlag struct {
+	InWatchlist bool
+	IsFavorite  bool
+}
+
+type WatchlistUseCase interface {
+	PostUserMovie(ctx context.Context, user AuthUser, listType string, request RequestUserMovie) (err error)
+	DeleteUserMovie(ctx context.Context, user AuthUser, listType string, movieID int) (err error)
+	GetAllUserMovie(ctx context.Context, user AuthUser, listType string, request RequestParamMovie) (response ResponseGetAllMovie, err error)
+}
+
+type WatchlistMySQLRepo interface {
+	PostUserMovie(ctx context.Context, userID int, listType string, movieID int) (err error)
+	DeleteUserMovie(ctx context.Context, userID int, listType string, movieID int) (err error)
+	GetUserMovieFlag(ctx context.Context, userID int, movieIDs []int) (response map[int]UserMovieFlag, err error)
+}
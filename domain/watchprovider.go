@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	AvailabilityTypeStream = "stream"
+	AvailabilityTypeRent   = "rent"
+	AvailabilityTypeBuy    = "buy"
+	AvailabilityTypeFree   = "free"
+)
+
+type RequestWatchProvider struct {
+	Name    string `json:"name" form:"name"`
+	Slug    string `json:"slug" form:"slug"`
+	LogoURL string `json:"logo_url" form:"logo_url"`
+	Website string `json:"website" form:"website"`
+}
+
+type ResponseWatchProvider struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	LogoURL string `json:"logo_url"`
+	Website string `json:"website"`
+	DtmCrt  string `json:"dtm_crt,omitempty"`
+	DtmUpd  string `json:"dtm_upd,omitempty"`
+}
+
+type RequestParamWatchProvider struct {
+	Page   *int    `json:"page"`
+	Limit  *int    `json:"limit"`
+	Search *string `json:"search"`
+}
+
+type ResponseGetAllWatchProvider struct {
+	MetaData MetaData                `json:"meta_data"`
+	Data     []ResponseWatchProvider `json:"data"`
+}
+
+// RequestAvailability is where a movie is watched in a region, Region is an ISO 3166-1 alpha-2
+// country code. Price is in the minor unit of Currency, ValidFrom and ValidTo are YYYY-MM-DD
+// days both included, nil leaves the window open
+type RequestAvailability struct {
+	ProviderID int     `json:"provider_id" form:"provider_id"`
+	Region     string  `json:"region" form:"region"`
+	Type       string  `json:"type" form:"type"`
+	Price      *int64  `json:"price" form:"price"`
+	Currency   *string `json:"currency" form:"currency"`
+	Link       string  `json:"link" form:"link"`
+	ValidFrom  *string `json:"valid_from" form:"valid_from"`
+	ValidTo    *string `json:"valid_to" form:"valid_to"`
+}
+
+type ResponseAvailability struct {
+	ID        uint                  `json:"id"`
+	MovieID   uint                  `json:"movie_id"`
+	Provider  ResponseWatchProvider `json:"provider"`
+	Region    string                `json:"region"`
+	Type      string                `json:"type"`
+	Price     *int64                `json:"price"`
+	Currency  *string               `json:"currency"`
+	Link      string                `json:"link"`
+	ValidFrom *string               `json:"valid_from"`
+	ValidTo   *string               `json:"valid_to"`
+	DtmCrt    string                `json:"dtm_crt"`
+	DtmUpd    string                `json:"dtm_upd"`
+}
+
+// RequestParamAvailability filters the availability of a movie, Current leaves out records
+// outside of their validity window
+type RequestParamAvailability struct {
+	MovieID int     `json:"movie_id"`
+	Region  *string `json:"region"`
+	Type    *string `json:"type"`
+	Current bool    `json:"current"`
+}
+
+type WatchProviderUseCase interface {
+	PostWatchProvider(ctx context.Context, request RequestWatchProvider) (id int, err error)
+	GetAllWatchProvider(ctx context.Context, request RequestParamWatchProvider) (response ResponseGetAllWatchProvider, err error)
+	GetDetailWatchProvider(ctx context.Context, id int) (response ResponseWatchProvider, err error)
+	UpdateWatchProvider(ctx context.Context, id int, request RequestWatchProvider) (err error)
+	DeleteWatchProvider(ctx context.Context, id int) (err error)
+	PostAvailability(ctx context.Context, movieID int, request RequestAvailability) (id int, err error)
+	GetAvailability(ctx context.Context, request RequestParamAvailability) (response []ResponseAvailability, err error)
+	UpdateAvailability(ctx context.Context, id int, request RequestAvailability) (err error)
+	DeleteAvailability(ctx context.Context, id int) (err error)
+}
+
+type WatchProviderMySQLRepo interface {
+	PostWatchProvider(ctx context.Context, request RequestWatchProvider) (id int, err error)
+	CountDataWatchProvider(ctx context.Context, request RequestParamWatchProvider) (response MetaData, err error)
+	GetAllWatchProvider(ctx context.Context, request RequestParamWatchProvider) (response []ResponseWatchProvider, err error)
+	GetDetailWatchProvider(ctx context.Context, id int) (response ResponseWatchProvider, err error)
+	GetWatchProviderBySlug(ctx context.Context, slug string) (response ResponseWatchProvider, err error)
+	UpdateWatchProvider(ctx context.Context, id int, request RequestWatchProvider) (err error)
+	DeleteWatchProvider(ctx context.Context, id int) (err error)
+	PostAvailability(ctx context.Context, movieID int, request RequestAvailability) (id int, err error)
+	GetAvailability(ctx context.Context, request RequestParamAvailability) (response []ResponseAvailability, err error)
+	GetDetailAvailability(ctx context.Context, id int) (response ResponseAvailability, err error)
+	UpdateAvailability(ctx context.Context, id int, request RequestAvailability) (err error)
+	DeleteAvailability(ctx context.Context, id int) (err error)
+}
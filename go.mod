@@ -3,6 +3,7 @@ module xsis-academy-test-service-movie
 go 1.20
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/gofiber/fiber/v2 v2.52.0
@@ -10,6 +11,7 @@ require (
 	github.com/labstack/gommon v0.4.2
 	github.com/processout/grpc-go-pool v1.2.1
 	github.com/sirupsen/logrus v1.9.3
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/spf13/viper v1.18.2
 	github.com/valyala/fasthttp v1.51.0
 	google.golang.org/grpc v1.60.1
@@ -0,0 +1,112 @@
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DumpFiles lists the .json, .ndjson and .jsonl files under root sorted by path, relative to root, or root
+// itself when it is a file
+func DumpFiles(root string) (files []string, err error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{""}, nil
+	}
+
+	err = filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json", ".ndjson", ".jsonl":
+			if !entry.IsDir() {
+				rel, err := filepath.Rel(root, name)
+				if err != nil {
+					return err
+				}
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// EachDumpEntry calls fn with every entry of a dump file numbered from 1 without loading the file. A .json
+// file holds an object or an array of objects, other files an object per line
+func EachDumpEntry(r io.Reader, fileName string, fn func(entry int, raw json.RawMessage) error) (err error) {
+	reader := bufio.NewReader(r)
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext == ".ndjson" || ext == ".jsonl" {
+		entry := 0
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				entry++
+				if err := fn(entry, json.RawMessage(line)); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF {
+				return nil
+			}
+		}
+	}
+
+	// A single object is told from an array by its first character
+	var first byte
+	for {
+		first, err = reader.ReadByte()
+		if err != nil {
+			return err
+		}
+		if first != ' ' && first != '\t' && first != '\r' && first != '\n' {
+			break
+		}
+	}
+	reader.UnreadByte()
+
+	decoder := json.NewDecoder(reader)
+	switch first {
+	case '{':
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return err
+		}
+		return fn(1, raw)
+
+	case '[':
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		entry := 0
+		for decoder.More() {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return err
+			}
+			entry++
+			if err := fn(entry, raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.New("dump must hold a JSON object or an array of objects")
+}
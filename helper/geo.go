@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// ParseNearby reads lat, lng, radius_km and limit query parameters, radius and limit fall back
+// to theater.nearby_radius and theater.nearby_limit
+func ParseNearby(c *fiber.Ctx) (request domain.RequestParamNearby, err error) {
+	if c.Query("lat") == "" || c.Query("lng") == "" {
+		return request, errors.New("lat and lng are required")
+	}
+
+	request.Lat, err = strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil {
+		return request, err
+	}
+	request.Lng, err = strconv.ParseFloat(c.Query("lng"), 64)
+	if err != nil {
+		return request, err
+	}
+
+	request.RadiusKm = viper.GetFloat64("theater.nearby_radius")
+	if c.Query("radius_km") != "" {
+		request.RadiusKm, err = strconv.ParseFloat(c.Query("radius_km"), 64)
+		if err != nil {
+			return request, err
+		}
+	}
+
+	request.Limit = viper.GetInt("theater.nearby_limit")
+	if c.Query("limit") != "" {
+		request.Limit, err = strconv.Atoi(c.Query("limit"))
+		if err != nil {
+			return request, err
+		}
+	}
+	return request, nil
+}
+
+// ValidateNearby checks the point is a valid coordinate and the radius is within theater.max_nearby_radius
+func ValidateNearby(request domain.RequestParamNearby) (err error) {
+	if request.Lat < -90 || request.Lat > 90 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("lat must be between -90 and 90")}
+	}
+	if request.Lng < -180 || request.Lng > 180 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("lng must be between -180 and 180")}
+	}
+
+	maxRadius := viper.GetFloat64("theater.max_nearby_radius")
+	if request.RadiusKm <= 0 || (maxRadius > 0 && request.RadiusKm > maxRadius) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("radius_km must be greater than 0 and at most %g", maxRadius)}
+	}
+	if request.Limit < 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("limit must not be negative")}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"errors"
 	"time"
 	"xsis-academy-test-service-movie/constant"
 
@@ -53,3 +54,17 @@ func HttpResponseSuccess(c *fiber.Ctx, data interface{}) error {
 func HttpResponseFileSuccess(c *fiber.Ctx, fullPathFile string) error {
 	return c.Status(fiber.StatusOK).SendFile(fullPathFile, false)
 }
+
+// HttpResponseFromError maps an error returned by a usecase to the matching HTTP response
+func HttpResponseFromError(c *fiber.Ctx, err error) error {
+	var resultError constant.ResultError
+	if errors.As(err, &resultError) {
+		return HttpResponseError(c, resultError.Code, resultError.Err.Error())
+	}
+
+	if err.Error() == "Not found" {
+		return HttpSimpleResponse(c, fasthttp.StatusNotFound)
+	}
+
+	return HttpSimpleResponse(c, fasthttp.StatusInternalServerError)
+}
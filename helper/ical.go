@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	icalLayout     = "20060102T150405Z"
+	icalDateLayout = "20060102"
+	icalLineLength = 75
+)
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// writeICalLine writes a content line folded at 75 octets without splitting a UTF-8 character,
+// continuation lines start with a space
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLength - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// ICalendar renders the calendar as an RFC 5545 iCalendar feed, times are written in UTC and
+// all-day events as dates
+func ICalendar(calendar domain.Calendar) []byte {
+	var buf bytes.Buffer
+	refresh := "PT" + strconv.Itoa(viper.GetInt("calendar.refresh_interval")) + "M"
+
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//xsis-academy//service-movie//EN")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+icalEscaper.Replace(calendar.Name))
+	writeICalLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:"+refresh)
+	writeICalLine(&buf, "X-PUBLISHED-TTL:"+refresh)
+
+	for _, event := range calendar.Events {
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, "UID:"+event.UID)
+		writeICalLine(&buf, "DTSTAMP:"+event.Modified.UTC().Format(icalLayout))
+		writeICalLine(&buf, "LAST-MODIFIED:"+event.Modified.UTC().Format(icalLayout))
+		if event.AllDay {
+			writeICalLine(&buf, "DTSTART;VALUE=DATE:"+event.Start.Format(icalDateLayout))
+			writeICalLine(&buf, "DTEND;VALUE=DATE:"+event.End.Format(icalDateLayout))
+		} else {
+			writeICalLine(&buf, "DTSTART:"+event.Start.UTC().Format(icalLayout))
+			writeICalLine(&buf, "DTEND:"+event.End.UTC().Format(icalLayout))
+		}
+		writeICalLine(&buf, "SUMMARY:"+icalEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&buf, "DESCRIPTION:"+icalEscaper.Replace(event.Description))
+		}
+		if event.Location != "" {
+			writeICalLine(&buf, "LOCATION:"+icalEscaper.Replace(event.Location))
+		}
+		writeICalLine(&buf, "END:VEVENT")
+	}
+
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// LocalTime parses a YYYY-MM-DD HH:MM:SS value stored in the service time location
+func LocalTime(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04:05", value, location)
+}
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// maxImageRedirects is the number of redirects followed to download an image
+const maxImageRedirects = 3
+
+// ErrImageTooLarge is returned when an image is over the allowed size
+var ErrImageTooLarge = errors.New("image is too large")
+
+// ErrImageHostNotPublic is returned when an image URL reaches a loopback, private or link-local address
+var ErrImageHostNotPublic = errors.New("image url host is not public")
+
+// SaveImage writes the image under parentPath/subpath with the lower cased file name and returns the path
+// stored in the database, like SaveImageToLocalDrive. maxSize 0 disables the size limit
+func SaveImage(src io.Reader, fileName string, parentPath string, subpath string, maxSize int64) (string, error) {
+	fileName = strings.ToLower(filepath.Base(fileName))
+	dstPath := filepath.Join(parentPath, subpath, fileName)
+	savePathDB := filepath.Join(subpath, fileName)
+
+	if maxSize > 0 {
+		src = io.LimitReader(src, maxSize+1)
+	}
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	written, err := io.Copy(dst, src)
+	if err != nil {
+		return "", err
+	}
+	if maxSize > 0 && written > maxSize {
+		dst.Close()
+		os.Remove(dstPath)
+		return "", ErrImageTooLarge
+	}
+
+	return savePathDB, nil
+}
+
+// IsImageURL tells whether the image reference is a http or https URL
+func IsImageURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// publicAddress tells whether an image may be downloaded from ip, the service must not be used to reach
+// itself or the internal network
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified()
+}
+
+// imageClient checks every address the host resolves to once it is dialed, redirects included, unless
+// import.allow_private_hosts is set
+func imageClient(timeout time.Duration) *http.Client {
+	allowPrivate := viper.GetBool("import.allow_private_hosts")
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || (!allowPrivate && !publicAddress(ip)) {
+				return ErrImageHostNotPublic
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxImageRedirects {
+				return errors.New("image url redirects too many times")
+			}
+			return nil
+		},
+	}
+}
+
+// DownloadImage fetches an image URL and saves it with SaveImage, the file name is prefix followed by the
+// last element of the URL path. Hosts resolving to loopback, private or link-local addresses are refused
+func DownloadImage(imageURL string, prefix string, parentPath string, subpath string, maxSize int64, timeout time.Duration) (string, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", errors.New("image url is invalid")
+	}
+
+	client := imageClient(timeout)
+	res, err := client.Get(imageURL)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image url answered %s", res.Status)
+	}
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "image/") {
+		return "", errors.New("image url is not an image")
+	}
+	if maxSize > 0 && res.ContentLength > maxSize {
+		return "", ErrImageTooLarge
+	}
+
+	return SaveImage(res.Body, prefix+path.Base(parsed.Path), parentPath, subpath, maxSize)
+}
@@ -0,0 +1,44 @@
+package helper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/spf13/viper"
+)
+
+func TestDownloadImageRefusesLocalHost(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}))
+	defer server.Close()
+
+	_, err := helper.DownloadImage(server.URL+"/poster.png", "", t.TempDir(), "", 0, time.Second)
+	if !errors.Is(err, helper.ErrImageHostNotPublic) || requested {
+		t.Fatalf("got %v, want the loopback host refused before any request", err)
+	}
+}
+
+func TestDownloadImageRedirectLimit(t *testing.T) {
+	viper.Set("import.allow_private_hosts", true)
+	defer viper.Reset()
+
+	redirects := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirects++
+		http.Redirect(w, r, "/poster.png", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := helper.DownloadImage(server.URL+"/poster.png", "", t.TempDir(), "", 0, time.Second)
+	if err == nil || redirects != 4 {
+		t.Fatalf("got %v after %d requests, want the download stopped after 3 redirects", err, redirects)
+	}
+}
@@ -0,0 +1,110 @@
+package helper
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// repeatedLetterLimit is the number of the same character in a row considered as spam
+const repeatedLetterLimit = 6
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+	leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+)
+
+// ContentFilter flags profanity from the configured word lists and common spam patterns
+type ContentFilter struct {
+	words    map[string]bool
+	phrases  []string
+	maxLinks int
+}
+
+// NewContentFilter loads every word list file, blank lines and lines starting with # are ignored
+func NewContentFilter(wordListFiles []string, maxLinks int) (*ContentFilter, error) {
+	filter := &ContentFilter{words: map[string]bool{}, maxLinks: maxLinks}
+	for _, file := range wordListFiles {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			filter.AddWord(line)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// AddWord registers a single word or a multi word phrase
+func (cf *ContentFilter) AddWord(word string) {
+	tokens := tokenize(word)
+	if len(tokens) == 1 {
+		cf.words[tokens[0]] = true
+	} else if len(tokens) > 1 {
+		cf.phrases = append(cf.phrases, " "+strings.Join(tokens, " ")+" ")
+	}
+}
+
+// Check returns the reason when the text must be held for moderation
+func (cf *ContentFilter) Check(text string) (reason string, flagged bool) {
+	if cf.maxLinks >= 0 && len(linkPattern.FindAllString(text, -1)) > cf.maxLinks {
+		return "spam: too many links", true
+	}
+
+	if hasRepeatedLetter(text) {
+		return "spam: repeated characters", true
+	}
+
+	tokens := tokenize(text)
+	for _, token := range tokens {
+		if cf.words[token] {
+			return "profanity: " + token, true
+		}
+	}
+
+	joined := " " + strings.Join(tokens, " ") + " "
+	for _, phrase := range cf.phrases {
+		if strings.Contains(joined, phrase) {
+			return "profanity:" + strings.TrimRight(phrase, " "), true
+		}
+	}
+	return "", false
+}
+
+// tokenize lowercases the text, undoes simple leetspeak and splits it into words
+func tokenize(text string) []string {
+	text = leetReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+func hasRepeatedLetter(text string) bool {
+	var last rune
+	count := 0
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			count++
+			if count >= repeatedLetterLimit {
+				return true
+			}
+			continue
+		}
+		last = r
+		count = 1
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+)
+
+// MovieFilter reads the movie list filters from the query parameters returned by query, shared by the movie
+// list, the export and the export job
+func MovieFilter(query func(key string, defaultValue ...string) string) (input domain.RequestParamMovie, err error) {
+	search := query("search")
+	if search != "" {
+		input.Search = &search
+	}
+
+	collection := query("collection")
+	if collection != "" {
+		collectionInt, err := strconv.Atoi(collection)
+		if err != nil {
+			return input, err
+		}
+		input.Collection = &collectionInt
+	}
+
+	tag := query("tag")
+	if tag != "" {
+		tag = Slugify(tag)
+		input.Tag = &tag
+	}
+
+	availableOn := query("available_on")
+	if availableOn != "" {
+		availableOn = Slugify(availableOn)
+		input.AvailableOn = &availableOn
+	}
+
+	region := query("region")
+	if region != "" {
+		region = strings.ToUpper(region)
+		input.Region = &region
+	}
+
+	status := query("status")
+	if status != "" {
+		if _, ok := domain.MovieTransition[status]; !ok {
+			return input, errors.New("status must be draft, review, published or archived")
+		}
+		input.Status = &status
+	}
+
+	// The order is written into the query, only the known values are accepted
+	order := strings.ToLower(query("order"))
+	if order != "" {
+		if order != domain.MovieOrderAsc && order != domain.MovieOrderDesc && order != domain.MovieOrderPopularity {
+			return input, errors.New("order must be asc, desc or popularity")
+		}
+		input.Order = &order
+	}
+
+	return input, nil
+}
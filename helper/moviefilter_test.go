@@ -0,0 +1,34 @@
+package helper_test
+
+import (
+	"testing"
+	"xsis-academy-test-service-movie/helper"
+)
+
+func TestMovieFilterOrder(t *testing.T) {
+	tests := []struct {
+		order string
+		valid bool
+	}{
+		{order: "asc", valid: true},
+		{order: "DESC", valid: true},
+		{order: "popularity", valid: true},
+		{order: "title"},
+		{order: "id; DROP TABLE movie"},
+		{order: "(SELECT SLEEP(5))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			_, err := helper.MovieFilter(func(key string, defaultValue ...string) string {
+				if key == "order" {
+					return tt.order
+				}
+				return ""
+			})
+			if valid := err == nil; valid != tt.valid {
+				t.Fatalf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
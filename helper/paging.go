@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// ParsePaging reads page and limit query parameters, falling back to the default paging from config
+func ParsePaging(c *fiber.Ctx) (page *int, limit *int, err error) {
+	limitInt := viper.GetInt("database.default_limit_query")
+	if c.Query("limit") != "" {
+		limitInt, err = strconv.Atoi(c.Query("limit"))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pageInt := viper.GetInt("database.default_page")
+	if c.Query("page") != "" {
+		pageInt, err = strconv.Atoi(c.Query("page"))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if pageInt < 1 || limitInt < 0 {
+		return nil, nil, errors.New("invalid paging")
+	}
+
+	return &pageInt, &limitInt, nil
+}
@@ -0,0 +1,94 @@
+package helper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"xsis-academy-test-service-movie/constant"
+)
+
+// MoviePosition checks the position a movie is placed at among movieIDs, the movies of a collection or a user
+// list. Without position the movie goes at the end, a movie already there is moved
+func MoviePosition(movieIDs []int, movieID int, position *int) (int, error) {
+	last := len(movieIDs) + 1
+	for _, id := range movieIDs {
+		if id == movieID {
+			last = len(movieIDs)
+		}
+	}
+	if position == nil {
+		return last, nil
+	}
+	if *position < 1 || *position > last {
+		return 0, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("position is out of range")}
+	}
+	return *position, nil
+}
+
+// PlaceMovie moves movieID to position among the movies of the parent id in table, running insert with the
+// parent id, the movie and the position when the movie is not there yet. The movies are numbered from 1
+// without gaps
+func PlaceMovie(ctx context.Context, tx *sql.Tx, table string, parent string, id int, movieID int, position int, insert string) (err error) {
+	movieIDs, err := lockMovie(ctx, tx, table, parent, id)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, 0, len(movieIDs)+1)
+	found := false
+	for _, current := range movieIDs {
+		if current == movieID {
+			found = true
+			continue
+		}
+		order = append(order, current)
+	}
+	if position < 1 || position > len(order)+1 {
+		position = len(order) + 1
+	}
+	order = append(order[:position-1], append([]int{movieID}, order[position-1:]...)...)
+
+	if !found {
+		_, err = tx.ExecContext(ctx, insert, id, movieID, position)
+		if err != nil {
+			return err
+		}
+	}
+	return numberMovie(ctx, tx, table, parent, id, order)
+}
+
+// CompactMovie numbers the movies of the parent id in table from 1 without gaps, once a movie is removed
+func CompactMovie(ctx context.Context, tx *sql.Tx, table string, parent string, id int) (err error) {
+	movieIDs, err := lockMovie(ctx, tx, table, parent, id)
+	if err != nil {
+		return err
+	}
+	return numberMovie(ctx, tx, table, parent, id, movieIDs)
+}
+
+func lockMovie(ctx context.Context, tx *sql.Tx, table string, parent string, id int) (movieIDs []int, err error) {
+	rows, err := tx.QueryContext(ctx, `SELECT movie_id FROM `+table+` WHERE `+parent+` = ? ORDER BY position, movie_id FOR UPDATE`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieID int
+		if err := rows.Scan(&movieID); err != nil {
+			return nil, err
+		}
+		movieIDs = append(movieIDs, movieID)
+	}
+	return movieIDs, rows.Err()
+}
+
+func numberMovie(ctx context.Context, tx *sql.Tx, table string, parent string, id int, order []int) (err error) {
+	for idx, movieID := range order {
+		_, err = tx.ExecContext(ctx, `UPDATE `+table+` SET position = ? WHERE `+parent+` = ? AND movie_id = ? AND position <> ?`, idx+1, id, movieID, idx+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
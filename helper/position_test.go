@@ -0,0 +1,106 @@
+package helper_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/helper/sqltest"
+)
+
+func TestMoviePosition(t *testing.T) {
+	at := func(position int) *int {
+		return &position
+	}
+	tests := []struct {
+		name     string
+		movieID  int
+		position *int
+		want     int
+		invalid  bool
+	}{
+		{name: "new movie goes at the end", movieID: 9, want: 4},
+		{name: "listed movie stays at the end", movieID: 2, want: 3},
+		{name: "new movie at the end position", movieID: 9, position: at(4), want: 4},
+		{name: "listed movie cannot go past the end", movieID: 2, position: at(4), invalid: true},
+		{name: "position starts at 1", movieID: 9, position: at(0), invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helper.MoviePosition([]int{1, 2, 3}, tt.movieID, tt.position)
+			if (err != nil) != tt.invalid || got != tt.want {
+				t.Fatalf("got %d and %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// positions answers the locked read with movies 5, 6 and 7 and gaps between their positions
+func positions(query string, args []interface{}) sqltest.Result {
+	if strings.Contains(query, "FOR UPDATE") {
+		return sqltest.Result{Columns: []string{"movie_id"}, Rows: [][]interface{}{{int64(5)}, {int64(6)}, {int64(7)}}}
+	}
+	return sqltest.Result{}
+}
+
+// numbered returns the position set for each movie
+func numbered(recorder *sqltest.Recorder) map[interface{}]interface{} {
+	response := map[interface{}]interface{}{}
+	for _, statement := range recorder.Find("UPDATE list_movie SET position") {
+		response[statement.Args[2]] = statement.Args[0]
+	}
+	return response
+}
+
+func TestPlaceMovie(t *testing.T) {
+	insert := `INSERT INTO list_movie (list_id, movie_id, position) VALUES (?, ?, ?)`
+
+	recorder := &sqltest.Recorder{Rows: positions}
+	tx, err := sqltest.Open(recorder).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Moving a listed movie renumbers the list without inserting it again
+	err = helper.PlaceMovie(context.Background(), tx, "list_movie", "list_id", 3, 7, 1, insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := numbered(recorder)
+	if len(recorder.Find("INSERT")) != 0 || got[int64(7)] != int64(1) || got[int64(5)] != int64(2) || got[int64(6)] != int64(3) {
+		t.Fatalf("got positions %v, want 7, 5 and 6 numbered from 1", got)
+	}
+
+	// A new movie is inserted at its position and the ones after it move down
+	recorder = &sqltest.Recorder{Rows: positions}
+	tx, err = sqltest.Open(recorder).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = helper.PlaceMovie(context.Background(), tx, "list_movie", "list_id", 3, 9, 2, insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inserts := recorder.Find("INSERT INTO list_movie")
+	got = numbered(recorder)
+	if len(inserts) != 1 || inserts[0].Count(9) != 1 || got[int64(5)] != int64(1) || got[int64(9)] != int64(2) || got[int64(6)] != int64(3) || got[int64(7)] != int64(4) {
+		t.Fatalf("got %+v and positions %v, want 9 inserted second", inserts, got)
+	}
+}
+
+func TestCompactMovie(t *testing.T) {
+	recorder := &sqltest.Recorder{Rows: positions}
+	tx, err := sqltest.Open(recorder).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = helper.CompactMovie(context.Background(), tx, "list_movie", "list_id", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := numbered(recorder); got[int64(5)] != int64(1) || got[int64(6)] != int64(2) || got[int64(7)] != int64(3) {
+		t.Fatalf("got positions %v, want 1 to 3", got)
+	}
+}
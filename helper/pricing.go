@@ -0,0 +1,27 @@
+package helper
+
+import (
+	"math"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/spf13/viper"
+)
+
+// SeatPrice prices a seat from the showtime base price, the seat class multiplier and the
+// weekend multiplier applied to showtimes starting on Saturday or Sunday
+func SeatPrice(basePrice int64, seatType string, startTime time.Time) int64 {
+	multiplier := 1.0
+	switch seatType {
+	case domain.SeatTypeVIP:
+		multiplier = viper.GetFloat64("pricing.seat_vip")
+	case domain.SeatTypeWheelchair:
+		multiplier = viper.GetFloat64("pricing.seat_wheelchair")
+	}
+
+	if day := startTime.Weekday(); day == time.Saturday || day == time.Sunday {
+		multiplier *= viper.GetFloat64("pricing.weekend")
+	}
+
+	return int64(math.Round(float64(basePrice) * multiplier))
+}
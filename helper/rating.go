@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"math"
+	"xsis-academy-test-service-movie/domain"
+)
+
+// RatingSummary computes mean, histogram and the Bayesian weighted score
+// (v/(v+m))*R + (m/(v+m))*C of a movie rating aggregate
+func RatingSummary(aggregate domain.RatingAggregate, globalMean float64, minVotes float64) domain.ResponseRatingAggregate {
+	response := domain.ResponseRatingAggregate{
+		Count:     aggregate.Count,
+		Histogram: map[int]uint{},
+	}
+	for idx, total := range aggregate.Histogram {
+		response.Histogram[idx+1] = total
+	}
+
+	votes := float64(aggregate.Count)
+	if votes > 0 {
+		response.Mean = float64(aggregate.Sum) / votes
+	}
+
+	if votes+minVotes > 0 {
+		response.WeightedScore = votes/(votes+minVotes)*response.Mean + minVotes/(votes+minVotes)*globalMean
+	}
+
+	response.Mean = math.Round(response.Mean*100) / 100
+	response.WeightedScore = math.Round(response.WeightedScore*100) / 100
+	return response
+}
@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+)
+
+// RecordFormat is the format named by the request, otherwise guessed from the file extension
+func RecordFormat(format string, fileName string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".json":
+		return domain.ImportFormatJSON
+	case ".ndjson", ".jsonl":
+		return domain.ImportFormatNDJSON
+	case ".xlsx":
+		return domain.ExportFormatXLSX
+	}
+	return ""
+}
+
+// ReadRecords reads the rows of a CSV file with a header line, a JSON array of objects or NDJSON
+// objects, one per line. Blank rows are left out
+func ReadRecords(r io.Reader, format string) (records []domain.Record, err error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return readCSV(r)
+	case domain.ImportFormatJSON:
+		return readJSON(r)
+	case domain.ImportFormatNDJSON:
+		return readNDJSON(r)
+	}
+	return nil, errors.New("format must be csv, json or ndjson")
+}
+
+func readCSV(r io.Reader) (records []domain.Record, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("csv header is missing")
+		}
+		return nil, err
+	}
+	for idx := range header {
+		header[idx] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[idx], "\ufeff")))
+	}
+
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := domain.Record{Row: line, Fields: map[string]string{}}
+		blank := true
+		for idx, value := range values {
+			if idx >= len(header) || header[idx] == "" {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value != "" {
+				blank = false
+			}
+			record.Fields[header[idx]] = value
+		}
+		if !blank {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func readJSON(r io.Reader) (records []domain.Record, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var rows []map[string]interface{}
+	err = decoder.Decode(&rows)
+	if err != nil {
+		return nil, fmt.Errorf("json must be an array of objects: %w", err)
+	}
+
+	for idx, row := range rows {
+		records = append(records, domain.Record{Row: idx + 1, Fields: recordFields(row)})
+	}
+	return records, nil
+}
+
+func readNDJSON(r io.Reader) (records []domain.Record, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("line %d must be a json object: %w", line, err)
+		}
+		records = append(records, domain.Record{Row: line, Fields: recordFields(row)})
+	}
+	return records, scanner.Err()
+}
+
+// recordFields turns the values of a JSON object into text, nested arrays and objects are kept as JSON
+func recordFields(row map[string]interface{}) map[string]string {
+	fields := map[string]string{}
+	for key, value := range row {
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch value := value.(type) {
+		case nil:
+			fields[key] = ""
+		case string:
+			fields[key] = strings.TrimSpace(value)
+		case json.Number:
+			fields[key] = value.String()
+		case bool:
+			fields[key] = fmt.Sprint(value)
+		default:
+			content, _ := json.Marshal(value)
+			fields[key] = string(content)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// Slugify normalizes a name into a lowercase, dash separated slug, e.g. "Based on Novel" -> "based-on-novel"
+func Slugify(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "-")
+}
+
+// RandomHex returns n random bytes encoded as hex, it is used to make shared slugs unguessable
+func RandomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
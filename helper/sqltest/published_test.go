@@ -0,0 +1,139 @@
+package sqltest_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	collection "xsis-academy-test-service-movie/collection/repository/mysql"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper/sqltest"
+	recommendation "xsis-academy-test-service-movie/recommendation/repository/mysql"
+	relation "xsis-academy-test-service-movie/relation/repository/mysql"
+	showtime "xsis-academy-test-service-movie/showtime/repository/mysql"
+	similar "xsis-academy-test-service-movie/similar/repository/mysql"
+	tag "xsis-academy-test-service-movie/tag/repository/mysql"
+	userlist "xsis-academy-test-service-movie/userlist/repository/mysql"
+)
+
+// TestPublishedFilter checks that every public read of movies binds the published status once per movie
+// table it joins, and that the management reads bind none
+func TestPublishedFilter(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		read  func(db *sql.DB) error
+		query string
+		want  int
+	}{
+		{
+			name: "collection detail",
+			read: func(db *sql.DB) error {
+				_, err := collection.NewMySQLCollectionRepository(db).GetCollectionMovie(ctx, 3, true)
+				return err
+			},
+			query: "FROM collection_movie",
+			want:  1,
+		},
+		{
+			name: "collection management",
+			read: func(db *sql.DB) error {
+				_, err := collection.NewMySQLCollectionRepository(db).GetCollectionMovie(ctx, 3, false)
+				return err
+			},
+			query: "FROM collection_movie",
+		},
+		{
+			name: "related movies on both sides of the relation",
+			read: func(db *sql.DB) error {
+				_, err := relation.NewMySQLRelationRepository(db).GetRelationByMovie(ctx, 7)
+				return err
+			},
+			query: "FROM movie_relation",
+			want:  2,
+		},
+		{
+			name: "similar movies",
+			read: func(db *sql.DB) error {
+				_, err := similar.NewMySQLSimilarRepository(db).GetSimilarMovie(ctx, 7, 10)
+				return err
+			},
+			query: "FROM movie_similar",
+			want:  1,
+		},
+		{
+			name: "recommended movies",
+			read: func(db *sql.DB) error {
+				_, err := recommendation.NewMySQLRecommendationRepository(db).GetRecommendedMovie(ctx, []int{1, 2})
+				return err
+			},
+			query: "FROM movie",
+			want:  1,
+		},
+		{
+			name: "popular movies",
+			read: func(db *sql.DB) error {
+				_, err := recommendation.NewMySQLRecommendationRepository(db).GetPopularMovie(ctx, []int{1}, 10)
+				return err
+			},
+			query: "FROM movie",
+			want:  1,
+		},
+		{
+			name: "shared user list",
+			read: func(db *sql.DB) error {
+				_, err := userlist.NewMySQLUserListRepository(db).GetUserListMovie(ctx, 4, true)
+				return err
+			},
+			query: "FROM user_list_movie",
+			want:  1,
+		},
+		{
+			name: "own user list",
+			read: func(db *sql.DB) error {
+				_, err := userlist.NewMySQLUserListRepository(db).GetUserListMovie(ctx, 4, false)
+				return err
+			},
+			query: "FROM user_list_movie",
+		},
+		{
+			name: "tag counts",
+			read: func(db *sql.DB) error {
+				_, err := tag.NewMySQLTagRepository(db).GetAllTag(ctx, domain.RequestParamTag{}, true)
+				return err
+			},
+			query: "COUNT(m.id)",
+			want:  1,
+		},
+		{
+			name: "public showtimes",
+			read: func(db *sql.DB) error {
+				_, err := showtime.NewMySQLShowtimeRepository(db).GetAllShowtime(ctx, domain.RequestParamShowtime{Published: true})
+				return err
+			},
+			query: "FROM showtime",
+			want:  1,
+		},
+		{
+			name: "showtimes of every movie",
+			read: func(db *sql.DB) error {
+				_, err := showtime.NewMySQLShowtimeRepository(db).GetAllShowtime(ctx, domain.RequestParamShowtime{})
+				return err
+			},
+			query: "FROM showtime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &sqltest.Recorder{}
+			if err := tt.read(sqltest.Open(recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			statements := recorder.Find(tt.query)
+			if len(statements) != 1 || statements[0].Count(domain.MovieStatusPublished) != tt.want {
+				t.Fatalf("got %+v, want one query binding published %d times", recorder.Statements(), tt.want)
+			}
+		})
+	}
+}
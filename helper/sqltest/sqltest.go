@@ -0,0 +1,224 @@
+// Package sqltest is a database/sql driver recording the statements run by a repository, for the tests of
+// the repositories without a MySQL server
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Statement is a query or an exec run by the repository with its arguments
+type Statement struct {
+	Query string
+	Args  []interface{}
+}
+
+// Count returns how many arguments equal value, ints are bound as int64
+func (statement Statement) Count(value interface{}) (count int) {
+	if id, ok := value.(int); ok {
+		value = int64(id)
+	}
+	for _, arg := range statement.Args {
+		if arg == value {
+			count++
+		}
+	}
+	return count
+}
+
+// Result is the rows returned to a query
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Recorder records the statements, Rows answers a query and returns no rows when nil. An exec affects one
+// row and inserts the id 1. Transactions are recorded as BEGIN, COMMIT and ROLLBACK statements
+type Recorder struct {
+	Rows func(query string, args []interface{}) Result
+
+	mu         sync.Mutex
+	statements []Statement
+}
+
+// Open returns a database recording to the recorder
+func Open(recorder *Recorder) *sql.DB {
+	return sql.OpenDB(connector{recorder})
+}
+
+// Statements returns the statements run so far
+func (recorder *Recorder) Statements() []Statement {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]Statement{}, recorder.statements...)
+}
+
+// Queries returns the query of every statement run so far
+func (recorder *Recorder) Queries() (response []string) {
+	for _, statement := range recorder.Statements() {
+		response = append(response, statement.Query)
+	}
+	return response
+}
+
+// Find returns the statements whose query contains every part
+func (recorder *Recorder) Find(parts ...string) (response []Statement) {
+	for _, statement := range recorder.Statements() {
+		found := true
+		for _, part := range parts {
+			found = found && strings.Contains(statement.Query, part)
+		}
+		if found {
+			response = append(response, statement)
+		}
+	}
+	return response
+}
+
+func (recorder *Recorder) record(query string, named []driver.NamedValue) []interface{} {
+	args := make([]interface{}, len(named))
+	for idx, arg := range named {
+		args[idx] = arg.Value
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.statements = append(recorder.statements, Statement{Query: query, Args: args})
+	return args
+}
+
+type connector struct {
+	recorder *Recorder
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{c.recorder}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return sqlDriver{c.recorder}
+}
+
+type sqlDriver struct {
+	recorder *Recorder
+}
+
+func (d sqlDriver) Open(string) (driver.Conn, error) {
+	return &conn{d.recorder}, nil
+}
+
+type conn struct {
+	recorder *Recorder
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c, query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.recorder.record("BEGIN", nil)
+	return tx{c.recorder}, nil
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, named []driver.NamedValue) (driver.Result, error) {
+	c.recorder.record(query, named)
+	return execResult{}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	args := c.recorder.record(query, named)
+	var result Result
+	if c.recorder.Rows != nil {
+		result = c.recorder.Rows(query, args)
+	}
+	return &rows{result: result}, nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for idx, arg := range args {
+		named[idx] = driver.NamedValue{Ordinal: idx + 1, Value: arg}
+	}
+	return named
+}
+
+type execResult struct{}
+
+func (execResult) LastInsertId() (int64, error) {
+	return 1, nil
+}
+
+func (execResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type tx struct {
+	recorder *Recorder
+}
+
+func (t tx) Commit() error {
+	t.recorder.record("COMMIT", nil)
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.recorder.record("ROLLBACK", nil)
+	return nil
+}
+
+type rows struct {
+	result Result
+	next   int
+}
+
+func (r *rows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	for idx, value := range r.result.Rows[r.next] {
+		dest[idx] = value
+	}
+	r.next++
+	return nil
+}
@@ -0,0 +1,37 @@
+package helper_test
+
+import (
+	"errors"
+	"testing"
+	"xsis-academy-test-service-movie/helper"
+)
+
+func TestTicketToken(t *testing.T) {
+	token, err := helper.SignTicket(12, 34, "ticket-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bookingID, seatID, err := helper.ParseTicket(token, "ticket-key")
+	if err != nil || bookingID != 12 || seatID != 34 {
+		t.Fatalf("got booking %d seat %d and %v, want booking 12 seat 34", bookingID, seatID, err)
+	}
+	if _, _, err := helper.ParseTicket(token, "other-key"); !errors.Is(err, helper.ErrInvalidToken) {
+		t.Fatalf("got %v, want a token of another secret rejected", err)
+	}
+}
+
+func TestTicketTokenWithoutSecret(t *testing.T) {
+	if _, err := helper.SignTicket(12, 34, ""); !errors.Is(err, helper.ErrNoTicketSecret) {
+		t.Fatalf("got %v, want nothing signed without a secret", err)
+	}
+
+	// A token signed with an empty key must not verify either
+	token, err := helper.SignTicket(12, 34, "ticket-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := helper.ParseTicket(token, ""); !errors.Is(err, helper.ErrInvalidToken) {
+		t.Fatalf("got %v, want every token rejected without a secret", err)
+	}
+}
@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token is expired")
+)
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+}
+
+type tokenClaims struct {
+	Sub  json.Number `json:"sub"`
+	Role string      `json:"role"`
+	Exp  int64       `json:"exp"`
+}
+
+// ParseToken verifies a HS256 JWT issued by the auth service and returns the user inside it
+func ParseToken(token string, secret string) (user domain.AuthUser, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return user, ErrInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return user, ErrInvalidToken
+	}
+
+	var header tokenHeader
+	err = decodeTokenPart(parts[0], &header)
+	if err != nil || header.Alg != "HS256" {
+		return user, ErrInvalidToken
+	}
+
+	var claims tokenClaims
+	err = decodeTokenPart(parts[1], &claims)
+	if err != nil {
+		return user, ErrInvalidToken
+	}
+
+	if claims.Exp != 0 && time.Now().Unix() > claims.Exp {
+		return user, ErrTokenExpired
+	}
+
+	userID, err := strconv.Atoi(claims.Sub.String())
+	if err != nil || userID <= 0 {
+		return user, ErrInvalidToken
+	}
+
+	return domain.AuthUser{ID: userID, Role: claims.Role}, nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/labstack/gommon/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	auditRedacted = "[REDACTED]"
+
+	localAuditBefore = "auditBefore"
+	localAuditAfter  = "auditAfter"
+)
+
+// Audit records every POST, PUT, PATCH and DELETE call with its actor, route, target and payloads once
+// the handler answered. A call that cannot be recorded is still answered, the failure is logged
+func Audit(AuditUseCase domain.AuditUseCase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		payload := auditPayload(c)
+
+		// The error handler answers here so the recorded status is the one sent
+		if err := c.Next(); err != nil {
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// Fiber reuses its buffers, the values are copied so the log stays valid after the request
+		audit := domain.AuditLog{
+			IP:        utils.CopyString(c.IP()),
+			UserAgent: string(c.Request().Header.UserAgent()),
+			Method:    utils.CopyString(c.Method()),
+			Route:     c.Route().Path,
+			Path:      utils.CopyString(c.Path()),
+			Status:    c.Response().StatusCode(),
+			Payload:   payload,
+			Response:  auditResponse(c),
+		}
+
+		// Routes without Auth still record the user of a valid token
+		user, ok := GetAuthUser(c)
+		if !ok {
+			user, ok, _ = authenticate(c)
+		}
+		if ok {
+			audit.UserID = &user.ID
+			audit.Role = &user.Role
+		}
+
+		audit.EntityID = auditEntityID(c)
+		audit.Before, _ = c.Locals(localAuditBefore).(*string)
+		audit.After, _ = c.Locals(localAuditAfter).(*string)
+
+		if err := AuditUseCase.PostAudit(c.Context(), audit); err != nil {
+			log.Error(err)
+		}
+		return nil
+	}
+}
+
+// AuditEntity loads the entity of the :id route parameter before the handler runs and again once it
+// answered, a created entity by the id answered. Audit records both snapshots, an entity not loaded
+// such as a deleted one is left empty
+func AuditEntity(load func(c *fiber.Ctx, id int) (interface{}, error)) fiber.Handler {
+	snapshot := func(c *fiber.Ctx, key string) {
+		id, err := strconv.Atoi(optional(auditEntityID(c)))
+		if err != nil {
+			return
+		}
+		entity, err := load(c, id)
+		if err != nil {
+			return
+		}
+		c.Locals(key, auditJSON(redactJSON(entity)))
+	}
+
+	return func(c *fiber.Ctx) error {
+		snapshot(c, localAuditBefore)
+
+		err := c.Next()
+		if err == nil && c.Response().StatusCode() < fiber.StatusBadRequest {
+			snapshot(c, localAuditAfter)
+		}
+		return err
+	}
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// auditEntityID is the ID of a created entity answered by the handler, otherwise the :id route parameter
+func auditEntityID(c *fiber.Ctx) *string {
+	if c.Response().StatusCode() == fiber.StatusCreated {
+		var created struct {
+			ID json.Number `json:"id"`
+		}
+		if json.Unmarshal(c.Response().Body(), &created) == nil && created.ID != "" {
+			id := created.ID.String()
+			return &id
+		}
+	}
+
+	if id := c.Params("id"); id != "" {
+		id = utils.CopyString(id)
+		return &id
+	}
+	return nil
+}
+
+// redacted tells whether a field holds a secret, a configured name matches any field containing it such
+// as access_token for token
+func redacted(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range viper.GetStringSlice("audit.redact") {
+		if field != "" && strings.Contains(key, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON replaces the secret fields at any depth of a value marshaled to JSON
+func redactJSON(value interface{}) interface{} {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if decoder.Decode(&decoded) != nil {
+		return nil
+	}
+	return redactValue(decoded)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if redacted(key) {
+				value[key] = auditRedacted
+				continue
+			}
+			value[key] = redactValue(field)
+		}
+	case []interface{}:
+		for idx, item := range value {
+			value[idx] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// auditBody is a JSON body with its secret fields redacted, otherwise its size and type as the content
+// is not known to be free of secrets
+func auditBody(body []byte, contentType string) *string {
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&decoded) == nil {
+		return auditJSON(redactValue(decoded))
+	}
+
+	value := "body:" + contentType + " (" + strconv.Itoa(len(body)) + " bytes)"
+	return &value
+}
+
+// auditPayload is the request body, the configured secret fields of a JSON object or a form are redacted
+// and uploaded files are replaced by their name
+func auditPayload(c *fiber.Ctx) *string {
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+
+	if strings.HasPrefix(contentType, fiber.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil
+		}
+
+		fields := map[string]interface{}{}
+		for key, values := range form.Value {
+			if redacted(key) {
+				fields[key] = auditRedacted
+				continue
+			}
+			fields[key] = values
+		}
+		for key, files := range form.File {
+			names := make([]string, len(files))
+			for idx, file := range files {
+				names[idx] = "file:" + file.Filename + " (" + strconv.FormatInt(file.Size, 10) + " bytes)"
+			}
+			fields[key] = names
+		}
+		return auditJSON(fields)
+	}
+
+	body := c.Body()
+	if len(body) == 0 {
+		return nil
+	}
+
+	if strings.HasPrefix(contentType, fiber.MIMEApplicationForm) {
+		args := c.Request().PostArgs()
+		fields := map[string]interface{}{}
+		args.VisitAll(func(key, value []byte) {
+			if redacted(string(key)) {
+				fields[string(key)] = auditRedacted
+				return
+			}
+			fields[string(key)] = string(value)
+		})
+		return auditJSON(fields)
+	}
+
+	return auditBody(body, contentType)
+}
+
+// auditResponse is the JSON response body with its secret fields redacted, or a plain text message
+func auditResponse(c *fiber.Ctx) *string {
+	contentType := strings.ToLower(string(c.Response().Header.ContentType()))
+	body := c.Response().Body()
+	if len(body) == 0 {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEApplicationJSON):
+		return auditBody(body, contentType)
+	case strings.HasPrefix(contentType, "text/plain"):
+		value := string(body)
+		return &value
+	}
+	return nil
+}
+
+func auditJSON(fields interface{}) *string {
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	value := string(content)
+	return &value
+}
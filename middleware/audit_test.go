@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+type auditUseCase struct {
+	domain.AuditUseCase
+	logs []domain.AuditLog
+}
+
+func (adu *auditUseCase) PostAudit(ctx context.Context, request domain.AuditLog) error {
+	adu.logs = append(adu.logs, request)
+	return nil
+}
+
+type account struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Password string            `json:"password"`
+	Keys     map[string]string `json:"keys"`
+}
+
+func decode(t *testing.T, value *string) (fields map[string]interface{}) {
+	t.Helper()
+	if value == nil {
+		t.Fatal("got no value, want a JSON object")
+	}
+	if err := json.Unmarshal([]byte(*value), &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestAuditEntity(t *testing.T) {
+	viper.Set("audit.redact", []string{"password", "token"})
+	t.Cleanup(viper.Reset)
+
+	accounts := map[int]account{
+		4: {ID: 4, Name: "before", Password: "hunter2", Keys: map[string]string{"api_token": "k-1", "label": "main"}},
+	}
+	load := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		return accounts[id], nil
+	})
+
+	audits := &auditUseCase{}
+	app := fiber.New()
+	app.Use(middleware.Audit(audits))
+	app.Patch("/account/:id", load, func(c *fiber.Ctx) error {
+		changed := accounts[4]
+		changed.Name = "after"
+		accounts[4] = changed
+		return c.JSON(fiber.Map{"data": fiber.Map{"id": 4, "access_token": "t-1"}})
+	})
+
+	body := `{"name": "after", "credentials": {"password": "hunter3"}, "tags": [{"refresh_token": "r-1"}]}`
+	req := httptest.NewRequest(fiber.MethodPatch, "/account/4", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK || len(audits.logs) != 1 {
+		t.Fatalf("got status %d and %d logs", resp.StatusCode, len(audits.logs))
+	}
+	audit := audits.logs[0]
+
+	before, after := decode(t, audit.Before), decode(t, audit.After)
+	if before["name"] != "before" || after["name"] != "after" {
+		t.Fatalf("got before %v and after %v, want the entity before and after the change", before, after)
+	}
+	for _, snapshot := range []map[string]interface{}{before, after} {
+		keys := snapshot["keys"].(map[string]interface{})
+		if snapshot["password"] != "[REDACTED]" || keys["api_token"] != "[REDACTED]" || keys["label"] != "main" {
+			t.Fatalf("got snapshot %v, want the secrets redacted", snapshot)
+		}
+	}
+
+	for _, secret := range []string{"hunter", "r-1", "t-1", "k-1"} {
+		for _, value := range []*string{audit.Payload, audit.Response, audit.Before, audit.After} {
+			if strings.Contains(*value, secret) {
+				t.Fatalf("got %s, want %s redacted", *value, secret)
+			}
+		}
+	}
+	if payload := decode(t, audit.Payload); payload["name"] != "after" {
+		t.Fatalf("got payload %v, want the other fields kept", payload)
+	}
+}
+
+func TestAuditPayloadNotJSON(t *testing.T) {
+	audits := &auditUseCase{}
+	app := fiber.New()
+	app.Use(middleware.Audit(audits))
+	app.Post("/upload", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/upload", strings.NewReader("password=hunter2"))
+	req.Header.Set(fiber.HeaderContentType, "text/plain")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	// A body of an unknown format is described instead of copied
+	if len(audits.logs) != 1 || audits.logs[0].Payload == nil || *audits.logs[0].Payload != "body:text/plain (16 bytes)" {
+		t.Fatalf("got %+v", audits.logs)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// LocalsAuthUser is the fiber locals key holding the authenticated domain.AuthUser
+const LocalsAuthUser = "auth_user"
+
+// Auth rejects the request when the bearer token is missing or invalid
+func Auth(c *fiber.Ctx) error {
+	user, ok, err := authenticate(c)
+	if err != nil {
+		if err == helper.ErrTokenExpired {
+			return helper.HttpResponseError(c, constant.StatusUnauthorizedTokenExpired, err.Error())
+		}
+		return helper.HttpResponseError(c, constant.StatusForbiddenInvalidToken, err.Error())
+	}
+	if !ok {
+		return helper.HttpSimpleResponse(c, fiber.StatusUnauthorized)
+	}
+
+	c.Locals(LocalsAuthUser, user)
+	return c.Next()
+}
+
+// OptionalAuth sets the authenticated user when a valid bearer token is sent, anonymous request is allowed
+func OptionalAuth(c *fiber.Ctx) error {
+	user, ok, err := authenticate(c)
+	if err == nil && ok {
+		c.Locals(LocalsAuthUser, user)
+	}
+	return c.Next()
+}
+
+// Role only allows users having one of the roles, it must be registered after Auth
+func Role(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := GetAuthUser(c)
+		if !ok {
+			return helper.HttpSimpleResponse(c, fiber.StatusUnauthorized)
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				return c.Next()
+			}
+		}
+		return helper.HttpSimpleResponse(c, fiber.StatusForbidden)
+	}
+}
+
+// GetAuthUser returns the user set by Auth or OptionalAuth
+func GetAuthUser(c *fiber.Ctx) (user domain.AuthUser, ok bool) {
+	user, ok = c.Locals(LocalsAuthUser).(domain.AuthUser)
+	return
+}
+
+func authenticate(c *fiber.Ctx) (user domain.AuthUser, ok bool, err error) {
+	authorization := c.Get(fiber.HeaderAuthorization)
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return user, false, nil
+	}
+
+	secret := viper.GetString("auth.secret")
+	if secret == "" {
+		return user, false, helper.ErrInvalidToken
+	}
+
+	user, err = helper.ParseToken(strings.TrimPrefix(authorization, "Bearer "), secret)
+	if err != nil {
+		return user, false, err
+	}
+	return user, true, nil
+}
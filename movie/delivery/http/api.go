@@ -4,6 +4,7 @@ import (
 	"xsis-academy-test-service-movie/movie/delivery/http/handler"
 	// "xsis-academy-test-service-movie/delivery/http/handler"
 	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -23,11 +24,24 @@ func RouterAPI(app *fiber.App, MovieUseCase domain.MovieUseCase) {
 	}))
 
 	log.Info(handlerMovie)
+	// The audit log records the movie before and after every change
+	auditMovie := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		user, _ := middleware.GetAuthUser(c)
+		return MovieUseCase.GetDetailMovie(c.Context(), id, &user, "")
+	})
+
 	// Public API Route
-	movie.Get("/movie", handlerMovie.GetAllMovie)
-	movie.Post("/movie", handlerMovie.PostMovie)
-	movie.Delete("/movie/:id", handlerMovie.DeleteMovie)
-	movie.Patch("/movie/:id", handlerMovie.UpdateMovie)
-	movie.Get("/movie/:id", handlerMovie.GetDetailMovie)
+	movie.Get("/movie", middleware.OptionalAuth, handlerMovie.GetAllMovie)
+	movie.Get("/movie/trending", handlerMovie.GetTrendingMovie)
+	movie.Get("/movie/export", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerMovie.ExportMovie)
+	movie.Post("/movie", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerMovie.PostMovie)
+	movie.Post("/movie/import", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerMovie.ImportMovie)
+	movie.Post("/movie/batch", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerMovie.BatchMovie)
+	movie.Delete("/movie/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditMovie, handlerMovie.DeleteMovie)
+	movie.Patch("/movie/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditMovie, handlerMovie.UpdateMovie)
+	movie.Patch("/movie/:id/status", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditMovie, handlerMovie.UpdateMovieStatus)
+	movie.Get("/movie/:id", middleware.OptionalAuth, handlerMovie.GetDetailMovie)
+	movie.Get("/movie/:id/revisions", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerMovie.GetAllMovieRevision)
+	movie.Post("/movie/:id/revisions/:rev/restore", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), auditMovie, handlerMovie.RestoreMovieRevision)
 
 }
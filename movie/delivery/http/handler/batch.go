@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+// BatchMovie runs a list of create, update and delete operations and answers a result per operation
+func (mh *MovieHandler) BatchMovie(c *fiber.Ctx) (err error) {
+	var input domain.RequestMovieBatch
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+
+	res, err := mh.MovieUseCase.BatchMovie(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	// Nothing was written, the results tell which operations to fix
+	if !res.Committed {
+		return c.Status(fasthttp.StatusUnprocessableEntity).JSON(res)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
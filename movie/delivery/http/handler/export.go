@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/spf13/viper"
+	"github.com/valyala/fasthttp"
+)
+
+// exportContext bounds an export stream by movie.export_timeout, so a stalled download does not hold the
+// query open forever
+func exportContext() (context.Context, context.CancelFunc) {
+	if timeout := viper.GetInt("movie.export_timeout"); timeout > 0 {
+		return context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// ExportMovie streams the movies matching the list filters, the request is checked before the first byte
+// is sent since a later failure can only cut the file
+func (mh *MovieHandler) ExportMovie(c *fiber.Ctx) (err error) {
+	input, err := helper.MovieFilter(c.Query)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+
+	format := c.Query("format", domain.ImportFormatCSV)
+	switch format {
+	case domain.ImportFormatCSV:
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	case domain.ImportFormatNDJSON:
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	case domain.ExportFormatXLSX:
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	default:
+		return c.Status(fasthttp.StatusBadRequest).SendString("format must be csv, ndjson or xlsx")
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="movies.`+format+`"`)
+
+	// The stream outlives the handler and its request context
+	ctx, cancel := exportContext()
+	c.Status(fasthttp.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		if err := mh.MovieUseCase.ExportMovie(ctx, input, format, w); err != nil {
+			log.Error(err)
+		}
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"archive/zip"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+func (mh *MovieHandler) ImportMovie(c *fiber.Ctx) (err error) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fasthttp.StatusBadRequest).SendString("No upload file")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusInternalServerError)
+	}
+	defer src.Close()
+
+	input := domain.RequestMovieImport{
+		File:   src,
+		Format: helper.RecordFormat(c.FormValue("format"), file.Filename),
+		Mode:   c.FormValue("mode"),
+	}
+
+	if images, err := c.FormFile("images"); err == nil {
+		zipFile, err := images.Open()
+		if err != nil {
+			log.Error(err)
+			return helper.HttpSimpleResponse(c, fasthttp.StatusInternalServerError)
+		}
+		defer zipFile.Close()
+
+		input.Images, err = zip.NewReader(zipFile, images.Size)
+		if err != nil {
+			return c.Status(fasthttp.StatusBadRequest).SendString("images must be a zip file")
+		}
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+
+	res, err := mh.MovieUseCase.ImportMovie(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	// Nothing was written, the report tells which rows to fix
+	if !res.Committed {
+		return c.Status(fasthttp.StatusUnprocessableEntity).JSON(res)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"xsis-academy-test-service-movie/domain"
 	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/labstack/gommon/log"
@@ -17,10 +18,9 @@ type MovieHandler struct {
 }
 
 func (mh *MovieHandler) GetAllMovie(c *fiber.Ctx) error {
-	var input domain.RequestParamMovie
-	search := c.Query("search")
-	if search != "" {
-		input.Search = &search
+	input, err := helper.MovieFilter(c.Query)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
 	}
 
 	limit := c.Query("limit")
@@ -51,12 +51,10 @@ func (mh *MovieHandler) GetAllMovie(c *fiber.Ctx) error {
 		input.Page = &pageInt
 	}
 
-	order := c.Query("order")
-	if order == "" {
-		input.Order = nil
-	} else {
-		input.Order = &order
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
 	}
+
 	res, err := mh.MovieUseCase.GetAllMovie(c.Context(), input)
 	if err != nil {
 		return c.SendStatus(fasthttp.StatusInternalServerError)
@@ -92,10 +90,13 @@ func (mh *MovieHandler) PostMovie(c *fiber.Ctx) (err error) {
 	}
 
 	input.FloatRating = ratingFloat
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
 	err = mh.MovieUseCase.PostMovie(c.Context(), input)
 	if err != nil {
 		log.Error(err)
-		return helper.HttpSimpleResponse(c, fasthttp.StatusInternalServerError)
+		return helper.HttpResponseFromError(c, err)
 	}
 	return c.SendStatus(fasthttp.StatusCreated)
 }
@@ -135,10 +136,13 @@ func (mh *MovieHandler) UpdateMovie(c *fiber.Ctx) (err error) {
 	}
 
 	input.FloatRating = ratingFloat
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
 	err = mh.MovieUseCase.UpdateMovie(c.Context(), int(id), input)
 	if err != nil {
 		log.Error(err)
-		return helper.HttpSimpleResponse(c, fasthttp.StatusInternalServerError)
+		return helper.HttpResponseFromError(c, err)
 	}
 	return c.Status(fasthttp.StatusOK).SendString("Updated")
 }
@@ -150,7 +154,12 @@ func (mh *MovieHandler) DeleteMovie(c *fiber.Ctx) (err error) {
 		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
 	}
 
-	err = mh.MovieUseCase.DeleteMovie(c.Context(), int(id))
+	var user *domain.AuthUser
+	if authUser, ok := middleware.GetAuthUser(c); ok {
+		user = &authUser
+	}
+
+	err = mh.MovieUseCase.DeleteMovie(c.Context(), int(id), user)
 	if err != nil {
 		if err.Error() == "Not found" {
 			return helper.HttpSimpleResponse(c, fasthttp.StatusNotFound)
@@ -167,7 +176,15 @@ func (mh *MovieHandler) GetDetailMovie(c *fiber.Ctx) (err error) {
 		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
 	}
 
-	res, err := mh.MovieUseCase.GetDetailMovie(c.Context(), int(id))
+	// Views are deduplicated per user, or per address and user agent for anonymous clients
+	var user *domain.AuthUser
+	viewer := "ip:" + c.IP() + "|" + string(c.Request().Header.UserAgent())
+	if authUser, ok := middleware.GetAuthUser(c); ok {
+		user = &authUser
+		viewer = "user:" + strconv.Itoa(authUser.ID)
+	}
+
+	res, err := mh.MovieUseCase.GetDetailMovie(c.Context(), int(id), user, viewer)
 	if err != nil {
 		if err.Error() == "Not found" {
 			return helper.HttpSimpleResponse(c, fasthttp.StatusNotFound)
@@ -176,3 +193,86 @@ func (mh *MovieHandler) GetDetailMovie(c *fiber.Ctx) (err error) {
 	}
 	return c.Status(fasthttp.StatusOK).JSON(res)
 }
+
+func (mh *MovieHandler) GetTrendingMovie(c *fiber.Ctx) (err error) {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := mh.MovieUseCase.GetTrendingMovie(c.Context(), c.Query("window", domain.TrendingWindowDay), limit)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (mh *MovieHandler) UpdateMovieStatus(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestMovieStatus
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+
+	res, err := mh.MovieUseCase.UpdateMovieStatus(c.Context(), int(id), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (mh *MovieHandler) GetAllMovieRevision(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input := domain.RequestParamMovieRevision{MovieID: int(id)}
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := mh.MovieUseCase.GetAllMovieRevision(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (mh *MovieHandler) RestoreMovieRevision(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	revision, err := strconv.Atoi(c.Params("rev"))
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var user *domain.AuthUser
+	if authUser, ok := middleware.GetAuthUser(c); ok {
+		user = &authUser
+	}
+
+	res, err := mh.MovieUseCase.RestoreMovieRevision(c.Context(), int(id), revision, user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
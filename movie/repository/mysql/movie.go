@@ -3,7 +3,9 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 	"xsis-academy-test-service-movie/domain"
 
@@ -18,30 +20,142 @@ func NewMySQLMovieRepository(Conn *sql.DB) domain.MovieMySQLRepo {
 	return &mysqlMovieRepository{Conn}
 }
 
+const movieQuery = `SELECT movie.id, movie.external_id, movie.title, movie.description, movie.rating, movie.runtime, movie.release_date,
+                  movie.status, movie.publish_at, movie.image, movie.view_count, movie.popularity, movie.dtm_crt, movie.dtm_upd
+              FROM movie`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMovie(row rowScanner) (response domain.ResponseMovie, err error) {
+	var externalID sql.NullString
+	var releaseDate, publishAt sql.NullTime
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&externalID,
+		&response.Title,
+		&response.Description,
+		&response.Rating,
+		&response.Runtime,
+		&releaseDate,
+		&response.Status,
+		&publishAt,
+		&response.Image,
+		&response.ViewCount,
+		&response.Popularity,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	if externalID.Valid {
+		response.ExternalID = &externalID.String
+	}
+	if releaseDate.Valid {
+		release := releaseDate.Time.Format(domain.ReleaseDateLayout)
+		response.ReleaseDate = &release
+	}
+	if publishAt.Valid {
+		publish := publishAt.Time.Format(domain.PublishAtLayout)
+		response.PublishAt = &publish
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// releaseDate stores an empty release date as NULL
+func releaseDate(request domain.RequestMovie) interface{} {
+	if request.ReleaseDate == "" {
+		return nil
+	}
+	return request.ReleaseDate
+}
+
+// snapshotMovie keeps the content of the movie stored by a revision
+func snapshotMovie(movie domain.ResponseMovie) domain.MovieSnapshot {
+	return domain.MovieSnapshot{
+		Title:       movie.Title,
+		Description: movie.Description,
+		Rating:      movie.Rating,
+		Runtime:     movie.Runtime,
+		ReleaseDate: movie.ReleaseDate,
+		Status:      movie.Status,
+		PublishAt:   movie.PublishAt,
+		Image:       movie.Image,
+	}
+}
+
+// insertSnapshot stores the snapshot as the next revision of the movie
+func insertSnapshot(ctx context.Context, tx *sql.Tx, movieID int, action string, snapshot domain.MovieSnapshot, user *domain.AuthUser) (err error) {
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var userID interface{}
+	if user != nil {
+		userID = user.ID
+	}
+
+	query := `INSERT INTO movie_revision (movie_id, revision, action, user_id, snapshot, dtm_crt)
+              SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, NOW() FROM movie_revision WHERE movie_id = ?`
+	_, err = tx.ExecContext(ctx, query, movieID, action, userID, content, movieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// insertRevision stores the current content of the movie as its next revision
+func insertRevision(ctx context.Context, tx *sql.Tx, movieID int, action string, user *domain.AuthUser) (err error) {
+	movie, err := scanMovie(tx.QueryRowContext(ctx, movieQuery+` WHERE movie.id = ?`, movieID))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return insertSnapshot(ctx, tx, movieID, action, snapshotMovie(movie), user)
+}
+
 func (db *mysqlMovieRepository) PostMovie(ctx context.Context, request domain.RequestMovie) (err error) {
-	query := `INSERT INTO movie (title, description, rating, image, dtm_crt, dtm_upd)
-              VALUES (?, ?, ?, ?, NOW(), NOW())`
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err = db.Conn.ExecContext(ctx, query, request.Title, request.Description, request.Rating, request.ImagePath)
+	query := `INSERT INTO movie (title, description, rating, runtime, release_date, image, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := tx.ExecContext(ctx, query, request.Title, request.Description, request.Rating, request.Runtime, releaseDate(request), request.ImagePath)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
 
+	err = insertRevision(ctx, tx, int(id), domain.MovieRevisionCreate, request.User)
 	if err != nil {
 		return err
 	}
 
-	return
+	return tx.Commit()
 }
 
 func (db *mysqlMovieRepository) CountDataMovie(ctx context.Context, request domain.RequestParamMovie) (response domain.MetaData, err error) {
-	var query string
-	query = "SELECT COUNT(id) as total FROM movie WHERE 1=1"
 	var limit, page int
-	var order string
-	var args []interface{}
-
-	if request.Search != nil {
-		query += " AND title LIKE ? OR description LIKE ? OR rating LIKE ?"
-		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%", "%"+*request.Search+"%")
-	}
+	filter, args := filterMovie(request)
+	query := "SELECT COUNT(movie.id) as total FROM movie" + filter
 
 	if request.Limit != nil {
 		limit = *request.Limit
@@ -51,16 +165,6 @@ func (db *mysqlMovieRepository) CountDataMovie(ctx context.Context, request doma
 		page = *request.Page
 	}
 
-	if request.Order != nil {
-		order = *request.Order
-		query += " ORDER BY " + order
-	}
-
-	if limit > 0 {
-		query += " LIMIT ? OFFSET ?"
-		args = append(args, limit, (page-1)*limit)
-	}
-
 	log.Debug(query)
 
 	var count int
@@ -74,50 +178,117 @@ func (db *mysqlMovieRepository) CountDataMovie(ctx context.Context, request doma
 		return domain.MetaData{}, err
 	}
 
-	totalPage := uint(count) / uint(*request.Limit)
-	if uint(count)%uint(*request.Limit) != 0 {
-		totalPage++
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
 	}
 
 	response = domain.MetaData{
 		TotalData: uint(count),
 		TotalPage: totalPage,
 		Page:      uint(page),
-		Limit:     uint(*request.Limit),
+		Limit:     uint(limit),
 	}
 
 	return response, nil
 }
 
+// filterMovie builds the join and where clause shared by the movie list and its count
+func filterMovie(request domain.RequestParamMovie) (query string, args []interface{}) {
+	if request.Collection != nil {
+		query += " JOIN collection_movie ON collection_movie.movie_id = movie.id AND collection_movie.collection_id = ?"
+		args = append(args, *request.Collection)
+	}
+
+	if request.UserMovie != nil {
+		query += " JOIN user_movie ON user_movie.movie_id = movie.id AND user_movie.user_id = ? AND user_movie.type = ?"
+		args = append(args, request.UserMovie.UserID, request.UserMovie.Type)
+	}
+
+	query += " WHERE 1=1"
+
+	if request.Status != nil {
+		query += " AND movie.status = ?"
+		args = append(args, *request.Status)
+	}
+
+	if request.Search != nil {
+		query += " AND (movie.title LIKE ? OR movie.description LIKE ? OR movie.rating LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	if request.Tag != nil {
+		query += " AND EXISTS (SELECT 1 FROM movie_tag JOIN tag ON tag.id = movie_tag.tag_id WHERE movie_tag.movie_id = movie.id AND tag.slug = ?)"
+		args = append(args, *request.Tag)
+	}
+
+	if request.AvailableOn != nil || request.Region != nil {
+		query += ` AND EXISTS (SELECT 1 FROM movie_availability ma JOIN watch_provider wp ON wp.id = ma.provider_id
+              WHERE ma.movie_id = movie.id
+              AND (ma.valid_from IS NULL OR ma.valid_from <= CURDATE()) AND (ma.valid_to IS NULL OR ma.valid_to >= CURDATE())`
+		if request.AvailableOn != nil {
+			query += " AND wp.slug = ?"
+			args = append(args, *request.AvailableOn)
+		}
+		if request.Region != nil {
+			query += " AND ma.region = ?"
+			args = append(args, *request.Region)
+		}
+		query += ")"
+	}
+
+	return query, args
+}
+
 func (db *mysqlMovieRepository) UpdateMovie(ctx context.Context, id int, request domain.RequestMovie) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE movie
-              SET title = ?, description = ?, rating = ?, image = ?, dtm_upd = NOW()
+              SET title = ?, description = ?, rating = ?, runtime = ?, release_date = ?, image = ?, dtm_upd = NOW()
               WHERE id = ?`
 
-	_, err = db.Conn.ExecContext(ctx, query, request.Title, request.Description, request.Rating, request.ImagePath, id)
+	_, err = tx.ExecContext(ctx, query, request.Title, request.Description, request.Rating, request.Runtime, releaseDate(request), request.ImagePath, id)
+	if err != nil {
+		return err
+	}
 
+	err = insertRevision(ctx, tx, id, domain.MovieRevisionUpdate, request.User)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+// orderMovie is the order clause shared by the movie list and the export
+func orderMovie(request domain.RequestParamMovie) (query string) {
+	if request.Order != nil && *request.Order == domain.MovieOrderPopularity {
+		query = " ORDER BY movie.popularity DESC, movie.id"
+	} else if request.Order != nil && *request.Order == domain.MovieOrderDesc {
+		query = " ORDER BY movie.id DESC"
+	} else if request.Order != nil && *request.Order == domain.MovieOrderAsc {
+		query = " ORDER BY movie.id"
+	} else if request.Collection != nil {
+		query = " ORDER BY collection_movie.position"
+	} else if request.UserMovie != nil {
+		query = " ORDER BY user_movie.dtm_crt DESC"
+	}
+	return query
 }
 
 func (db *mysqlMovieRepository) GetAllMovie(ctx context.Context, request domain.RequestParamMovie) (response []domain.ResponseMovie, err error) {
-	query := `SELECT id, title, description, rating, image, dtm_crt, dtm_upd FROM movie`
 	var limit, page int
-	var order string
-	var args []interface{}
-
-	if request.Search != nil {
-		query += " WHERE title LIKE ? OR description LIKE ? OR rating LIKE ?"
-		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%", "%"+*request.Search+"%")
-	}
+	filter, args := filterMovie(request)
+	query := movieQuery + filter
 
-	if request.Order != nil {
-		order = *request.Order
-		query += " ORDER BY " + order
-	}
+	query += orderMovie(request)
 	if request.Page != nil {
 		page = *request.Page
 	}
@@ -140,67 +311,741 @@ func (db *mysqlMovieRepository) GetAllMovie(ctx context.Context, request domain.
 
 	var movies []domain.ResponseMovie
 	for rows.Next() {
-		var i domain.ResponseMovie
-		var dtmCrt, dtmUpd time.Time
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Rating,
-			&i.Image,
-			&dtmCrt,
-			&dtmUpd,
-		); err != nil {
+		i, err := scanMovie(rows)
+		if err != nil {
 			log.Error(err)
 			return nil, err
 		}
-
-		i.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
-		i.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
-
 		movies = append(movies, i)
 	}
 
 	return movies, nil
 }
 
-func (db *mysqlMovieRepository) DeleteMovie(ctx context.Context, id int) (err error) {
-	query := `DELETE FROM movie WHERE id = ?`
-	_, err = db.Conn.ExecContext(ctx, query, id)
+// EachMovie calls fn with every movie matching the list filters, reading one row at a time
+func (db *mysqlMovieRepository) EachMovie(ctx context.Context, request domain.RequestParamMovie, fn func(domain.ResponseMovie) error) (err error) {
+	filter, args := filterMovie(request)
+	query := movieQuery + filter
+	if order := orderMovie(request); order != "" {
+		query += order
+	} else {
+		query += " ORDER BY movie.id"
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanMovie(rows)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// DeleteMovie deletes the movie, its last content is kept by a delete revision
+func (db *mysqlMovieRepository) DeleteMovie(ctx context.Context, id int, user *domain.AuthUser) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = deleteMovie(ctx, tx, id, user)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteMovie deletes the movie and keeps its content as the delete revision, a movie having showtimes with
+// bookings returns Booked
+func deleteMovie(ctx context.Context, tx *sql.Tx, id int, user *domain.AuthUser) (err error) {
+	movie, err := scanMovie(tx.QueryRowContext(ctx, movieQuery+` WHERE movie.id = ? FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Not found")
+		}
+		log.Error(err)
+		return err
+	}
+
+	// The showtimes are deleted by cascade, their bookings are kept as the sales history
+	var count int
+	query := `SELECT COUNT(*) FROM booking b JOIN showtime s ON s.id = b.showtime_id WHERE s.movie_id = ? FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, id).Scan(&count)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
+	if count > 0 {
+		return errors.New("Booked")
+	}
 
-	return
+	_, err = tx.ExecContext(ctx, `DELETE FROM movie WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return insertSnapshot(ctx, tx, id, domain.MovieRevisionDelete, snapshotMovie(movie), user)
 }
 
 func (db *mysqlMovieRepository) GetDetailMovie(ctx context.Context, id int) (response domain.ResponseMovie, err error) {
-	query := `SELECT id, title, description, rating, image, dtm_crt, dtm_upd FROM movie WHERE id = ?`
+	response, err = scanMovie(db.Conn.QueryRowContext(ctx, movieQuery+` WHERE movie.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseMovie{}, err
+		}
+		log.Error(err)
+		return domain.ResponseMovie{}, err
+	}
 
-	row := db.Conn.QueryRowContext(ctx, query, id)
-	var dtmCrt, dtmUpd time.Time
+	return response, nil
+}
+
+func (db *mysqlMovieRepository) GetMovieByIDs(ctx context.Context, ids []int) (response map[int]domain.ResponseMovie, err error) {
+	response = map[int]domain.ResponseMovie{}
+	if len(ids) == 0 {
+		return response, nil
+	}
+
+	args := []interface{}{}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	query := movieQuery + ` WHERE movie.id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanMovie(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[int(i.ID)] = i
+	}
+
+	return response, nil
+}
+
+// GetMovieByRelease returns the published movies released from one day up to another day excluded, by release date
+func (db *mysqlMovieRepository) GetMovieByRelease(ctx context.Context, from string, to string) (response []domain.ResponseMovie, err error) {
+	query := movieQuery + ` WHERE movie.status = ? AND movie.release_date >= ? AND movie.release_date < ? ORDER BY movie.release_date, movie.id`
+
+	rows, err := db.Conn.QueryContext(ctx, query, domain.MovieStatusPublished, from, to)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanMovie(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+// UpdateMovieView adds the flushed view counts and replaces the popularity of every movie,
+// movies missing from popularity have no recent view and fall back to zero. The flush ID is recorded
+// in the same transaction, so a batch already added is not counted again when it is flushed twice
+func (db *mysqlMovieRepository) UpdateMovieView(ctx context.Context, flushID string, views map[int]int64, popularity []domain.MovieScore) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if flushID != "" {
+		res, err := tx.ExecContext(ctx, `INSERT IGNORE INTO movie_view_flush (flush_id) VALUES (?)`, flushID)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			log.Warnf("view batch %s is already counted", flushID)
+			views = nil
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM movie_view_flush WHERE dtm_crt < NOW() - INTERVAL 7 DAY`)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	for movieID, count := range views {
+		_, err = tx.ExecContext(ctx, `UPDATE movie SET view_count = view_count + ? WHERE id = ?`, count, movieID)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE movie SET popularity = 0 WHERE popularity <> 0`)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	for _, score := range popularity {
+		_, err = tx.ExecContext(ctx, `UPDATE movie SET popularity = ? WHERE id = ?`, score.Score, score.MovieID)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateMovieStatus moves the movie from one status to another and replaces its publish time,
+// "Conflict" is returned when the movie is no longer in the from status
+func (db *mysqlMovieRepository) UpdateMovieStatus(ctx context.Context, id int, from string, to string, publishAt *string, user *domain.AuthUser) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE movie SET status = ?, publish_at = ?, dtm_upd = NOW() WHERE id = ? AND status = ?`, to, publishAt, id, from)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Conflict")
+	}
+
+	err = insertRevision(ctx, tx, id, domain.MovieRevisionStatus, user)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PublishScheduledMovie publishes the movies in review whose publish time is reached
+func (db *mysqlMovieRepository) PublishScheduledMovie(ctx context.Context) (published int64, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM movie WHERE status = ? AND publish_at <= NOW() FOR UPDATE`, domain.MovieStatusReview)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Error(err)
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		_, err = tx.ExecContext(ctx, `UPDATE movie SET status = ?, dtm_upd = NOW() WHERE id = ?`, domain.MovieStatusPublished, id)
+		if err != nil {
+			log.Error(err)
+			return 0, err
+		}
+
+		err = insertRevision(ctx, tx, id, domain.MovieRevisionPublish, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(ids)), tx.Commit()
+}
+
+// RestoreMovie replaces the content of the movie by the snapshot, keeping its status. A deleted movie is
+// created again with its former ID as a draft
+func (db *mysqlMovieRepository) RestoreMovie(ctx context.Context, id int, snapshot domain.MovieSnapshot, user *domain.AuthUser) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(id) FROM movie WHERE id = ? FOR UPDATE`, id).Scan(&count)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if count > 0 {
+		query := `UPDATE movie
+              SET title = ?, description = ?, rating = ?, runtime = ?, release_date = ?, image = ?, dtm_upd = NOW()
+              WHERE id = ?`
+		_, err = tx.ExecContext(ctx, query, snapshot.Title, snapshot.Description, snapshot.Rating, snapshot.Runtime, snapshot.ReleaseDate, snapshot.Image, id)
+	} else {
+		query := `INSERT INTO movie (id, title, description, rating, runtime, release_date, image, status, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+		_, err = tx.ExecContext(ctx, query, id, snapshot.Title, snapshot.Description, snapshot.Rating, snapshot.Runtime, snapshot.ReleaseDate, snapshot.Image, domain.MovieStatusDraft)
+	}
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = insertRevision(ctx, tx, id, domain.MovieRevisionRestore, user)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const revisionQuery = `SELECT id, movie_id, revision, action, user_id, snapshot, dtm_crt FROM movie_revision`
+
+func scanRevision(row rowScanner) (response domain.ResponseMovieRevision, err error) {
+	var userID sql.NullInt32
+	var snapshot []byte
+	var dtmCrt time.Time
 	err = row.Scan(
 		&response.ID,
-		&response.Title,
-		&response.Description,
-		&response.Rating,
-		&response.Image,
+		&response.MovieID,
+		&response.Revision,
+		&response.Action,
+		&userID,
+		&snapshot,
 		&dtmCrt,
-		&dtmUpd,
 	)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(snapshot, &response.Snapshot)
+	if err != nil {
+		return response, err
+	}
+	if userID.Valid {
+		id := int(userID.Int32)
+		response.UserID = &id
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	return response, nil
+}
 
+func (db *mysqlMovieRepository) CountDataMovieRevision(ctx context.Context, request domain.RequestParamMovieRevision) (response domain.MetaData, err error) {
+	var count int
+	err = db.Conn.QueryRowContext(ctx, `SELECT COUNT(id) FROM movie_revision WHERE movie_id = ?`, request.MovieID).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+// GetAllMovieRevision returns the revisions of the movie, newest first
+func (db *mysqlMovieRepository) GetAllMovieRevision(ctx context.Context, request domain.RequestParamMovieRevision) (response []domain.ResponseMovieRevision, err error) {
+	query := revisionQuery + ` WHERE movie_id = ? ORDER BY revision DESC`
+	args := []interface{}{request.MovieID}
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanRevision(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlMovieRepository) GetMovieRevision(ctx context.Context, movieID int, revision int) (response domain.ResponseMovieRevision, err error) {
+	response, err = scanRevision(db.Conn.QueryRowContext(ctx, revisionQuery+` WHERE movie_id = ? AND revision = ?`, movieID, revision))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = errors.New("Not found")
-			return domain.ResponseMovie{}, err
+			return domain.ResponseMovieRevision{}, err
 		}
 		log.Error(err)
-		return domain.ResponseMovie{}, err
+		return domain.ResponseMovieRevision{}, err
 	}
 
-	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
-	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// updateMovie replaces the content of the movie, an empty image keeps the image of the movie
+func updateMovie(ctx context.Context, tx *sql.Tx, id int, request domain.RequestMovie) (err error) {
+	query := `UPDATE movie
+              SET title = ?, description = ?, rating = ?, runtime = ?, release_date = ?, image = COALESCE(NULLIF(?, ''), image), dtm_upd = NOW()
+              WHERE id = ?`
+	_, err = tx.ExecContext(ctx, query, request.Title, request.Description, request.FloatRating, request.Runtime, releaseDate(request), request.ImagePath, id)
+	if err != nil {
+		return err
+	}
+	return insertRevision(ctx, tx, id, domain.MovieRevisionUpdate, request.User)
+}
+
+// upsertMovie updates the movie having the external ID, otherwise creates it. An empty image keeps the image
+// of an updated movie
+func upsertMovie(ctx context.Context, tx *sql.Tx, movie domain.MovieImport, user *domain.AuthUser) (id int, created bool, err error) {
+	request := movie.Request
+	request.User = user
+
+	if movie.ExternalID != "" {
+		err = tx.QueryRowContext(ctx, `SELECT id FROM movie WHERE external_id = ? FOR UPDATE`, movie.ExternalID).Scan(&id)
+		if err == nil {
+			return id, false, updateMovie(ctx, tx, id, request)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, false, err
+		}
+	}
+
+	var externalID interface{}
+	if movie.ExternalID != "" {
+		externalID = movie.ExternalID
+	}
+
+	query := `INSERT INTO movie (external_id, title, description, rating, runtime, release_date, image, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+	res, err := tx.ExecContext(ctx, query, externalID, request.Title, request.Description, request.FloatRating, request.Runtime, releaseDate(request), request.ImagePath)
+	if err != nil {
+		return 0, false, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return int(lastID), true, insertRevision(ctx, tx, int(lastID), domain.MovieRevisionCreate, user)
+}
+
+// ImportMovie writes the movies in one transaction when atomic, the first failure rolls every movie back
+// and is returned. Otherwise each movie has its own transaction and a failure only fails its row
+func (db *mysqlMovieRepository) ImportMovie(ctx context.Context, movies []domain.MovieImport, atomic bool, user *domain.AuthUser) (response []domain.ResponseMovieImportRow, err error) {
+	if atomic {
+		tx, err := db.Conn.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		for _, movie := range movies {
+			id, created, err := upsertMovie(ctx, tx, movie, user)
+			if err != nil {
+				log.Error(err)
+				return nil, err
+			}
+			response = append(response, importRow(movie, id, created))
+		}
+
+		return response, tx.Commit()
+	}
+
+	for _, movie := range movies {
+		id, created, err := db.importOne(ctx, movie, user)
+		if err != nil {
+			log.Error(err)
+			response = append(response, domain.ResponseMovieImportRow{
+				Row:        movie.Row,
+				ExternalID: movie.ExternalID,
+				Status:     domain.ImportStatusFailed,
+				Errors:     []string{"movie could not be saved"},
+			})
+			continue
+		}
+		response = append(response, importRow(movie, id, created))
+	}
+	return response, nil
+}
+
+func (db *mysqlMovieRepository) importOne(ctx context.Context, movie domain.MovieImport, user *domain.AuthUser) (id int, created bool, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	id, created, err = upsertMovie(ctx, tx, movie, user)
+	if err != nil {
+		return 0, false, err
+	}
+	return id, created, tx.Commit()
+}
+
+func importRow(movie domain.MovieImport, id int, created bool) domain.ResponseMovieImportRow {
+	status := domain.ImportStatusUpdated
+	if created {
+		status = domain.ImportStatusCreated
+	}
+	return domain.ResponseMovieImportRow{Row: movie.Row, ExternalID: movie.ExternalID, Status: status, MovieID: &id}
+}
+
+// ImportDumpMovie upserts the movie of a dump with its tags and credits in one transaction. Tags are created
+// by slug and added to the movie, the credits replace the ones of the movie
+func (db *mysqlMovieRepository) ImportDumpMovie(ctx context.Context, movie domain.MovieDump, user *domain.AuthUser) (id int, created bool, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	id, created, err = upsertMovie(ctx, tx, movie.Movie, user)
+	if err != nil {
+		log.Error(err)
+		return 0, false, err
+	}
+
+	for _, tag := range movie.Tags {
+		res, err := tx.ExecContext(ctx, `INSERT INTO tag (name, slug, dtm_crt, dtm_upd) VALUES (?, ?, NOW(), NOW())
+              ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)`, tag.Name, tag.Slug)
+		if err != nil {
+			log.Error(err)
+			return 0, false, err
+		}
+		tagID, err := res.LastInsertId()
+		if err != nil {
+			return 0, false, err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT IGNORE INTO movie_tag (movie_id, tag_id) VALUES (?, ?)`, id, tagID)
+		if err != nil {
+			log.Error(err)
+			return 0, false, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM movie_credit WHERE movie_id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return 0, false, err
+	}
+	for _, credit := range movie.Credits {
+		res, err := tx.ExecContext(ctx, `INSERT INTO person (external_id, name, dtm_crt, dtm_upd) VALUES (?, ?, NOW(), NOW())
+              ON DUPLICATE KEY UPDATE name = VALUES(name), dtm_upd = NOW(), id = LAST_INSERT_ID(id)`, credit.ExternalID, credit.Name)
+		if err != nil {
+			log.Error(err)
+			return 0, false, err
+		}
+		personID, err := res.LastInsertId()
+		if err != nil {
+			return 0, false, err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO movie_credit (movie_id, person_id, kind, role, department, position) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, personID, credit.Kind, credit.Role, credit.Department, credit.Position)
+		if err != nil {
+			log.Error(err)
+			return 0, false, err
+		}
+	}
+
+	return id, created, tx.Commit()
+}
+
+// GetMovieCredit lists the cast then the crew of the movie, each by position
+func (db *mysqlMovieRepository) GetMovieCredit(ctx context.Context, movieID int) (response []domain.ResponseMovieCredit, err error) {
+	query := `SELECT person.id, person.name, movie_credit.kind, movie_credit.role, movie_credit.department, movie_credit.position
+              FROM movie_credit
+              JOIN person ON person.id = movie_credit.person_id
+              WHERE movie_credit.movie_id = ?
+              ORDER BY movie_credit.kind, movie_credit.position, movie_credit.id`
+
+	rows, err := db.Conn.QueryContext(ctx, query, movieID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var credit domain.ResponseMovieCredit
+		err = rows.Scan(&credit.PersonID, &credit.Name, &credit.Kind, &credit.Role, &credit.Department, &credit.Position)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, credit)
+	}
+
+	return response, rows.Err()
+}
 
+// batchOperation writes an operation of a batch, an update or delete of a movie not found returns Not found
+func batchOperation(ctx context.Context, tx *sql.Tx, operation domain.MovieBatchOperation, user *domain.AuthUser) (response domain.ResponseMovieBatchResult, err error) {
+	response = domain.ResponseMovieBatchResult{Index: operation.Index, Op: operation.Op}
+	id := operation.ID
+
+	switch operation.Op {
+	case domain.BatchOpCreate:
+		id, _, err = upsertMovie(ctx, tx, domain.MovieImport{Request: operation.Request}, user)
+		response.Status = domain.ImportStatusCreated
+
+	case domain.BatchOpUpdate:
+		var count int
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(id) FROM movie WHERE id = ? FOR UPDATE`, id).Scan(&count)
+		if err == nil && count == 0 {
+			err = errors.New("Not found")
+		}
+		if err == nil {
+			request := operation.Request
+			request.User = user
+			err = updateMovie(ctx, tx, id, request)
+		}
+		response.Status = domain.ImportStatusUpdated
+
+	case domain.BatchOpDelete:
+		err = deleteMovie(ctx, tx, id, user)
+		response.Status = domain.BatchStatusDeleted
+	}
+	if err != nil {
+		return domain.ResponseMovieBatchResult{}, err
+	}
+
+	response.MovieID = &id
 	return response, nil
 }
+
+// BatchMovie writes the operations in order in one transaction when atomic, the first failure rolls every
+// operation back and is returned with the results of the operations before it. Otherwise each operation
+// has its own transaction and a failure only fails its result
+func (db *mysqlMovieRepository) BatchMovie(ctx context.Context, operations []domain.MovieBatchOperation, atomic bool, user *domain.AuthUser) (response []domain.ResponseMovieBatchResult, err error) {
+	if atomic {
+		tx, err := db.Conn.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		for _, operation := range operations {
+			result, err := batchOperation(ctx, tx, operation, user)
+			if err != nil {
+				log.Error(err)
+				return response, err
+			}
+			response = append(response, result)
+		}
+
+		return response, tx.Commit()
+	}
+
+	for _, operation := range operations {
+		result, err := db.batchOne(ctx, operation, user)
+		if err != nil {
+			message := "movie could not be saved"
+			if err.Error() == "Not found" {
+				message = "movie is not exists"
+			} else if err.Error() == "Booked" {
+				message = "movie has showtimes with bookings"
+			} else {
+				log.Error(err)
+			}
+			result = domain.ResponseMovieBatchResult{Index: operation.Index, Op: operation.Op, Status: domain.ImportStatusFailed, Errors: []string{message}}
+		}
+		response = append(response, result)
+	}
+	return response, nil
+}
+
+func (db *mysqlMovieRepository) batchOne(ctx context.Context, operation domain.MovieBatchOperation, user *domain.AuthUser) (response domain.ResponseMovieBatchResult, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return response, err
+	}
+	defer tx.Rollback()
+
+	response, err = batchOperation(ctx, tx, operation, user)
+	if err != nil {
+		return response, err
+	}
+	return response, tx.Commit()
+}
@@ -0,0 +1,226 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/labstack/gommon/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	keyViewSeen     = "movie:view:seen:"
+	keyViewHour     = "movie:view:hour:"
+	keyViewPending  = "movie:view:pending"
+	keyViewFlushing = "movie:view:flushing"
+	keyViewLock     = "movie:view:lock"
+	keyTrending     = "movie:trending:"
+
+	// fieldFlushID is the field of the flushing batch holding its flush ID, other fields are movie IDs
+	fieldFlushID = "flush_id"
+
+	// hourBucketTTL keeps the hourly buckets a bit longer than the week window
+	hourBucketTTL = 8 * 24 * time.Hour
+)
+
+// unlockScript deletes the lock only while the owner still holds it, KEYS is the lock and ARGV the owner
+var unlockScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+    return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type redisMovieRepository struct {
+	Client *goredis.Client
+}
+
+func NewRedisMovieRepository(Client *goredis.Client) domain.MovieRedisRepo {
+	return &redisMovieRepository{Client}
+}
+
+func hourKey(t time.Time) string {
+	return keyViewHour + t.UTC().Format("2006010215")
+}
+
+// RecordView counts a view once per viewer per dedupe window, into the hourly bucket and the pending counter
+func (db *redisMovieRepository) RecordView(ctx context.Context, movieID int, viewer string) (err error) {
+	hash := sha1.Sum([]byte(viewer))
+	seenKey := keyViewSeen + strconv.Itoa(movieID) + ":" + hex.EncodeToString(hash[:])
+
+	window := time.Duration(viper.GetInt("popularity.dedupe_window")) * time.Minute
+	first, err := db.Client.SetNX(ctx, seenKey, 1, window).Result()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	member := strconv.Itoa(movieID)
+	bucket := hourKey(time.Now())
+	_, err = db.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, bucket, 1, member)
+		pipe.Expire(ctx, bucket, hourBucketTTL)
+		pipe.HIncrBy(ctx, keyViewPending, member, 1)
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTrendingScore sums the hourly buckets of the window, each bucket halves its weight every half life,
+// the result is cached for a short time so the union is not rebuilt on every request
+func (db *redisMovieRepository) GetTrendingScore(ctx context.Context, window string, limit int) (response []domain.MovieScore, err error) {
+	hours, halfLife := 24, viper.GetFloat64("popularity.half_life_day")
+	if window == domain.TrendingWindowWeek {
+		hours, halfLife = 7*24, viper.GetFloat64("popularity.half_life_week")
+	}
+
+	dest := keyTrending + window
+	exists, err := db.Client.Exists(ctx, dest).Result()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if exists == 0 {
+		now := time.Now()
+		store := &goredis.ZStore{Aggregate: "SUM"}
+		for age := 0; age < hours; age++ {
+			store.Keys = append(store.Keys, hourKey(now.Add(-time.Duration(age)*time.Hour)))
+			weight := 1.0
+			if halfLife > 0 {
+				weight = math.Pow(0.5, float64(age)/halfLife)
+			}
+			store.Weights = append(store.Weights, weight)
+		}
+
+		_, err = db.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.ZUnionStore(ctx, dest, store)
+			pipe.Expire(ctx, dest, time.Duration(viper.GetInt("popularity.trending_cache"))*time.Second)
+			return nil
+		})
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	stop := int64(-1)
+	if limit > 0 {
+		stop = int64(limit - 1)
+	}
+	rows, err := db.Client.ZRevRangeWithScores(ctx, dest, 0, stop).Result()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	for _, row := range rows {
+		movieID, err := strconv.Atoi(row.Member.(string))
+		if err != nil {
+			continue
+		}
+		response = append(response, domain.MovieScore{MovieID: movieID, Score: math.Round(row.Score*10000) / 10000})
+	}
+
+	return response, nil
+}
+
+// PopPendingView takes the view counts recorded since the last flush into the flushing batch, a batch left by
+// a failed flush is taken again first with the same flush ID. The batch is kept until DeleteFlushedView
+// confirms it is stored
+func (db *redisMovieRepository) PopPendingView(ctx context.Context) (flushID string, response map[int]int64, err error) {
+	response = map[int]int64{}
+
+	exists, err := db.Client.Exists(ctx, keyViewFlushing).Result()
+	if err != nil {
+		log.Error(err)
+		return "", nil, err
+	}
+
+	if exists == 0 {
+		err = db.Client.Rename(ctx, keyViewPending, keyViewFlushing).Err()
+		if err != nil {
+			if err.Error() == "ERR no such key" {
+				return "", response, nil
+			}
+			log.Error(err)
+			return "", nil, err
+		}
+	}
+
+	// The flush ID is set once per batch, a batch taken again keeps the ID it was first flushed with
+	err = db.Client.HSetNX(ctx, keyViewFlushing, fieldFlushID, helper.RandomHex(16)).Err()
+	if err != nil {
+		log.Error(err)
+		return "", nil, err
+	}
+
+	values, err := db.Client.HGetAll(ctx, keyViewFlushing).Result()
+	if err != nil {
+		log.Error(err)
+		return "", nil, err
+	}
+	flushID = values[fieldFlushID]
+
+	for member, value := range values {
+		movieID, err := strconv.Atoi(member)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		response[movieID] = count
+	}
+
+	return flushID, response, nil
+}
+
+// DeleteFlushedView drops the flushing batch once its counts are committed to MySQL
+func (db *redisMovieRepository) DeleteFlushedView(ctx context.Context) (err error) {
+	err = db.Client.Del(ctx, keyViewFlushing).Err()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// LockViewFlush takes the flush lock for owner until ttl expires, ok is false while another flush holds it
+func (db *redisMovieRepository) LockViewFlush(ctx context.Context, owner string, ttl time.Duration) (ok bool, err error) {
+	ok, err = db.Client.SetNX(ctx, keyViewLock, owner, ttl).Result()
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// UnlockViewFlush releases the flush lock, a lock expired and taken by another flush is left alone
+func (db *redisMovieRepository) UnlockViewFlush(ctx context.Context, owner string) (err error) {
+	err = unlockScript.Run(ctx, db.Client, []string{keyViewLock}, owner).Err()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+// movieFields lists the fields of a movie like the rows of an import
+func movieFields(movie domain.ResponseMovie) map[string]string {
+	fields := map[string]string{
+		"title":       movie.Title,
+		"description": movie.Description,
+		"rating":      strconv.FormatFloat(movie.Rating, 'f', -1, 64),
+		"runtime":     strconv.Itoa(movie.Runtime),
+	}
+	if movie.ReleaseDate != nil {
+		fields["release_date"] = *movie.ReleaseDate
+	}
+	return fields
+}
+
+// batchFields copies the fields changed by the ones sent with the operation
+func batchFields(current map[string]string, operation domain.RequestMovieBatchOperation) map[string]string {
+	fields := make(map[string]string, len(current))
+	for key, value := range current {
+		fields[key] = value
+	}
+	if operation.Title != nil {
+		fields["title"] = *operation.Title
+	}
+	if operation.Description != nil {
+		fields["description"] = *operation.Description
+	}
+	if operation.Rating != nil {
+		fields["rating"] = strconv.FormatFloat(*operation.Rating, 'f', -1, 64)
+	}
+	if operation.Runtime != nil {
+		fields["runtime"] = strconv.Itoa(*operation.Runtime)
+	}
+	if operation.ReleaseDate != nil {
+		fields["release_date"] = *operation.ReleaseDate
+	}
+	return fields
+}
+
+// batchCount sums the results by status
+func batchCount(response *domain.ResponseMovieBatch) {
+	for _, result := range response.Results {
+		switch result.Status {
+		case domain.ImportStatusCreated:
+			response.Created++
+		case domain.ImportStatusUpdated:
+			response.Updated++
+		case domain.BatchStatusDeleted:
+			response.Deleted++
+		case domain.ImportStatusFailed:
+			response.Failed++
+		}
+	}
+}
+
+// skipBatch reports the operations without error as skipped and the first failed one, when the transaction
+// writes none
+func skipBatch(response *domain.ResponseMovieBatch) {
+	for idx := range response.Results {
+		if len(response.Results[idx].Errors) == 0 {
+			response.Results[idx] = domain.ResponseMovieBatchResult{Index: idx, Op: response.Results[idx].Op, Status: domain.ImportStatusSkipped}
+			continue
+		}
+		if response.FailedIndex == nil {
+			failed := idx
+			response.FailedIndex = &failed
+		}
+	}
+}
+
+// removeImages deletes the images saved for operations or import rows that were not written, every one
+// without keep
+func removeImages(images map[int]string, keep func(key int) bool) {
+	for key, image := range images {
+		if keep != nil && keep(key) {
+			continue
+		}
+		err := os.Remove(filepath.Join(viper.GetString("server.url_assets"), image))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warn(err)
+		}
+	}
+}
+
+// BatchMovie checks every operation then writes the valid ones in order. An update is checked against the
+// movie as left by the operations before it, an operation on a movie deleted by the batch fails. In
+// transaction mode a single failed operation leaves every movie untouched
+func (mvu *movieUseCase) BatchMovie(ctx context.Context, request domain.RequestMovieBatch) (response domain.ResponseMovieBatch, err error) {
+	if request.Mode == "" {
+		request.Mode = domain.ImportModeTransaction
+	}
+	if request.Mode != domain.ImportModeTransaction && request.Mode != domain.ImportModeBestEffort {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("mode must be transaction or best_effort")}
+	}
+	if len(request.Operations) == 0 {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("operations is required")}
+	}
+	if maxOperations := viper.GetInt("movie.batch_max_operations"); maxOperations > 0 && len(request.Operations) > maxOperations {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("batch has more than %d operations", maxOperations)}
+	}
+
+	response = domain.ResponseMovieBatch{Mode: request.Mode, Total: len(request.Operations), Results: make([]domain.ResponseMovieBatchResult, len(request.Operations))}
+	var operations []domain.MovieBatchOperation
+	images := map[int]string{}
+	movies := map[int]map[string]string{}
+	deleted := map[int]int{}
+	for idx, operation := range request.Operations {
+		result := &response.Results[idx]
+		*result = domain.ResponseMovieBatchResult{Index: idx, Op: operation.Op, Status: domain.ImportStatusFailed}
+
+		var fields map[string]string
+		switch operation.Op {
+		case domain.BatchOpCreate:
+			fields = batchFields(nil, operation)
+
+		case domain.BatchOpUpdate, domain.BatchOpDelete:
+			if operation.ID < 1 {
+				result.Errors = []string{"id is required"}
+				continue
+			}
+			if index, ok := deleted[operation.ID]; ok {
+				result.Errors = []string{fmt.Sprintf("movie is deleted by operation %d", index)}
+				continue
+			}
+
+			current, ok := movies[operation.ID]
+			if !ok {
+				movie, err := mvu.movieMySQLRepo.GetDetailMovie(ctx, operation.ID)
+				if err != nil {
+					if err.Error() == "Not found" {
+						result.Errors = []string{"movie is not exists"}
+						continue
+					}
+					return domain.ResponseMovieBatch{}, err
+				}
+				current = movieFields(movie)
+			}
+
+			if operation.Op == domain.BatchOpDelete {
+				deleted[operation.ID] = idx
+				operations = append(operations, domain.MovieBatchOperation{Index: idx, Op: operation.Op, ID: operation.ID})
+				continue
+			}
+			fields = batchFields(current, operation)
+
+		default:
+			result.Errors = []string{"op must be create, update or delete"}
+			continue
+		}
+
+		movie, errs := importMovie(domain.Record{Row: idx, Fields: fields}, nil)
+		if operation.Image != nil && *operation.Image != "" {
+			if helper.IsImageURL(*operation.Image) {
+				images[idx] = *operation.Image
+			} else {
+				errs = append(errs, "image must be a http or https url")
+			}
+		}
+		if len(errs) > 0 {
+			result.Errors = errs
+			continue
+		}
+
+		if operation.Op == domain.BatchOpUpdate {
+			movies[operation.ID] = fields
+		}
+		operations = append(operations, domain.MovieBatchOperation{Index: idx, Op: operation.Op, ID: operation.ID, Request: movie.Request})
+	}
+
+	// Images are downloaded once every operation is checked, so a rejected transaction downloads none. The
+	// images saved for operations that end up not written are removed
+	atomic := request.Mode == domain.ImportModeTransaction
+	saved := map[int]string{}
+	if !atomic || len(operations) == len(request.Operations) {
+		valid := operations[:0]
+		for _, operation := range operations {
+			if image, ok := images[operation.Index]; ok {
+				timeout := time.Duration(viper.GetInt("import.image_timeout")) * time.Second
+				// A random part keeps every download apart, removing one never touches the image of a written movie
+				prefix := helper.Slugify(operation.Request.Title) + "-" + helper.RandomHex(4) + "-"
+				operation.Request.ImagePath, err = helper.DownloadImage(image, prefix, viper.GetString("server.url_assets"), "images/banner", viper.GetInt64("import.max_image_size"), timeout)
+				if err != nil {
+					log.Warn(err)
+					response.Results[operation.Index].Errors = []string{"image could not be saved: " + err.Error()}
+					continue
+				}
+				saved[operation.Index] = operation.Request.ImagePath
+			}
+			valid = append(valid, operation)
+		}
+		operations = valid
+	}
+
+	if atomic && len(operations) != len(request.Operations) {
+		removeImages(saved, nil)
+		skipBatch(&response)
+		batchCount(&response)
+		return response, nil
+	}
+
+	if len(operations) > 0 {
+		results, err := mvu.movieMySQLRepo.BatchMovie(ctx, operations, atomic, request.User)
+		if err != nil {
+			removeImages(saved, nil)
+			// An error before the commit fails its operation and the transaction is rolled back, a movie deleted since
+			// it was checked or a deleted movie with booked showtimes is answered as such
+			if !atomic || len(results) >= len(operations) {
+				log.Error(err)
+				return domain.ResponseMovieBatch{}, err
+			}
+			messages := map[string]string{"Not found": "movie is not exists", "Booked": "movie has showtimes with bookings"}
+			message, ok := messages[err.Error()]
+			if !ok {
+				log.Error(err)
+				message = "movie could not be saved"
+			}
+			failed := operations[len(results)].Index
+			response.Results[failed].Errors = []string{message}
+			skipBatch(&response)
+			batchCount(&response)
+			return response, nil
+		}
+		for _, result := range results {
+			response.Results[result.Index] = result
+		}
+		removeImages(saved, func(index int) bool { return response.Results[index].Status != domain.ImportStatusFailed })
+		response.Committed = true
+	}
+
+	batchCount(&response)
+	return response, nil
+}
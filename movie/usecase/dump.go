@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+// omdbReleasedLayout is the layout of the OMDb release date, e.g. 14 Oct 1994
+const omdbReleasedLayout = "02 Jan 2006"
+
+// tmdbReleaseDate is the first theatrical release in the region, otherwise its first release, otherwise
+// the primary release date of the movie
+func tmdbReleaseDate(movie domain.TMDBMovie, region string) string {
+	for _, country := range movie.ReleaseDates.Results {
+		if !strings.EqualFold(country.Country, region) {
+			continue
+		}
+
+		var first, theatrical string
+		for _, release := range country.ReleaseDates {
+			if len(release.ReleaseDate) < len(domain.ReleaseDateLayout) {
+				continue
+			}
+			date := release.ReleaseDate[:len(domain.ReleaseDateLayout)]
+			if first == "" || date < first {
+				first = date
+			}
+			if release.Type == domain.TMDBReleaseTheatrical && (theatrical == "" || date < theatrical) {
+				theatrical = date
+			}
+		}
+		if theatrical != "" {
+			return theatrical
+		}
+		if first != "" {
+			return first
+		}
+	}
+	return movie.ReleaseDate
+}
+
+// tmdbDump maps a TMDB movie, the cast is cut to dump.max_cast and the crew kept to the dump.crew_jobs
+func tmdbDump(movie domain.TMDBMovie) (fields map[string]string, genres []string, credits []domain.RequestMovieCredit, poster string) {
+	fields = map[string]string{
+		"title":        strings.TrimSpace(movie.Title),
+		"description":  strings.TrimSpace(movie.Overview),
+		"release_date": tmdbReleaseDate(movie, viper.GetString("dump.region")),
+	}
+	if movie.ID > 0 {
+		fields["external_id"] = "tmdb:" + strconv.Itoa(movie.ID)
+	}
+	if movie.VoteAverage > 0 {
+		fields["rating"] = strconv.FormatFloat(movie.VoteAverage, 'f', -1, 64)
+	}
+	if movie.Runtime > 0 {
+		fields["runtime"] = strconv.Itoa(movie.Runtime)
+	}
+
+	for _, genre := range movie.Genres {
+		genres = append(genres, genre.Name)
+	}
+
+	cast := movie.Credits.Cast
+	sort.SliceStable(cast, func(i, j int) bool {
+		return cast[i].Order < cast[j].Order
+	})
+	if maxCast := viper.GetInt("dump.max_cast"); maxCast > 0 && len(cast) > maxCast {
+		cast = cast[:maxCast]
+	}
+	for _, person := range cast {
+		if person.ID == 0 || person.Name == "" {
+			continue
+		}
+		credits = append(credits, domain.RequestMovieCredit{
+			ExternalID: "tmdb:" + strconv.Itoa(person.ID),
+			Name:       person.Name,
+			Kind:       domain.CreditKindCast,
+			Role:       person.Character,
+			Position:   len(credits),
+		})
+	}
+
+	jobs := map[string]bool{}
+	for _, job := range viper.GetStringSlice("dump.crew_jobs") {
+		jobs[strings.ToLower(job)] = true
+	}
+	position := 0
+	for _, person := range movie.Credits.Crew {
+		if person.ID == 0 || person.Name == "" || (len(jobs) > 0 && !jobs[strings.ToLower(person.Job)]) {
+			continue
+		}
+		credits = append(credits, domain.RequestMovieCredit{
+			ExternalID: "tmdb:" + strconv.Itoa(person.ID),
+			Name:       person.Name,
+			Kind:       domain.CreditKindCrew,
+			Role:       person.Job,
+			Department: person.Department,
+			Position:   position,
+		})
+		position++
+	}
+
+	poster = movie.PosterPath
+	if poster == "" {
+		var best float64 = -1
+		for _, image := range movie.Images.Posters {
+			if image.FilePath != "" && image.VoteAverage > best {
+				poster, best = image.FilePath, image.VoteAverage
+			}
+		}
+	}
+	return fields, genres, credits, poster
+}
+
+// omdbList splits an OMDb list like "Tim Robbins, Morgan Freeman"
+func omdbList(value string) (items []string) {
+	if value == "N/A" {
+		return nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// omdbDump maps an OMDb movie, people have no ID in OMDb so they are told apart by name
+func omdbDump(movie domain.OMDbMovie) (fields map[string]string, genres []string, credits []domain.RequestMovieCredit, poster string) {
+	value := func(value string) string {
+		value = strings.TrimSpace(value)
+		if value == "N/A" {
+			return ""
+		}
+		return value
+	}
+
+	fields = map[string]string{
+		"title":       value(movie.Title),
+		"description": value(movie.Plot),
+		"rating":      value(movie.IMDbRating),
+		"runtime":     strings.TrimSuffix(value(movie.Runtime), " min"),
+	}
+	if id := value(movie.IMDbID); id != "" {
+		fields["external_id"] = "imdb:" + id
+	}
+	if released := value(movie.Released); released != "" {
+		fields["release_date"] = released
+		if date, err := time.Parse(omdbReleasedLayout, released); err == nil {
+			fields["release_date"] = date.Format(domain.ReleaseDateLayout)
+		}
+	}
+
+	genres = omdbList(movie.Genre)
+
+	person := func(name string, kind string, role string, department string, position int) domain.RequestMovieCredit {
+		return domain.RequestMovieCredit{
+			ExternalID: "omdb:" + helper.Slugify(name),
+			Name:       name,
+			Kind:       kind,
+			Role:       role,
+			Department: department,
+			Position:   position,
+		}
+	}
+	for idx, name := range omdbList(movie.Actors) {
+		credits = append(credits, person(name, domain.CreditKindCast, "", "", idx))
+	}
+	position := 0
+	for _, name := range omdbList(movie.Director) {
+		credits = append(credits, person(name, domain.CreditKindCrew, "Director", "Directing", position))
+		position++
+	}
+	for _, name := range omdbList(movie.Writer) {
+		// A writer may be followed by the source written, e.g. Stephen King (short story)
+		role := "Writer"
+		if open := strings.Index(name, " ("); open > 0 && strings.HasSuffix(name, ")") {
+			role = "Writer, " + name[open+2:len(name)-1]
+			name = name[:open]
+		}
+		credits = append(credits, person(name, domain.CreditKindCrew, role, "Writing", position))
+		position++
+	}
+
+	return fields, genres, credits, value(movie.Poster)
+}
+
+// dumpMovie maps an entry in the format told by its fields, the poster is returned to be saved once the
+// movie is valid
+func dumpMovie(entry []byte) (dump domain.MovieDump, poster string, errs []string) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(entry, &keys); err != nil {
+		return dump, "", []string{"entry is not a JSON object"}
+	}
+
+	var fields map[string]string
+	var genres []string
+	if _, ok := keys["imdbID"]; ok {
+		var movie domain.OMDbMovie
+		if err := json.Unmarshal(entry, &movie); err != nil {
+			return dump, "", []string{"entry is not an OMDb movie: " + err.Error()}
+		}
+		fields, genres, dump.Credits, poster = omdbDump(movie)
+	} else if _, ok := keys["id"]; ok {
+		var movie domain.TMDBMovie
+		if err := json.Unmarshal(entry, &movie); err != nil {
+			return dump, "", []string{"entry is not a TMDB movie: " + err.Error()}
+		}
+		fields, genres, dump.Credits, poster = tmdbDump(movie)
+	} else {
+		return dump, "", []string{"entry is neither a TMDB nor an OMDb movie"}
+	}
+
+	dump.Movie, errs = importMovie(domain.Record{Fields: fields}, nil)
+	if dump.Movie.ExternalID == "" {
+		errs = append(errs, "id is required")
+	}
+
+	seen := map[string]bool{}
+	for _, genre := range genres {
+		slug := helper.Slugify(genre)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		dump.Tags = append(dump.Tags, domain.RequestTag{Name: strings.TrimSpace(genre), Slug: slug})
+	}
+	return dump, poster, errs
+}
+
+// saveDumpImage copies the poster from the images directory by file name, otherwise downloads it when
+// dump.download_images is set. A TMDB poster path is resolved against dump.image_base_url
+func (mvu *movieUseCase) saveDumpImage(poster string, images string, externalID string) (imagePath string, err error) {
+	name := path.Base(poster)
+	if helper.IsImageURL(poster) {
+		parsed, err := url.Parse(poster)
+		if err != nil {
+			return "", err
+		}
+		name = path.Base(parsed.Path)
+	}
+
+	parentPath := viper.GetString("server.url_assets")
+	subPath := "images/banner"
+	maxSize := viper.GetInt64("import.max_image_size")
+	prefix := helper.Slugify(externalID) + "-"
+
+	if images != "" {
+		src, err := os.Open(filepath.Join(images, name))
+		if err == nil {
+			defer src.Close()
+			return helper.SaveImage(src, prefix+name, parentPath, subPath, maxSize)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	if !viper.GetBool("dump.download_images") {
+		return "", nil
+	}
+	if !helper.IsImageURL(poster) {
+		poster = strings.TrimRight(viper.GetString("dump.image_base_url"), "/") + "/" + strings.TrimPrefix(poster, "/")
+	}
+	timeout := time.Duration(viper.GetInt("import.image_timeout")) * time.Second
+	return helper.DownloadImage(poster, prefix, parentPath, subPath, maxSize, timeout)
+}
+
+// ImportDumpMovie upserts the movie of a TMDB or OMDb entry by its external ID with its genres as tags and
+// its credits. A poster that cannot be saved is left out, an updated movie then keeps its image
+func (mvu *movieUseCase) ImportDumpMovie(ctx context.Context, request domain.RequestMovieDump) (response domain.ResponseMovieImportRow, err error) {
+	dump, poster, errs := dumpMovie(request.Entry)
+	response = domain.ResponseMovieImportRow{ExternalID: dump.Movie.ExternalID, Status: domain.ImportStatusFailed, Errors: errs}
+	if len(errs) > 0 {
+		return response, nil
+	}
+
+	if poster != "" {
+		dump.Movie.Request.ImagePath, err = mvu.saveDumpImage(poster, request.Images, dump.Movie.ExternalID)
+		if err != nil {
+			log.Warnf("%s: poster %s could not be saved: %v", dump.Movie.ExternalID, poster, err)
+			dump.Movie.Request.ImagePath = ""
+		}
+	}
+
+	id, created, err := mvu.movieMySQLRepo.ImportDumpMovie(ctx, dump, request.User)
+	if err != nil {
+		log.Error(err)
+		return response, err
+	}
+
+	response.Status = domain.ImportStatusUpdated
+	if created {
+		response.Status = domain.ImportStatusCreated
+	}
+	response.MovieID = &id
+	return response, nil
+}
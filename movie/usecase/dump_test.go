@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/spf13/viper"
+)
+
+const dumpFixtures = "../../fixtures/dump"
+
+// dumpEntry reads the entry of a fixture, numbered from 0 in an array
+func dumpEntry(t *testing.T, name string, entry int) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dumpFixtures, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != '[' {
+		return data
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	return entries[entry]
+}
+
+func setDumpConfig(t *testing.T) {
+	t.Helper()
+	viper.Set("dump.region", "ID")
+	viper.Set("dump.max_cast", 2)
+	viper.Set("dump.crew_jobs", []string{"Director", "Screenplay"})
+	viper.Set("dump.download_images", false)
+	t.Cleanup(viper.Reset)
+}
+
+func creditNames(credits []domain.RequestMovieCredit) (names []string) {
+	for _, credit := range credits {
+		names = append(names, credit.Kind+" "+credit.Name+" "+credit.Role)
+	}
+	return names
+}
+
+func tagNames(tags []domain.RequestTag) (names []string) {
+	for _, tag := range tags {
+		names = append(names, tag.Slug)
+	}
+	return names
+}
+
+func TestDumpMovieTMDB(t *testing.T) {
+	setDumpConfig(t)
+
+	dump, poster, errs := dumpMovie(dumpEntry(t, "tmdb/550.json", 0))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	request := dump.Movie.Request
+	if dump.Movie.ExternalID != "tmdb:550" || request.Title != "Fight Club" || request.FloatRating != 8.4 || request.Runtime != 139 {
+		t.Fatalf("got %+v, want Fight Club tmdb:550 rated 8.4 running 139 minutes", dump.Movie)
+	}
+	// The theatrical release in ID wins over the US and the digital release
+	if request.ReleaseDate != "1999-11-10" {
+		t.Fatalf("got release date %s, want 1999-11-10", request.ReleaseDate)
+	}
+	if poster != "/pB8BM7pdSp6B6Ih7QZ4DrQ3PmJK.jpg" {
+		t.Fatalf("got poster %s", poster)
+	}
+	if got, want := tagNames(dump.Tags), []string{"drama", "thriller"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got tags %v, want %v", got, want)
+	}
+
+	// The cast is cut to dump.max_cast and the crew kept to dump.crew_jobs
+	want := []string{
+		"cast Edward Norton The Narrator",
+		"cast Brad Pitt Tyler Durden",
+		"crew David Fincher Director",
+		"crew Jim Uhls Screenplay",
+	}
+	if got := creditNames(dump.Credits); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got credits %v, want %v", got, want)
+	}
+	if dump.Credits[0].ExternalID != "tmdb:819" || dump.Credits[1].Position != 1 || dump.Credits[3].Position != 1 {
+		t.Fatalf("got credits %+v", dump.Credits)
+	}
+}
+
+func TestDumpMovieTMDBBestPoster(t *testing.T) {
+	setDumpConfig(t)
+
+	// Inception has no poster path, the poster with the best vote is taken
+	dump, poster, errs := dumpMovie(dumpEntry(t, "tmdb/popular.json", 1))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if dump.Movie.ExternalID != "tmdb:27205" || poster != "/oYuLEt3zVCKq57qu2F8dT7NIa6f.jpg" {
+		t.Fatalf("got %s with poster %s", dump.Movie.ExternalID, poster)
+	}
+	// Without a release in ID the primary release date is kept
+	if dump.Movie.Request.ReleaseDate != "2010-07-15" {
+		t.Fatalf("got release date %s, want 2010-07-15", dump.Movie.Request.ReleaseDate)
+	}
+}
+
+func TestDumpMovieOMDb(t *testing.T) {
+	setDumpConfig(t)
+
+	dump, poster, errs := dumpMovie(dumpEntry(t, "omdb/tt0111161.json", 0))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	request := dump.Movie.Request
+	if dump.Movie.ExternalID != "imdb:tt0111161" || request.Title != "The Shawshank Redemption" || request.FloatRating != 9.3 || request.Runtime != 142 || request.ReleaseDate != "1994-10-14" {
+		t.Fatalf("got %+v", dump.Movie)
+	}
+	if !strings.HasPrefix(poster, "https://m.media-amazon.com/") {
+		t.Fatalf("got poster %s, want the OMDb poster url", poster)
+	}
+	if got, want := tagNames(dump.Tags), []string{"drama"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got tags %v, want %v", got, want)
+	}
+
+	want := []string{
+		"cast Tim Robbins ",
+		"cast Morgan Freeman ",
+		"cast Bob Gunton ",
+		"crew Frank Darabont Director",
+		"crew Stephen King Writer, short story",
+		"crew Frank Darabont Writer, screenplay",
+	}
+	if got := creditNames(dump.Credits); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got credits %v, want %v", got, want)
+	}
+	if dump.Credits[3].ExternalID != "omdb:frank-darabont" || dump.Credits[3].ExternalID != dump.Credits[5].ExternalID {
+		t.Fatalf("got credits %+v, want one person per name", dump.Credits)
+	}
+}
+
+func TestDumpMovieInvalid(t *testing.T) {
+	setDumpConfig(t)
+
+	_, _, errs := dumpMovie(dumpEntry(t, "tmdb/popular.json", 2))
+	if !reflect.DeepEqual(errs, []string{"title is required"}) {
+		t.Fatalf("got %v, want title is required", errs)
+	}
+
+	_, _, errs = dumpMovie([]byte(`{"name": "not a movie"}`))
+	if !reflect.DeepEqual(errs, []string{"entry is neither a TMDB nor an OMDb movie"}) {
+		t.Fatalf("got %v", errs)
+	}
+}
+
+// dumpRepo stores the movies by external ID like the upsert of the movie repository
+type dumpRepo struct {
+	domain.MovieMySQLRepo
+	movies map[string]domain.MovieDump
+	ids    map[string]int
+}
+
+func (repo *dumpRepo) ImportDumpMovie(ctx context.Context, movie domain.MovieDump, user *domain.AuthUser) (id int, created bool, err error) {
+	id, ok := repo.ids[movie.Movie.ExternalID]
+	if !ok {
+		id = len(repo.ids) + 1
+		repo.ids[movie.Movie.ExternalID] = id
+	}
+	if movie.Movie.Request.ImagePath == "" {
+		movie.Movie.Request.ImagePath = repo.movies[movie.Movie.ExternalID].Movie.Request.ImagePath
+	}
+	repo.movies[movie.Movie.ExternalID] = movie
+	return id, !ok, nil
+}
+
+func TestImportDumpMovieTwice(t *testing.T) {
+	setDumpConfig(t)
+	assets := t.TempDir()
+	viper.Set("server.url_assets", assets)
+	if err := os.MkdirAll(filepath.Join(assets, "images/banner"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &dumpRepo{movies: map[string]domain.MovieDump{}, ids: map[string]int{}}
+	movieUseCase := &movieUseCase{movieMySQLRepo: repo}
+	request := domain.RequestMovieDump{Entry: dumpEntry(t, "tmdb/550.json", 0), Images: filepath.Join(dumpFixtures, "posters")}
+
+	first, err := movieUseCase.ImportDumpMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := movieUseCase.ImportDumpMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Status != domain.ImportStatusCreated || second.Status != domain.ImportStatusUpdated || *first.MovieID != *second.MovieID {
+		t.Fatalf("got %+v then %+v, want the movie created then updated", first, second)
+	}
+	if len(repo.movies) != 1 {
+		t.Fatalf("got %d movies, want 1", len(repo.movies))
+	}
+
+	// The poster is copied from the images directory under the external ID
+	image := repo.movies["tmdb:550"].Movie.Request.ImagePath
+	if image != filepath.Join("images/banner", "tmdb-550-pb8bm7pdsp6b6ih7qz4drq3pmjk.jpg") {
+		t.Fatalf("got image %s", image)
+	}
+	if _, err := os.Stat(filepath.Join(assets, image)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var exportColumns = []string{"id", "external_id", "title", "description", "rating", "runtime", "release_date",
+	"status", "publish_at", "image", "view_count", "popularity", "dtm_crt", "dtm_upd"}
+
+// exportRow lists the values of the export columns, nil for an empty value
+func exportRow(movie domain.ResponseMovie) []interface{} {
+	optional := func(value *string) interface{} {
+		if value == nil {
+			return nil
+		}
+		return *value
+	}
+
+	return []interface{}{movie.ID, optional(movie.ExternalID), movie.Title, movie.Description, movie.Rating, movie.Runtime,
+		optional(movie.ReleaseDate), movie.Status, optional(movie.PublishAt), movie.Image, movie.ViewCount, movie.Popularity,
+		movie.DtmCrt, movie.DtmUpd}
+}
+
+// ExportMovie writes the movies matching the list filters in format, one row at a time. Like the list, only
+// editors export movies that are not published
+func (mvu *movieUseCase) ExportMovie(ctx context.Context, request domain.RequestParamMovie, format string, w io.Writer) (err error) {
+	if !domain.CanManageMovie(request.User) {
+		published := domain.MovieStatusPublished
+		request.Status = &published
+	}
+
+	switch format {
+	case domain.ImportFormatCSV:
+		writer := csv.NewWriter(w)
+		err = writer.Write(exportColumns)
+		if err != nil {
+			return err
+		}
+
+		err = mvu.movieMySQLRepo.EachMovie(ctx, request, func(movie domain.ResponseMovie) error {
+			values := exportRow(movie)
+			record := make([]string, len(values))
+			for idx, value := range values {
+				record[idx] = exportText(value)
+			}
+			return writer.Write(record)
+		})
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case domain.ImportFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		err = mvu.movieMySQLRepo.EachMovie(ctx, request, func(movie domain.ResponseMovie) error {
+			return encoder.Encode(movie)
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return err
+
+	case domain.ExportFormatXLSX:
+		writer, err := helper.NewXLSXWriter(w, "Movies")
+		if err != nil {
+			return err
+		}
+
+		header := make([]interface{}, len(exportColumns))
+		for idx, column := range exportColumns {
+			header[idx] = column
+		}
+		err = writer.WriteRow(header)
+		if err != nil {
+			return err
+		}
+
+		err = mvu.movieMySQLRepo.EachMovie(ctx, request, func(movie domain.ResponseMovie) error {
+			return writer.WriteRow(exportRow(movie))
+		})
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		return writer.Close()
+	}
+
+	return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("format must be csv, ndjson or xlsx")}
+}
+
+func exportText(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case uint:
+		return strconv.FormatUint(uint64(value), 10)
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
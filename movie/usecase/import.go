@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+// importMovie validates the fields of a row, images are only checked to exist and saved afterwards
+func importMovie(record domain.Record, images map[string]bool) (movie domain.MovieImport, errs []string) {
+	fields := record.Fields
+	movie = domain.MovieImport{Row: record.Row, ExternalID: fields["external_id"]}
+	movie.Request = domain.RequestMovie{
+		Title:       fields["title"],
+		Description: fields["description"],
+		Rating:      fields["rating"],
+		ReleaseDate: fields["release_date"],
+		ImagePath:   fields["image"],
+	}
+
+	if len(movie.ExternalID) > 100 {
+		errs = append(errs, "external_id must be at most 100 characters")
+	}
+	if movie.Request.Title == "" {
+		errs = append(errs, "title is required")
+	}
+
+	if movie.Request.Rating != "" {
+		rating, err := strconv.ParseFloat(movie.Request.Rating, 64)
+		if err != nil || rating < 0 || rating > 10 {
+			errs = append(errs, "rating must be a number between 0 and 10")
+		}
+		movie.Request.FloatRating = rating
+	}
+
+	if fields["runtime"] != "" {
+		runtime, err := strconv.Atoi(fields["runtime"])
+		if err != nil || runtime < 0 {
+			errs = append(errs, "runtime must be a positive number of minutes")
+		}
+		movie.Request.Runtime = runtime
+	}
+
+	if movie.Request.ReleaseDate != "" {
+		if _, err := time.Parse(domain.ReleaseDateLayout, movie.Request.ReleaseDate); err != nil {
+			errs = append(errs, "release_date must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	image := movie.Request.ImagePath
+	if image != "" && !helper.IsImageURL(image) && !images[path.Clean(strings.TrimPrefix(image, "/"))] {
+		errs = append(errs, "image "+image+" is neither a url nor a file of the images zip")
+	}
+	return movie, errs
+}
+
+// saveImportImage downloads the image URL or copies the image from the ZIP, the file name is prefixed by
+// the external ID or the title and a random part so images sharing a name never overwrite each other, nor
+// the image of a movie imported before
+func (mvu *movieUseCase) saveImportImage(request domain.RequestMovieImport, movie domain.MovieImport) (imagePath string, err error) {
+	image := movie.Request.ImagePath
+	if image == "" {
+		return "", nil
+	}
+
+	parentPath := viper.GetString("server.url_assets")
+	subPath := "images/banner"
+	maxSize := viper.GetInt64("import.max_image_size")
+	prefix := helper.Slugify(movie.ExternalID)
+	if prefix == "" {
+		prefix = helper.Slugify(movie.Request.Title)
+	}
+	prefix += "-" + helper.RandomHex(4) + "-"
+
+	if helper.IsImageURL(image) {
+		timeout := time.Duration(viper.GetInt("import.image_timeout")) * time.Second
+		return helper.DownloadImage(image, prefix, parentPath, subPath, maxSize, timeout)
+	}
+
+	name := path.Clean(strings.TrimPrefix(image, "/"))
+	src, err := request.Images.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	return helper.SaveImage(src, prefix+path.Base(name), parentPath, subPath, maxSize)
+}
+
+// ImportMovie reads, validates and writes the rows of a movie file. In transaction mode a single invalid row
+// leaves every movie untouched and the valid rows are reported skipped, in best effort mode the valid rows
+// are written. A row with an external ID already imported updates that movie
+func (mvu *movieUseCase) ImportMovie(ctx context.Context, request domain.RequestMovieImport) (response domain.ResponseMovieImport, err error) {
+	if request.Mode == "" {
+		request.Mode = domain.ImportModeTransaction
+	}
+	if request.Mode != domain.ImportModeTransaction && request.Mode != domain.ImportModeBestEffort {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("mode must be transaction or best_effort")}
+	}
+
+	records, err := helper.ReadRecords(request.File, request.Format)
+	if err != nil {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: err}
+	}
+	if len(records) == 0 {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("file has no row")}
+	}
+	if maxRows := viper.GetInt("import.max_rows"); maxRows > 0 && len(records) > maxRows {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("file has more than %d rows", maxRows)}
+	}
+
+	images := map[string]bool{}
+	if request.Images != nil {
+		for _, file := range request.Images.File {
+			if !file.FileInfo().IsDir() {
+				images[path.Clean(file.Name)] = true
+			}
+		}
+	}
+
+	response = domain.ResponseMovieImport{Mode: request.Mode, Total: len(records), Rows: make([]domain.ResponseMovieImportRow, len(records))}
+	var movies []domain.MovieImport
+	index := map[int]int{}
+	seen := map[string]int{}
+	for idx, record := range records {
+		movie, errs := importMovie(record, images)
+		if first, ok := seen[movie.ExternalID]; ok && movie.ExternalID != "" {
+			errs = append(errs, fmt.Sprintf("external_id is already used by row %d", first))
+		} else if movie.ExternalID != "" {
+			seen[movie.ExternalID] = record.Row
+		}
+
+		response.Rows[idx] = domain.ResponseMovieImportRow{Row: record.Row, ExternalID: movie.ExternalID, Status: domain.ImportStatusFailed, Errors: errs}
+		if len(errs) == 0 {
+			index[record.Row] = idx
+			movies = append(movies, movie)
+		}
+	}
+
+	// Images are saved once the whole file is checked, so a rejected transaction saves none, and the
+	// images saved for rows that end up not written are removed
+	atomic := request.Mode == domain.ImportModeTransaction
+	saved := map[int]string{}
+	if !atomic || len(movies) == len(records) {
+		valid := movies[:0]
+		for _, movie := range movies {
+			movie.Request.ImagePath, err = mvu.saveImportImage(request, movie)
+			if err != nil {
+				log.Warn(err)
+				response.Rows[index[movie.Row]].Errors = []string{"image could not be saved: " + err.Error()}
+				continue
+			}
+			if movie.Request.ImagePath != "" {
+				saved[movie.Row] = movie.Request.ImagePath
+			}
+			valid = append(valid, movie)
+		}
+		movies = valid
+	}
+
+	if atomic && len(movies) != len(records) {
+		removeImages(saved, nil)
+		for idx := range response.Rows {
+			if len(response.Rows[idx].Errors) == 0 {
+				response.Rows[idx].Status = domain.ImportStatusSkipped
+			}
+		}
+		movies = nil
+	}
+
+	if len(movies) > 0 {
+		results, err := mvu.movieMySQLRepo.ImportMovie(ctx, movies, atomic, request.User)
+		if err != nil {
+			removeImages(saved, nil)
+			log.Error(err)
+			return domain.ResponseMovieImport{}, err
+		}
+		for _, result := range results {
+			response.Rows[index[result.Row]] = result
+		}
+		removeImages(saved, func(row int) bool { return response.Rows[index[row]].Status != domain.ImportStatusFailed })
+		response.Committed = true
+	}
+
+	for _, row := range response.Rows {
+		switch row.Status {
+		case domain.ImportStatusCreated:
+			response.Created++
+		case domain.ImportStatusUpdated:
+			response.Updated++
+		case domain.ImportStatusFailed:
+			response.Failed++
+		}
+	}
+	return response, nil
+}
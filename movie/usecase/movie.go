@@ -2,6 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
 	"xsis-academy-test-service-movie/domain"
 	"xsis-academy-test-service-movie/helper"
 
@@ -10,17 +15,111 @@ import (
 )
 
 type movieUseCase struct {
-	movieUseCase   domain.MovieUseCase
-	movieMySQLRepo domain.MovieMySQLRepo
+	movieUseCase        domain.MovieUseCase
+	movieMySQLRepo      domain.MovieMySQLRepo
+	movieRedisRepo      domain.MovieRedisRepo
+	collectionMySQLRepo domain.CollectionMySQLRepo
+	tagMySQLRepo        domain.TagMySQLRepo
+	reviewMySQLRepo     domain.ReviewMySQLRepo
+	watchlistMySQLRepo  domain.WatchlistMySQLRepo
+	userListMySQLRepo   domain.UserListMySQLRepo
+	location            *time.Location
 }
 
-func NewMovieUsecase(MovieMySQLRepo domain.MovieMySQLRepo) domain.MovieUseCase {
+func NewMovieUsecase(MovieMySQLRepo domain.MovieMySQLRepo, MovieRedisRepo domain.MovieRedisRepo, CollectionMySQLRepo domain.CollectionMySQLRepo, TagMySQLRepo domain.TagMySQLRepo, ReviewMySQLRepo domain.ReviewMySQLRepo, WatchlistMySQLRepo domain.WatchlistMySQLRepo, UserListMySQLRepo domain.UserListMySQLRepo) domain.MovieUseCase {
+	location, err := time.LoadLocation(constant.TimeLocation)
+	if err != nil {
+		location = time.Local
+	}
+
 	return &movieUseCase{
-		movieMySQLRepo: MovieMySQLRepo,
+		movieMySQLRepo:      MovieMySQLRepo,
+		movieRedisRepo:      MovieRedisRepo,
+		collectionMySQLRepo: CollectionMySQLRepo,
+		tagMySQLRepo:        TagMySQLRepo,
+		reviewMySQLRepo:     ReviewMySQLRepo,
+		watchlistMySQLRepo:  WatchlistMySQLRepo,
+		userListMySQLRepo:   UserListMySQLRepo,
+		location:            location,
+	}
+}
+
+// attachUserMovieFlag sets the watchlist and favorite flags of the authenticated user
+func (mvu *movieUseCase) attachUserMovieFlag(ctx context.Context, movies []domain.ResponseMovie, user *domain.AuthUser) (err error) {
+	if user == nil {
+		return
+	}
+
+	movieIDs := make([]int, len(movies))
+	for idx, movie := range movies {
+		movieIDs[idx] = int(movie.ID)
+	}
+
+	flags, err := mvu.watchlistMySQLRepo.GetUserMovieFlag(ctx, user.ID, movieIDs)
+	if err != nil {
+		return err
+	}
+
+	for idx := range movies {
+		flag := flags[int(movies[idx].ID)]
+		movies[idx].InWatchlist = &flag.InWatchlist
+		movies[idx].IsFavorite = &flag.IsFavorite
+	}
+	return
+}
+
+// attachUserRating sets the aggregated user review rating of every movie
+func (mvu *movieUseCase) attachUserRating(ctx context.Context, movies []domain.ResponseMovie) (err error) {
+	movieIDs := make([]int, len(movies))
+	for idx, movie := range movies {
+		movieIDs[idx] = int(movie.ID)
+	}
+
+	aggregates, err := mvu.reviewMySQLRepo.GetRatingAggregate(ctx, movieIDs)
+	if err != nil {
+		return err
+	}
+
+	globalMean, globalCount, err := mvu.reviewMySQLRepo.GetGlobalMean(ctx)
+	if err != nil {
+		return err
+	}
+	if globalCount == 0 {
+		globalMean = viper.GetFloat64("review.prior_mean")
+	}
+
+	for idx := range movies {
+		userRating := helper.RatingSummary(aggregates[int(movies[idx].ID)], globalMean, viper.GetFloat64("review.bayesian_min_votes"))
+		movies[idx].UserRating = &userRating
+	}
+	return
+}
+
+func canTransition(from string, to string) bool {
+	for _, status := range domain.MovieTransition[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (mvu *movieUseCase) validate(request *domain.RequestMovie) (err error) {
+	request.ReleaseDate = strings.TrimSpace(request.ReleaseDate)
+	if request.ReleaseDate != "" {
+		if _, err := time.Parse(domain.ReleaseDateLayout, request.ReleaseDate); err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("release_date must be formatted as YYYY-MM-DD")}
+		}
 	}
+	return
 }
 
 func (mvu *movieUseCase) PostMovie(ctx context.Context, request domain.RequestMovie) (err error) {
+	err = mvu.validate(&request)
+	if err != nil {
+		return err
+	}
+
 	parentPath := viper.GetString("server.url_assets")
 	subPath := "images/banner"
 	imagePath, err := helper.SaveImageToLocalDrive(request.Image, parentPath, subPath)
@@ -38,6 +137,11 @@ func (mvu *movieUseCase) PostMovie(ctx context.Context, request domain.RequestMo
 }
 
 func (mvu *movieUseCase) GetAllMovie(ctx context.Context, request domain.RequestParamMovie) (response domain.ResponseGetAllMovie, err error) {
+	if !domain.CanManageMovie(request.User) {
+		published := domain.MovieStatusPublished
+		request.Status = &published
+	}
+
 	resMovieCount, err := mvu.movieMySQLRepo.CountDataMovie(ctx, request)
 	if err != nil {
 		return domain.ResponseGetAllMovie{}, err
@@ -48,6 +152,16 @@ func (mvu *movieUseCase) GetAllMovie(ctx context.Context, request domain.Request
 		return response, err
 	}
 
+	err = mvu.attachUserRating(ctx, resMovie)
+	if err != nil {
+		return response, err
+	}
+
+	err = mvu.attachUserMovieFlag(ctx, resMovie, request.User)
+	if err != nil {
+		return response, err
+	}
+
 	response = domain.ResponseGetAllMovie{
 		MetaData: resMovieCount,
 		Data:     resMovie,
@@ -57,24 +171,76 @@ func (mvu *movieUseCase) GetAllMovie(ctx context.Context, request domain.Request
 	return
 }
 
-func (mvu *movieUseCase) DeleteMovie(ctx context.Context, id int) (err error) {
+func (mvu *movieUseCase) DeleteMovie(ctx context.Context, id int, user *domain.AuthUser) (err error) {
 	_, err = mvu.movieMySQLRepo.GetDetailMovie(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	err = mvu.movieMySQLRepo.DeleteMovie(ctx, id)
+	err = mvu.movieMySQLRepo.DeleteMovie(ctx, id, user)
 	if err != nil {
+		if err.Error() == "Booked" {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("movie has showtimes with bookings")}
+		}
 		return err
 	}
 	return
 }
 
-func (mvu *movieUseCase) GetDetailMovie(ctx context.Context, id int) (response domain.ResponseMovie, err error) {
+func (mvu *movieUseCase) GetDetailMovie(ctx context.Context, id int, user *domain.AuthUser, viewer string) (response domain.ResponseMovie, err error) {
 	response, err = mvu.movieMySQLRepo.GetDetailMovie(ctx, id)
 	if err != nil {
 		return response, err
 	}
+
+	// Unpublished movies are hidden as if they did not exist and their views are not counted
+	if response.Status != domain.MovieStatusPublished {
+		if !domain.CanManageMovie(user) {
+			return domain.ResponseMovie{}, errors.New("Not found")
+		}
+		viewer = ""
+	}
+
+	// A view that cannot be recorded must not fail the request
+	if viewer != "" {
+		if err := mvu.movieRedisRepo.RecordView(ctx, id, viewer); err != nil {
+			log.Warn(err)
+		}
+	}
+
+	response.Collections, err = mvu.collectionMySQLRepo.GetCollectionByMovie(ctx, id)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+
+	response.Tags, err = mvu.tagMySQLRepo.GetTagByMovie(ctx, id)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+
+	response.Credits, err = mvu.movieMySQLRepo.GetMovieCredit(ctx, id)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+
+	// Only public lists are counted so private lists are not disclosed
+	listCount, err := mvu.userListMySQLRepo.CountPublicUserListByMovie(ctx, id)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+	response.ListCount = &listCount
+
+	movies := []domain.ResponseMovie{response}
+	err = mvu.attachUserRating(ctx, movies)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+
+	err = mvu.attachUserMovieFlag(ctx, movies, user)
+	if err != nil {
+		return domain.ResponseMovie{}, err
+	}
+	response = movies[0]
 	return
 }
 
@@ -84,6 +250,11 @@ func (mvu *movieUseCase) UpdateMovie(ctx context.Context, id int, request domain
 		return err
 	}
 
+	err = mvu.validate(&request)
+	if err != nil {
+		return err
+	}
+
 	if request.Image.Filename != "" {
 		parentPath := viper.GetString("server.url_assets")
 		subPath := "images/banner"
@@ -100,3 +271,234 @@ func (mvu *movieUseCase) UpdateMovie(ctx context.Context, id int, request domain
 	}
 	return
 }
+
+func (mvu *movieUseCase) GetTrendingMovie(ctx context.Context, window string, limit int) (response []domain.ResponseMovie, err error) {
+	if window != domain.TrendingWindowDay && window != domain.TrendingWindowWeek {
+		return nil, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("window must be day or week")}
+	}
+	if limit <= 0 {
+		limit = viper.GetInt("popularity.trending_limit")
+	}
+
+	scores, err := mvu.movieRedisRepo.GetTrendingScore(ctx, window, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	movieIDs := make([]int, len(scores))
+	for idx, score := range scores {
+		movieIDs[idx] = score.MovieID
+	}
+
+	movies, err := mvu.movieMySQLRepo.GetMovieByIDs(ctx, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response = []domain.ResponseMovie{}
+	for _, score := range scores {
+		movie, ok := movies[score.MovieID]
+		if !ok || movie.Status != domain.MovieStatusPublished {
+			continue
+		}
+		trending := score.Score
+		movie.Trending = &trending
+		response = append(response, movie)
+	}
+	return response, nil
+}
+
+// viewFlushLock bounds how long a flush holds the lock when it dies without releasing it
+const viewFlushLock = 5 * time.Minute
+
+// FlushMovieView moves the view counts from Redis to MySQL and stores the week trending score as popularity.
+// The batch stays in Redis until MySQL commits, a failed flush is retried with the same batch on the next run
+// and its flush ID keeps it from being counted twice. One instance flushes at a time, the others skip the run
+func (mvu *movieUseCase) FlushMovieView(ctx context.Context) (err error) {
+	owner := helper.RandomHex(16)
+	locked, err := mvu.movieRedisRepo.LockViewFlush(ctx, owner, viewFlushLock)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		log.Info("views are being flushed by another instance")
+		return nil
+	}
+	defer func() {
+		if err := mvu.movieRedisRepo.UnlockViewFlush(ctx, owner); err != nil {
+			log.Warn(err)
+		}
+	}()
+
+	flushID, views, err := mvu.movieRedisRepo.PopPendingView(ctx)
+	if err != nil {
+		return err
+	}
+
+	popularity, err := mvu.movieRedisRepo.GetTrendingScore(ctx, domain.TrendingWindowWeek, 0)
+	if err != nil {
+		return err
+	}
+
+	err = mvu.movieMySQLRepo.UpdateMovieView(ctx, flushID, views, popularity)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = mvu.movieRedisRepo.DeleteFlushedView(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("flushed views of %d movies", len(views))
+	return nil
+}
+
+// UpdateMovieStatus moves a movie along the publishing workflow. Publishing sets the publish time to now,
+// a publish time given with the review status schedules the publication and leaving review clears it
+func (mvu *movieUseCase) UpdateMovieStatus(ctx context.Context, id int, request domain.RequestMovieStatus) (response domain.ResponseMovie, err error) {
+	movie, err := mvu.movieMySQLRepo.GetDetailMovie(ctx, id)
+	if err != nil {
+		return response, err
+	}
+
+	if _, ok := domain.MovieTransition[request.Status]; !ok {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("status must be draft, review, published or archived")}
+	}
+
+	// A movie in review may stay in review to reschedule its publication
+	reschedule := movie.Status == domain.MovieStatusReview && request.Status == domain.MovieStatusReview
+	if !reschedule && !canTransition(movie.Status, request.Status) {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("movie cannot move from %s to %s", movie.Status, request.Status)}
+	}
+
+	var publishAt *string
+	if request.PublishAt != nil && strings.TrimSpace(*request.PublishAt) != "" {
+		if request.Status != domain.MovieStatusReview {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("publish_at is only scheduled with the review status")}
+		}
+		value := strings.TrimSpace(*request.PublishAt)
+		at, err := helper.LocalTime(value)
+		if err != nil {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("publish_at must be formatted as YYYY-MM-DD HH:MM:SS")}
+		}
+		if !at.After(time.Now()) {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("publish_at must be in the future")}
+		}
+		publishAt = &value
+	}
+	if request.Status == domain.MovieStatusPublished {
+		now := time.Now().In(mvu.location).Format(domain.PublishAtLayout)
+		publishAt = &now
+	}
+
+	err = mvu.movieMySQLRepo.UpdateMovieStatus(ctx, id, movie.Status, request.Status, publishAt, request.User)
+	if err != nil {
+		if err.Error() == "Conflict" {
+			return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("movie status has changed, please try again")}
+		}
+		return response, err
+	}
+
+	return mvu.movieMySQLRepo.GetDetailMovie(ctx, id)
+}
+
+// PublishScheduledMovie publishes the movies in review whose publish time is reached
+func (mvu *movieUseCase) PublishScheduledMovie(ctx context.Context) (err error) {
+	published, err := mvu.movieMySQLRepo.PublishScheduledMovie(ctx)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if published > 0 {
+		log.Infof("published %d scheduled movies", published)
+	}
+	return nil
+}
+
+// snapshotFields lists the fields of a snapshot in a stable order, nil pointers are kept as nil
+func snapshotFields(snapshot domain.MovieSnapshot) (names []string, values []interface{}) {
+	optional := func(value *string) interface{} {
+		if value == nil {
+			return nil
+		}
+		return *value
+	}
+
+	names = []string{"title", "description", "rating", "runtime", "release_date", "status", "publish_at", "image"}
+	values = []interface{}{snapshot.Title, snapshot.Description, snapshot.Rating, snapshot.Runtime,
+		optional(snapshot.ReleaseDate), snapshot.Status, optional(snapshot.PublishAt), snapshot.Image}
+	return names, values
+}
+
+// diffSnapshot lists the fields changed from the previous snapshot, every field when there is none
+func diffSnapshot(previous *domain.MovieSnapshot, current domain.MovieSnapshot) []domain.MovieFieldChange {
+	names, values := snapshotFields(current)
+	var previousValues []interface{}
+	if previous != nil {
+		_, previousValues = snapshotFields(*previous)
+	}
+
+	changes := []domain.MovieFieldChange{}
+	for idx, name := range names {
+		var old interface{}
+		if previous != nil {
+			old = previousValues[idx]
+			if old == values[idx] {
+				continue
+			}
+		}
+		changes = append(changes, domain.MovieFieldChange{Field: name, Old: old, New: values[idx]})
+	}
+	return changes
+}
+
+// GetAllMovieRevision returns the revisions of a movie, newest first, each with the fields it changed
+func (mvu *movieUseCase) GetAllMovieRevision(ctx context.Context, request domain.RequestParamMovieRevision) (response domain.ResponseGetAllMovieRevision, err error) {
+	response.MetaData, err = mvu.movieMySQLRepo.CountDataMovieRevision(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data, err = mvu.movieMySQLRepo.GetAllMovieRevision(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	// The oldest revision of the page is compared with the revision before it, out of the page
+	var previous *domain.MovieSnapshot
+	if count := len(response.Data); count > 0 && response.Data[count-1].Revision > 1 {
+		revision, err := mvu.movieMySQLRepo.GetMovieRevision(ctx, request.MovieID, response.Data[count-1].Revision-1)
+		if err != nil && err.Error() != "Not found" {
+			return response, err
+		}
+		if err == nil {
+			previous = &revision.Snapshot
+		}
+	}
+
+	for idx := len(response.Data) - 1; idx >= 0; idx-- {
+		response.Data[idx].Changes = diffSnapshot(previous, response.Data[idx].Snapshot)
+		previous = &response.Data[idx].Snapshot
+	}
+	return response, nil
+}
+
+// RestoreMovieRevision brings back the content of a revision as a new revision, the status is left
+// to the publishing workflow. A deleted movie comes back as a draft
+func (mvu *movieUseCase) RestoreMovieRevision(ctx context.Context, movieID int, revision int, user *domain.AuthUser) (response domain.ResponseMovie, err error) {
+	rev, err := mvu.movieMySQLRepo.GetMovieRevision(ctx, movieID, revision)
+	if err != nil {
+		return response, err
+	}
+
+	err = mvu.movieMySQLRepo.RestoreMovie(ctx, movieID, rev.Snapshot, user)
+	if err != nil {
+		log.Error(err)
+		return response, err
+	}
+
+	return mvu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+}
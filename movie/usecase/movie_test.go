@@ -0,0 +1,315 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/movie/usecase"
+
+	"github.com/spf13/viper"
+)
+
+// viewRedis keeps a pending and a flushing batch with its flush ID and the flush lock like the Redis repository
+type viewRedis struct {
+	domain.MovieRedisRepo
+	pending, flushing map[int]int64
+	flushID           string
+	batches           int
+	failDelete        bool
+	lock              string
+}
+
+func (repo *viewRedis) PopPendingView(ctx context.Context) (string, map[int]int64, error) {
+	if repo.flushing == nil {
+		repo.flushing, repo.pending = repo.pending, map[int]int64{}
+		repo.batches++
+		repo.flushID = fmt.Sprintf("batch-%d", repo.batches)
+	}
+	return repo.flushID, repo.flushing, nil
+}
+
+func (repo *viewRedis) GetTrendingScore(ctx context.Context, window string, limit int) ([]domain.MovieScore, error) {
+	return nil, nil
+}
+
+func (repo *viewRedis) DeleteFlushedView(ctx context.Context) error {
+	if repo.failDelete {
+		return errors.New("redis: connection reset")
+	}
+	repo.flushing = nil
+	return nil
+}
+
+func (repo *viewRedis) LockViewFlush(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	if repo.lock != "" {
+		return false, nil
+	}
+	repo.lock = owner
+	return true, nil
+}
+
+func (repo *viewRedis) UnlockViewFlush(ctx context.Context, owner string) error {
+	if repo.lock == owner {
+		repo.lock = ""
+	}
+	return nil
+}
+
+// viewMySQL adds a batch once per flush ID like the movie_view_flush table
+type viewMySQL struct {
+	domain.MovieMySQLRepo
+	fail    bool
+	views   map[int]int64
+	flushed map[string]bool
+}
+
+func (repo *viewMySQL) UpdateMovieView(ctx context.Context, flushID string, views map[int]int64, popularity []domain.MovieScore) error {
+	if repo.fail {
+		return errors.New("driver: bad connection")
+	}
+	if repo.flushed[flushID] {
+		return nil
+	}
+	repo.flushed[flushID] = true
+	for movieID, count := range views {
+		repo.views[movieID] += count
+	}
+	return nil
+}
+
+func TestFlushMovieViewRetriesFailedBatch(t *testing.T) {
+	redis := &viewRedis{pending: map[int]int64{7: 3}}
+	mysql := &viewMySQL{fail: true, views: map[int]int64{}, flushed: map[string]bool{}}
+	movieUseCase := usecase.NewMovieUsecase(mysql, redis, nil, nil, nil, nil, nil)
+
+	if err := movieUseCase.FlushMovieView(context.Background()); err == nil {
+		t.Fatal("got no error, want the storage failure")
+	}
+	if redis.flushing[7] != 3 {
+		t.Fatalf("got flushing %v, want the batch kept after the failure", redis.flushing)
+	}
+
+	// Views recorded meanwhile wait for the next flush, the failed batch is stored once
+	redis.pending[7] = 2
+	mysql.fail = false
+	if err := movieUseCase.FlushMovieView(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mysql.views[7] != 3 || redis.flushing != nil {
+		t.Fatalf("got views %v and flushing %v, want the failed batch stored and dropped", mysql.views, redis.flushing)
+	}
+
+	if err := movieUseCase.FlushMovieView(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mysql.views[7] != 5 {
+		t.Fatalf("got views %v, want 5", mysql.views)
+	}
+}
+
+func TestFlushMovieViewReplayedBatch(t *testing.T) {
+	redis := &viewRedis{pending: map[int]int64{7: 3}, failDelete: true}
+	mysql := &viewMySQL{views: map[int]int64{}, flushed: map[string]bool{}}
+	movieUseCase := usecase.NewMovieUsecase(mysql, redis, nil, nil, nil, nil, nil)
+
+	// The batch is committed but stays in Redis, the next flush takes it again with the same flush ID
+	if err := movieUseCase.FlushMovieView(context.Background()); err == nil {
+		t.Fatal("got no error, want the cleanup failure")
+	}
+	redis.failDelete = false
+	if err := movieUseCase.FlushMovieView(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mysql.views[7] != 3 || redis.flushing != nil || redis.lock != "" {
+		t.Fatalf("got views %v, flushing %v and lock %q, want the batch counted once and the lock released", mysql.views, redis.flushing, redis.lock)
+	}
+}
+
+func TestFlushMovieViewLocked(t *testing.T) {
+	redis := &viewRedis{pending: map[int]int64{7: 3}, lock: "other"}
+	mysql := &viewMySQL{views: map[int]int64{}, flushed: map[string]bool{}}
+	movieUseCase := usecase.NewMovieUsecase(mysql, redis, nil, nil, nil, nil, nil)
+
+	if err := movieUseCase.FlushMovieView(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(mysql.views) != 0 || redis.pending[7] != 3 || redis.lock != "other" {
+		t.Fatalf("got views %v and lock %q, want the flush left to the lock holder", mysql.views, redis.lock)
+	}
+}
+
+// batchRepo writes the operations or the import rows, the one numbered failAt fails like a lost connection
+type batchRepo struct {
+	domain.MovieMySQLRepo
+	failAt int
+}
+
+func (repo *batchRepo) ImportMovie(ctx context.Context, movies []domain.MovieImport, atomic bool, user *domain.AuthUser) (response []domain.ResponseMovieImportRow, err error) {
+	for idx, movie := range movies {
+		if idx+1 == repo.failAt {
+			if atomic {
+				return nil, errors.New("driver: bad connection")
+			}
+			response = append(response, domain.ResponseMovieImportRow{Row: movie.Row, Status: domain.ImportStatusFailed, Errors: []string{"movie could not be saved"}})
+			continue
+		}
+		movieID := idx + 1
+		response = append(response, domain.ResponseMovieImportRow{Row: movie.Row, MovieID: &movieID, Status: domain.ImportStatusCreated})
+	}
+	return response, nil
+}
+
+func (repo *batchRepo) BatchMovie(ctx context.Context, operations []domain.MovieBatchOperation, atomic bool, user *domain.AuthUser) (response []domain.ResponseMovieBatchResult, err error) {
+	for idx, operation := range operations {
+		if idx+1 == repo.failAt {
+			if atomic {
+				return response, errors.New("driver: bad connection")
+			}
+			response = append(response, domain.ResponseMovieBatchResult{Index: operation.Index, Op: operation.Op, Status: domain.ImportStatusFailed, Errors: []string{"movie could not be saved"}})
+			continue
+		}
+		movieID := idx + 1
+		response = append(response, domain.ResponseMovieBatchResult{Index: operation.Index, Op: operation.Op, MovieID: &movieID, Status: domain.ImportStatusCreated})
+	}
+	return response, nil
+}
+
+// imageServer serves every path as a PNG image, downloaded to the banner directory of a temporary assets path
+func imageServer(t *testing.T) (serverURL string, banner string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}))
+	t.Cleanup(server.Close)
+
+	assets := t.TempDir()
+	viper.Set("server.url_assets", assets)
+	viper.Set("import.allow_private_hosts", true)
+	t.Cleanup(viper.Reset)
+	banner = filepath.Join(assets, "images/banner")
+	if err := os.MkdirAll(banner, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return server.URL, banner
+}
+
+func batchImages(t *testing.T) (request domain.RequestMovieBatch, banner string) {
+	t.Helper()
+	serverURL, banner := imageServer(t)
+	for _, title := range []string{"Heat", "Ronin", "Thief"} {
+		title, image, rating, runtime := title, serverURL+"/"+title+".png", 7.5, 120
+		request.Operations = append(request.Operations, domain.RequestMovieBatchOperation{Op: domain.BatchOpCreate, Title: &title, Rating: &rating, Runtime: &runtime, Image: &image})
+	}
+	return request, banner
+}
+
+func savedImages(t *testing.T, banner string) int {
+	t.Helper()
+	entries, err := os.ReadDir(banner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+func TestBatchMovieRollbackRemovesImages(t *testing.T) {
+	request, banner := batchImages(t)
+	movieUseCase := usecase.NewMovieUsecase(&batchRepo{failAt: 2}, nil, nil, nil, nil, nil, nil)
+
+	response, err := movieUseCase.BatchMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Committed || response.FailedIndex == nil || *response.FailedIndex != 1 || response.Results[1].Status != domain.ImportStatusFailed {
+		t.Fatalf("got %+v, want the transaction rejected by operation 1", response)
+	}
+	if response.Results[0].Status != domain.ImportStatusSkipped || response.Results[2].Status != domain.ImportStatusSkipped {
+		t.Fatalf("got %+v, want the other operations skipped", response.Results)
+	}
+	if saved := savedImages(t, banner); saved != 0 {
+		t.Fatalf("got %d images, want the images of the rolled back batch removed", saved)
+	}
+}
+
+func TestBatchMovieBestEffortKeepsWrittenImages(t *testing.T) {
+	request, banner := batchImages(t)
+	request.Mode = domain.ImportModeBestEffort
+	movieUseCase := usecase.NewMovieUsecase(&batchRepo{failAt: 2}, nil, nil, nil, nil, nil, nil)
+
+	response, err := movieUseCase.BatchMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Committed || response.FailedIndex != nil || response.Created != 2 || response.Failed != 1 {
+		t.Fatalf("got %+v, want 2 created and 1 failed", response)
+	}
+	if saved := savedImages(t, banner); saved != 2 {
+		t.Fatalf("got %d images, want only the images of the written movies", saved)
+	}
+}
+
+func TestBatchMovieImagesSharingName(t *testing.T) {
+	request, banner := batchImages(t)
+	request.Mode = domain.ImportModeBestEffort
+	for idx := range request.Operations {
+		request.Operations[idx].Title = request.Operations[0].Title
+		request.Operations[idx].Image = request.Operations[0].Image
+	}
+	movieUseCase := usecase.NewMovieUsecase(&batchRepo{failAt: 2}, nil, nil, nil, nil, nil, nil)
+
+	response, err := movieUseCase.BatchMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The failed operation downloaded the same URL for the same title, removing it keeps the written ones
+	if saved := savedImages(t, banner); saved != 2 || response.Created != 2 {
+		t.Fatalf("got %d images for %d created movies, want one image per written movie", saved, response.Created)
+	}
+}
+
+func importImages(t *testing.T, mode string) (request domain.RequestMovieImport, banner string) {
+	t.Helper()
+	serverURL, banner := imageServer(t)
+	file := "title,runtime,image\n"
+	for _, title := range []string{"Heat", "Ronin", "Thief"} {
+		file += title + ",120," + serverURL + "/" + title + ".png\n"
+	}
+	return domain.RequestMovieImport{File: strings.NewReader(file), Format: domain.ImportFormatCSV, Mode: mode}, banner
+}
+
+func TestImportMovieFailureRemovesImages(t *testing.T) {
+	request, banner := importImages(t, domain.ImportModeTransaction)
+	movieUseCase := usecase.NewMovieUsecase(&batchRepo{failAt: 2}, nil, nil, nil, nil, nil, nil)
+
+	if _, err := movieUseCase.ImportMovie(context.Background(), request); err == nil {
+		t.Fatal("got no error, want the storage failure")
+	}
+	if saved := savedImages(t, banner); saved != 0 {
+		t.Fatalf("got %d images, want the images of the failed import removed", saved)
+	}
+}
+
+func TestImportMovieBestEffortKeepsWrittenImages(t *testing.T) {
+	request, banner := importImages(t, domain.ImportModeBestEffort)
+	movieUseCase := usecase.NewMovieUsecase(&batchRepo{failAt: 2}, nil, nil, nil, nil, nil, nil)
+
+	response, err := movieUseCase.ImportMovie(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Created != 2 || response.Failed != 1 {
+		t.Fatalf("got %+v, want 2 created and 1 failed", response)
+	}
+	if saved := savedImages(t, banner); saved != 2 {
+		t.Fatalf("got %d images, want only the images of the written movies", saved)
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/promo/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for the promo code REST API, promos are managed by admins
+func RouterAPI(app *fiber.App, PromoUseCase domain.PromoUseCase) {
+	handlerPromo := &handler.PromoHandler{PromoUseCase: PromoUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	promo := app.Group(basePath+"/promo", middleware.Auth, middleware.Role(domain.RoleAdmin))
+	auditPromo := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		return PromoUseCase.GetDetailPromo(c.Context(), id)
+	})
+
+	promo.Get("", handlerPromo.GetAllPromo)
+	promo.Post("", auditPromo, handlerPromo.PostPromo)
+	promo.Get("/:id", handlerPromo.GetDetailPromo)
+	promo.Patch("/:id", auditPromo, handlerPromo.UpdatePromo)
+	promo.Delete("/:id", auditPromo, handlerPromo.DeletePromo)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type PromoHandler struct {
+	PromoUseCase domain.PromoUseCase
+}
+
+func (ph *PromoHandler) GetAllPromo(c *fiber.Ctx) error {
+	var input domain.RequestParamPromo
+	var err error
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	search := c.Query("search")
+	if search != "" {
+		input.Search = &search
+	}
+
+	res, err := ph.PromoUseCase.GetAllPromo(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (ph *PromoHandler) PostPromo(c *fiber.Ctx) (err error) {
+	var input domain.RequestPromo
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	id, err := ph.PromoUseCase.PostPromo(c.Context(), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+func (ph *PromoHandler) GetDetailPromo(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := ph.PromoUseCase.GetDetailPromo(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (ph *PromoHandler) UpdatePromo(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestPromo
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ph.PromoUseCase.UpdatePromo(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (ph *PromoHandler) DeletePromo(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = ph.PromoUseCase.DeletePromo(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
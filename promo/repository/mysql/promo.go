@@ -0,0 +1,305 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlPromoRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLPromoRepository(Conn *sql.DB) domain.PromoMySQLRepo {
+	return &mysqlPromoRepository{Conn}
+}
+
+const promoQuery = `SELECT id, code, description, discount_type, discount_value, max_discount, movie_id, theater_id,
+                  starts_at, ends_at, usage_limit, per_user_limit, used_count, priority, stackable, active, dtm_crt, dtm_upd
+              FROM promo`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromo(row rowScanner) (response domain.ResponsePromo, err error) {
+	var maxDiscount sql.NullInt64
+	var movieID, theaterID, usageLimit, perUserLimit sql.NullInt32
+	var startsAt, endsAt sql.NullTime
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.Code,
+		&response.Description,
+		&response.DiscountType,
+		&response.DiscountValue,
+		&maxDiscount,
+		&movieID,
+		&theaterID,
+		&startsAt,
+		&endsAt,
+		&usageLimit,
+		&perUserLimit,
+		&response.UsedCount,
+		&response.Priority,
+		&response.Stackable,
+		&response.Active,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	if maxDiscount.Valid {
+		response.MaxDiscount = &maxDiscount.Int64
+	}
+	response.MovieID = nullInt(movieID)
+	response.TheaterID = nullInt(theaterID)
+	response.UsageLimit = nullInt(usageLimit)
+	response.PerUserLimit = nullInt(perUserLimit)
+	if startsAt.Valid {
+		value := startsAt.Time.Format(domain.PromoLayout)
+		response.StartsAt = &value
+	}
+	if endsAt.Valid {
+		value := endsAt.Time.Format(domain.PromoLayout)
+		response.EndsAt = &value
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+func nullInt(value sql.NullInt32) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int32)
+	return &i
+}
+
+func (db *mysqlPromoRepository) PostPromo(ctx context.Context, request domain.RequestPromo) (id int, err error) {
+	query := `INSERT INTO promo (code, description, discount_type, discount_value, max_discount, movie_id, theater_id,
+                  starts_at, ends_at, usage_limit, per_user_limit, priority, stackable, active, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, request.Code, request.Description, request.DiscountType, request.DiscountValue,
+		request.MaxDiscount, request.MovieID, request.TheaterID, request.StartsAt, request.EndsAt, request.UsageLimit,
+		request.PerUserLimit, request.Priority, request.Stackable, *request.Active)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+func filterPromo(request domain.RequestParamPromo) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.Search != nil {
+		query += " AND (code LIKE ? OR description LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	return query, args
+}
+
+func (db *mysqlPromoRepository) CountDataPromo(ctx context.Context, request domain.RequestParamPromo) (response domain.MetaData, err error) {
+	filter, args := filterPromo(request)
+	query := "SELECT COUNT(id) as total FROM promo" + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlPromoRepository) GetAllPromo(ctx context.Context, request domain.RequestParamPromo) (response []domain.ResponsePromo, err error) {
+	filter, args := filterPromo(request)
+	query := promoQuery + filter + ` ORDER BY id DESC`
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanPromo(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlPromoRepository) GetDetailPromo(ctx context.Context, id int) (response domain.ResponsePromo, err error) {
+	response, err = scanPromo(db.Conn.QueryRowContext(ctx, promoQuery+` WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponsePromo{}, err
+		}
+		log.Error(err)
+		return domain.ResponsePromo{}, err
+	}
+
+	return response, nil
+}
+
+// GetPromoByCode returns the promos having one of the codes, unknown codes are left out
+func (db *mysqlPromoRepository) GetPromoByCode(ctx context.Context, codes []string) (response []domain.ResponsePromo, err error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(codes))
+	for idx, code := range codes {
+		args[idx] = code
+	}
+
+	query := promoQuery + ` WHERE code IN (?` + strings.Repeat(", ?", len(codes)-1) + `)`
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanPromo(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+// CountUserUsage counts the pending and paid bookings of the user using each promo
+func (db *mysqlPromoRepository) CountUserUsage(ctx context.Context, promoIDs []int, userID int) (response map[int]int, err error) {
+	response = map[int]int{}
+	if len(promoIDs) == 0 {
+		return response, nil
+	}
+
+	args := []interface{}{userID, domain.BookingStatusPending, domain.BookingStatusPaid}
+	for _, id := range promoIDs {
+		args = append(args, id)
+	}
+
+	query := `SELECT bp.promo_id, COUNT(*) FROM booking_promo bp
+              JOIN booking b ON b.id = bp.booking_id
+              WHERE bp.user_id = ? AND b.status IN (?, ?) AND bp.promo_id IN (?` + strings.Repeat(", ?", len(promoIDs)-1) + `)
+              GROUP BY bp.promo_id`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var promoID, total int
+		if err := rows.Scan(&promoID, &total); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[promoID] = total
+	}
+
+	return response, nil
+}
+
+func (db *mysqlPromoRepository) UpdatePromo(ctx context.Context, id int, request domain.RequestPromo) (err error) {
+	query := `UPDATE promo
+              SET code = ?, description = ?, discount_type = ?, discount_value = ?, max_discount = ?, movie_id = ?, theater_id = ?,
+                  starts_at = ?, ends_at = ?, usage_limit = ?, per_user_limit = ?, priority = ?, stackable = ?, active = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.Code, request.Description, request.DiscountType, request.DiscountValue,
+		request.MaxDiscount, request.MovieID, request.TheaterID, request.StartsAt, request.EndsAt, request.UsageLimit,
+		request.PerUserLimit, request.Priority, request.Stackable, *request.Active, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *mysqlPromoRepository) DeletePromo(ctx context.Context, id int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `DELETE FROM promo WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
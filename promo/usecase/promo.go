@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+type promoUseCase struct {
+	promoMySQLRepo   domain.PromoMySQLRepo
+	movieMySQLRepo   domain.MovieMySQLRepo
+	theaterMySQLRepo domain.TheaterMySQLRepo
+	location         *time.Location
+}
+
+func NewPromoUsecase(PromoMySQLRepo domain.PromoMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo, TheaterMySQLRepo domain.TheaterMySQLRepo) domain.PromoUseCase {
+	location, err := time.LoadLocation(constant.TimeLocation)
+	if err != nil {
+		location = time.Local
+	}
+
+	return &promoUseCase{
+		promoMySQLRepo:   PromoMySQLRepo,
+		movieMySQLRepo:   MovieMySQLRepo,
+		theaterMySQLRepo: TheaterMySQLRepo,
+		location:         location,
+	}
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (pmu *promoUseCase) validate(ctx context.Context, id int, request *domain.RequestPromo) (err error) {
+	request.Code = normalizeCode(request.Code)
+	request.Description = strings.TrimSpace(request.Description)
+	if !promoCodePattern.MatchString(request.Code) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("code must be 3 to 50 letters, digits, dash or underscore")}
+	}
+
+	switch request.DiscountType {
+	case domain.PromoTypePercentage:
+		if request.DiscountValue < 1 || request.DiscountValue > 100 {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("discount_value must be between 1 and 100 for percentage promos")}
+		}
+	case domain.PromoTypeFixed:
+		if request.DiscountValue < 1 {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("discount_value must be positive")}
+		}
+	default:
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("discount_type must be percentage or fixed")}
+	}
+
+	if request.MaxDiscount != nil && *request.MaxDiscount < 1 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("max_discount must be positive")}
+	}
+	if request.UsageLimit != nil && *request.UsageLimit < 1 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("usage_limit must be positive")}
+	}
+	if request.PerUserLimit != nil && *request.PerUserLimit < 1 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("per_user_limit must be positive")}
+	}
+
+	var startsAt, endsAt time.Time
+	if request.StartsAt != nil {
+		startsAt, err = time.Parse(domain.PromoLayout, *request.StartsAt)
+		if err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("starts_at must be formatted as YYYY-MM-DD HH:MM:SS")}
+		}
+	}
+	if request.EndsAt != nil {
+		endsAt, err = time.Parse(domain.PromoLayout, *request.EndsAt)
+		if err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("ends_at must be formatted as YYYY-MM-DD HH:MM:SS")}
+		}
+	}
+	if request.StartsAt != nil && request.EndsAt != nil && !endsAt.After(startsAt) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("ends_at must be after starts_at")}
+	}
+
+	if request.MovieID != nil {
+		_, err = pmu.movieMySQLRepo.GetDetailMovie(ctx, *request.MovieID)
+		if err != nil {
+			if err.Error() == "Not found" {
+				return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+			}
+			return err
+		}
+	}
+	if request.TheaterID != nil {
+		_, err = pmu.theaterMySQLRepo.GetDetailTheater(ctx, *request.TheaterID)
+		if err != nil {
+			if err.Error() == "Not found" {
+				return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("theater is not exists")}
+			}
+			return err
+		}
+	}
+
+	existing, err := pmu.promoMySQLRepo.GetPromoByCode(ctx, []string{request.Code})
+	if err != nil {
+		return err
+	}
+	for _, promo := range existing {
+		if int(promo.ID) != id {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("code already exists")}
+		}
+	}
+
+	if request.Active == nil {
+		active := true
+		request.Active = &active
+	}
+	return
+}
+
+func (pmu *promoUseCase) PostPromo(ctx context.Context, request domain.RequestPromo) (id int, err error) {
+	err = pmu.validate(ctx, 0, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = pmu.promoMySQLRepo.PostPromo(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (pmu *promoUseCase) GetAllPromo(ctx context.Context, request domain.RequestParamPromo) (response domain.ResponseGetAllPromo, err error) {
+	resCount, err := pmu.promoMySQLRepo.CountDataPromo(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllPromo{}, err
+	}
+
+	resPromo, err := pmu.promoMySQLRepo.GetAllPromo(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllPromo{
+		MetaData: resCount,
+		Data:     resPromo,
+	}
+	return
+}
+
+func (pmu *promoUseCase) GetDetailPromo(ctx context.Context, id int) (response domain.ResponsePromo, err error) {
+	return pmu.promoMySQLRepo.GetDetailPromo(ctx, id)
+}
+
+func (pmu *promoUseCase) UpdatePromo(ctx context.Context, id int, request domain.RequestPromo) (err error) {
+	_, err = pmu.promoMySQLRepo.GetDetailPromo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = pmu.validate(ctx, id, &request)
+	if err != nil {
+		return err
+	}
+
+	err = pmu.promoMySQLRepo.UpdatePromo(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (pmu *promoUseCase) DeletePromo(ctx context.Context, id int) (err error) {
+	_, err = pmu.promoMySQLRepo.GetDetailPromo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return pmu.promoMySQLRepo.DeletePromo(ctx, id)
+}
+
+// checkPromo returns why the promo does not apply to the request, empty when it applies
+func (pmu *promoUseCase) checkPromo(promo domain.ResponsePromo, request domain.RequestApplyPromo, userUsage int, now time.Time) string {
+	if !promo.Active {
+		return "promo is not active"
+	}
+	if promo.StartsAt != nil {
+		startsAt, err := time.ParseInLocation(domain.PromoLayout, *promo.StartsAt, pmu.location)
+		if err == nil && now.Before(startsAt) {
+			return "promo starts at " + *promo.StartsAt
+		}
+	}
+	if promo.EndsAt != nil {
+		endsAt, err := time.ParseInLocation(domain.PromoLayout, *promo.EndsAt, pmu.location)
+		if err == nil && !now.Before(endsAt) {
+			return "promo has ended"
+		}
+	}
+	if promo.MovieID != nil && *promo.MovieID != request.MovieID {
+		return "promo is not valid for this movie"
+	}
+	if promo.TheaterID != nil && *promo.TheaterID != request.TheaterID {
+		return "promo is not valid for this theater"
+	}
+	if promo.UsageLimit != nil && promo.UsedCount >= *promo.UsageLimit {
+		return "promo usage limit reached"
+	}
+	if promo.PerUserLimit != nil && userUsage >= *promo.PerUserLimit {
+		return "you already used this promo"
+	}
+	return ""
+}
+
+// promoDiscount computes the discount of a promo on the remaining amount, never more than the amount
+func promoDiscount(promo domain.ResponsePromo, amount int64) (discount int64) {
+	discount = promo.DiscountValue
+	if promo.DiscountType == domain.PromoTypePercentage {
+		discount = amount * promo.DiscountValue / 100
+		if promo.MaxDiscount != nil && discount > *promo.MaxDiscount {
+			discount = *promo.MaxDiscount
+		}
+	}
+
+	if discount > amount {
+		discount = amount
+	}
+	return discount
+}
+
+// ApplyPromo evaluates the codes in precedence order, the highest priority first then the oldest promo.
+// A promo not stackable only applies alone: when it comes first the other codes are rejected, otherwise
+// it is rejected. Each discount is taken from the amount left by the previous ones
+func (pmu *promoUseCase) ApplyPromo(ctx context.Context, request domain.RequestApplyPromo) (response domain.ResponseApplyPromo, err error) {
+	var codes []string
+	seen := map[string]bool{}
+	for _, code := range request.Codes {
+		code = normalizeCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	response.Promos = []domain.ResponsePromoResult{}
+	if len(codes) == 0 {
+		return response, nil
+	}
+
+	promos, err := pmu.promoMySQLRepo.GetPromoByCode(ctx, codes)
+	if err != nil {
+		return response, err
+	}
+	byCode := map[string]domain.ResponsePromo{}
+	promoIDs := make([]int, len(promos))
+	for idx, promo := range promos {
+		byCode[promo.Code] = promo
+		promoIDs[idx] = int(promo.ID)
+	}
+
+	usage, err := pmu.promoMySQLRepo.CountUserUsage(ctx, promoIDs, request.UserID)
+	if err != nil {
+		return response, err
+	}
+
+	now := time.Now().In(pmu.location)
+	results := map[string]*domain.ResponsePromoResult{}
+	var candidates []domain.ResponsePromo
+	for _, code := range codes {
+		result := &domain.ResponsePromoResult{Code: code}
+		results[code] = result
+
+		promo, ok := byCode[code]
+		if !ok {
+			result.Reason = "promo code is not exists"
+			continue
+		}
+		result.PromoID = promo.ID
+		result.PerUserLimit = promo.PerUserLimit
+
+		result.Reason = pmu.checkPromo(promo, request, usage[int(promo.ID)], now)
+		if result.Reason == "" {
+			candidates = append(candidates, promo)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	amount := request.Amount
+	for idx, promo := range candidates {
+		result := results[promo.Code]
+		if idx > 0 && (!promo.Stackable || !candidates[0].Stackable) {
+			result.Reason = "promo cannot be combined with " + candidates[0].Code
+			continue
+		}
+
+		result.Applied = true
+		result.Discount = promoDiscount(promo, amount)
+		amount -= result.Discount
+		response.Discount += result.Discount
+	}
+
+	for _, code := range codes {
+		response.Promos = append(response.Promos, *results[code])
+	}
+	return response, nil
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/recommendation/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for personalized recommendation REST API
+func RouterAPI(app *fiber.App, RecommendationUseCase domain.RecommendationUseCase) {
+	handlerRecommendation := &handler.RecommendationHandler{RecommendationUseCase: RecommendationUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	// Authenticated user API Route
+	me := app.Group(basePath+"/me", middleware.Auth)
+	me.Get("/recommendations", handlerRecommendation.GetRecommendation)
+}
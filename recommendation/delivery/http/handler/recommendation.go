@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+type RecommendationHandler struct {
+	RecommendationUseCase domain.RecommendationUseCase
+}
+
+func (rh *RecommendationHandler) GetRecommendation(c *fiber.Ctx) (err error) {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := rh.RecommendationUseCase.GetRecommendation(c.Context(), user, limit)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
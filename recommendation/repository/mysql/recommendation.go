@@ -0,0 +1,192 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlRecommendationRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLRecommendationRepository(Conn *sql.DB) domain.RecommendationMySQLRepo {
+	return &mysqlRecommendationRepository{Conn}
+}
+
+// interactionQuery reads reviews and watchlist/favorites as one signal list, rejected reviews are ignored
+const interactionQuery = `SELECT user_id, movie_id, 'review' as type, score FROM review WHERE status <> 'rejected' %s
+              UNION ALL
+              SELECT user_id, movie_id, type, 0 as score FROM user_movie %s`
+
+func (db *mysqlRecommendationRepository) queryInteraction(ctx context.Context, query string, args ...interface{}) (response []domain.Interaction, err error) {
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.Interaction
+		if err := rows.Scan(&i.UserID, &i.MovieID, &i.Type, &i.Score); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, rows.Err()
+}
+
+func (db *mysqlRecommendationRepository) GetAllInteraction(ctx context.Context) (response []domain.Interaction, err error) {
+	return db.queryInteraction(ctx, fmt.Sprintf(interactionQuery, "", ""))
+}
+
+func (db *mysqlRecommendationRepository) GetUserInteraction(ctx context.Context, userID int) (response []domain.Interaction, err error) {
+	return db.queryInteraction(ctx, fmt.Sprintf(interactionQuery, "AND user_id = ?", "WHERE user_id = ?"), userID, userID)
+}
+
+// ReplaceItemSimilarity swaps the whole item-item similarity table in one transaction
+func (db *mysqlRecommendationRepository) ReplaceItemSimilarity(ctx context.Context, scores map[int][]domain.SimilarScore) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM movie_item_similarity`)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO movie_item_similarity (movie_id, similar_movie_id, score, dtm_crt) VALUES (?, ?, ?, NOW())`)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer stmt.Close()
+
+	for movieID, neighbors := range scores {
+		for _, neighbor := range neighbors {
+			_, err = stmt.ExecContext(ctx, movieID, neighbor.SimilarMovieID, neighbor.Score)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlRecommendationRepository) GetItemNeighbor(ctx context.Context, movieIDs []int) (response map[int][]domain.SimilarScore, err error) {
+	response = map[int][]domain.SimilarScore{}
+	if len(movieIDs) == 0 {
+		return response, nil
+	}
+
+	args := []interface{}{}
+	for _, movieID := range movieIDs {
+		args = append(args, movieID)
+	}
+
+	query := `SELECT movie_id, similar_movie_id, score FROM movie_item_similarity
+              WHERE movie_id IN (?` + strings.Repeat(", ?", len(movieIDs)-1) + `)`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieID int
+		var i domain.SimilarScore
+		if err := rows.Scan(&movieID, &i.SimilarMovieID, &i.Score); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[movieID] = append(response[movieID], i)
+	}
+
+	return response, nil
+}
+
+// GetRecommendedMovie returns the published movies among movieIDs by id
+func (db *mysqlRecommendationRepository) GetRecommendedMovie(ctx context.Context, movieIDs []int) (response map[int]domain.ResponseRecommendation, err error) {
+	response = map[int]domain.ResponseRecommendation{}
+	if len(movieIDs) == 0 {
+		return response, nil
+	}
+
+	args := []interface{}{domain.MovieStatusPublished}
+	for _, movieID := range movieIDs {
+		args = append(args, movieID)
+	}
+
+	query := `SELECT id, title, rating, image FROM movie
+              WHERE status = ? AND id IN (?` + strings.Repeat(", ?", len(movieIDs)-1) + `)`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseRecommendation
+		if err := rows.Scan(&i.ID, &i.Title, &i.Rating, &i.Image); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[int(i.ID)] = i
+	}
+
+	return response, nil
+}
+
+// GetPopularMovie ranks the published movies by the number of reviews, watchlists and favorites
+func (db *mysqlRecommendationRepository) GetPopularMovie(ctx context.Context, excludeIDs []int, limit int) (response []domain.ResponseRecommendation, err error) {
+	query := `SELECT m.id, m.title, m.rating, m.image,
+                  COALESCE(r.review_count, 0) + (SELECT COUNT(*) FROM user_movie um WHERE um.movie_id = m.id) as popularity
+              FROM movie m
+              LEFT JOIN movie_rating r ON r.movie_id = m.id
+              WHERE m.status = ?`
+	args := []interface{}{domain.MovieStatusPublished}
+
+	if len(excludeIDs) > 0 {
+		query += ` AND m.id NOT IN (?` + strings.Repeat(", ?", len(excludeIDs)-1) + `)`
+		for _, movieID := range excludeIDs {
+			args = append(args, movieID)
+		}
+	}
+
+	query += ` ORDER BY popularity DESC, m.rating DESC, m.id LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i := domain.ResponseRecommendation{Reason: domain.RecommendationReasonPopular}
+		if err := rows.Scan(&i.ID, &i.Title, &i.Rating, &i.Image, &i.Score); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
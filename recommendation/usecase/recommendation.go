@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type recommendationUseCase struct {
+	recommendationMySQLRepo domain.RecommendationMySQLRepo
+}
+
+func NewRecommendationUsecase(RecommendationMySQLRepo domain.RecommendationMySQLRepo) domain.RecommendationUseCase {
+	return &recommendationUseCase{
+		recommendationMySQLRepo: RecommendationMySQLRepo,
+	}
+}
+
+// weight turns an interaction into an implicit preference, a user keeps the strongest signal per movie
+func weight(interaction domain.Interaction) float64 {
+	switch interaction.Type {
+	case domain.InteractionReview:
+		return viper.GetFloat64("recommendation.weight_review") * float64(interaction.Score) / domain.ReviewScoreMax
+	case domain.InteractionFavorite:
+		return viper.GetFloat64("recommendation.weight_favorite")
+	case domain.InteractionWatchlist:
+		return viper.GetFloat64("recommendation.weight_watchlist")
+	}
+	return 0
+}
+
+func preference(interactions []domain.Interaction) (response map[int]map[int]float64) {
+	response = map[int]map[int]float64{}
+	for _, interaction := range interactions {
+		if response[interaction.UserID] == nil {
+			response[interaction.UserID] = map[int]float64{}
+		}
+		if w := weight(interaction); w > response[interaction.UserID][interaction.MovieID] {
+			response[interaction.UserID][interaction.MovieID] = w
+		}
+	}
+	return response
+}
+
+type itemPair struct {
+	a, b int
+}
+
+// ComputeItemSimilarity computes the cosine similarity between movies over the user preferences
+// and stores the nearest neighbors of every movie
+func (rcu *recommendationUseCase) ComputeItemSimilarity(ctx context.Context) (err error) {
+	interactions, err := rcu.recommendationMySQLRepo.GetAllInteraction(ctx)
+	if err != nil {
+		return err
+	}
+
+	norm := map[int]float64{}
+	dot := map[itemPair]float64{}
+	common := map[itemPair]int{}
+	for _, items := range preference(interactions) {
+		movieIDs := make([]int, 0, len(items))
+		for movieID, w := range items {
+			movieIDs = append(movieIDs, movieID)
+			norm[movieID] += w * w
+		}
+		sort.Ints(movieIDs)
+
+		for i := 0; i < len(movieIDs); i++ {
+			for j := i + 1; j < len(movieIDs); j++ {
+				pair := itemPair{movieIDs[i], movieIDs[j]}
+				dot[pair] += items[pair.a] * items[pair.b]
+				common[pair]++
+			}
+		}
+	}
+
+	minCommon := viper.GetInt("recommendation.min_common_users")
+	scores := map[int][]domain.SimilarScore{}
+	for pair, value := range dot {
+		if common[pair] < minCommon || norm[pair.a] == 0 || norm[pair.b] == 0 {
+			continue
+		}
+		score := math.Round(value/math.Sqrt(norm[pair.a]*norm[pair.b])*10000) / 10000
+		if score <= 0 {
+			continue
+		}
+		scores[pair.a] = append(scores[pair.a], domain.SimilarScore{SimilarMovieID: pair.b, Score: score})
+		scores[pair.b] = append(scores[pair.b], domain.SimilarScore{SimilarMovieID: pair.a, Score: score})
+	}
+
+	neighbors := viper.GetInt("recommendation.neighbors")
+	for movieID := range scores {
+		sort.Slice(scores[movieID], func(a, b int) bool {
+			if scores[movieID][a].Score == scores[movieID][b].Score {
+				return scores[movieID][a].SimilarMovieID < scores[movieID][b].SimilarMovieID
+			}
+			return scores[movieID][a].Score > scores[movieID][b].Score
+		})
+		if neighbors > 0 && len(scores[movieID]) > neighbors {
+			scores[movieID] = scores[movieID][:neighbors]
+		}
+	}
+
+	err = rcu.recommendationMySQLRepo.ReplaceItemSimilarity(ctx, scores)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("item similarity computed for %d movies from %d interactions", len(scores), len(interactions))
+	return nil
+}
+
+// GetRecommendation scores the neighbors of the movies a user interacted with, movies the user
+// already rated or listed are excluded and popular movies fill the rest for cold-start users
+func (rcu *recommendationUseCase) GetRecommendation(ctx context.Context, user domain.AuthUser, limit int) (response []domain.ResponseRecommendation, err error) {
+	if limit <= 0 {
+		limit = viper.GetInt("recommendation.limit")
+	}
+
+	interactions, err := rcu.recommendationMySQLRepo.GetUserInteraction(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := preference(interactions)[user.ID]
+	excludeIDs := make([]int, 0, len(items))
+	for movieID := range items {
+		excludeIDs = append(excludeIDs, movieID)
+	}
+
+	neighbors, err := rcu.recommendationMySQLRepo.GetItemNeighbor(ctx, excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := map[int]float64{}
+	for movieID, w := range items {
+		for _, neighbor := range neighbors[movieID] {
+			if _, seen := items[neighbor.SimilarMovieID]; seen {
+				continue
+			}
+			candidates[neighbor.SimilarMovieID] += neighbor.Score * w
+		}
+	}
+
+	candidateIDs := make([]int, 0, len(candidates))
+	for movieID := range candidates {
+		candidateIDs = append(candidateIDs, movieID)
+	}
+	sort.Slice(candidateIDs, func(a, b int) bool {
+		if candidates[candidateIDs[a]] == candidates[candidateIDs[b]] {
+			return candidateIDs[a] < candidateIDs[b]
+		}
+		return candidates[candidateIDs[a]] > candidates[candidateIDs[b]]
+	})
+	if len(candidateIDs) > limit {
+		candidateIDs = candidateIDs[:limit]
+	}
+
+	movies, err := rcu.recommendationMySQLRepo.GetRecommendedMovie(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response = []domain.ResponseRecommendation{}
+	for _, movieID := range candidateIDs {
+		movie, ok := movies[movieID]
+		if !ok {
+			continue
+		}
+		movie.Score = math.Round(candidates[movieID]*10000) / 10000
+		movie.Reason = domain.RecommendationReasonSimilar
+		response = append(response, movie)
+		excludeIDs = append(excludeIDs, movieID)
+	}
+
+	if len(response) < limit {
+		popular, err := rcu.recommendationMySQLRepo.GetPopularMovie(ctx, excludeIDs, limit-len(response))
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, popular...)
+	}
+
+	return response, nil
+}
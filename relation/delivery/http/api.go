@@ -0,0 +1,22 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/relation/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for related movie REST API
+func RouterAPI(app *fiber.App, RelationUseCase domain.RelationUseCase) {
+	handlerRelation := &handler.RelationHandler{RelationUseCase: RelationUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	relation := app.Group(basePath)
+
+	relation.Get("/movie/:id/related", handlerRelation.GetRelatedMovie)
+	relation.Post("/movie/:id/related", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerRelation.PostRelation)
+	relation.Delete("/movie/:id/related/:related_id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerRelation.DeleteRelation)
+}
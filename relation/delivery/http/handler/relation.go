@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type RelationHandler struct {
+	RelationUseCase domain.RelationUseCase
+}
+
+func (rh *RelationHandler) GetRelatedMovie(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := rh.RelationUseCase.GetRelatedMovie(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (rh *RelationHandler) PostRelation(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestRelation
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = rh.RelationUseCase.PostRelation(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.SendStatus(fasthttp.StatusCreated)
+}
+
+func (rh *RelationHandler) DeleteRelation(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	relatedID, err := strconv.ParseInt(c.Params("related_id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input := domain.RequestRelation{
+		RelatedMovieID: int(relatedID),
+		Type:           c.Query("type"),
+	}
+	err = rh.RelationUseCase.DeleteRelation(c.Context(), int(id), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
@@ -0,0 +1,113 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlRelationRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLRelationRepository(Conn *sql.DB) domain.RelationMySQLRepo {
+	return &mysqlRelationRepository{Conn}
+}
+
+func (db *mysqlRelationRepository) PostRelation(ctx context.Context, relation domain.MovieRelation) (err error) {
+	query := `INSERT IGNORE INTO movie_relation (movie_id, related_movie_id, type, dtm_crt)
+              VALUES (?, ?, ?, NOW())`
+
+	_, err = db.Conn.ExecContext(ctx, query, relation.MovieID, relation.RelatedMovieID, relation.Type)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+func (db *mysqlRelationRepository) DeleteRelation(ctx context.Context, relation domain.MovieRelation) (err error) {
+	query := `DELETE FROM movie_relation WHERE movie_id = ? AND related_movie_id = ? AND type = ?`
+
+	res, err := db.Conn.ExecContext(ctx, query, relation.MovieID, relation.RelatedMovieID, relation.Type)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Not found")
+	}
+
+	return
+}
+
+// GetRelationByMovie returns the stored relations on both sides of the movie, RelatedMovie is always the other movie.
+// Unpublished related movies are left out
+func (db *mysqlRelationRepository) GetRelationByMovie(ctx context.Context, id int) (response []domain.MovieRelation, err error) {
+	query := `SELECT r.movie_id, r.related_movie_id, r.type, m.id, m.title, m.rating, m.image
+              FROM movie_relation r
+              JOIN movie m ON m.id = r.related_movie_id
+              WHERE r.movie_id = ? AND m.status = ?
+              UNION ALL
+              SELECT r.movie_id, r.related_movie_id, r.type, m.id, m.title, m.rating, m.image
+              FROM movie_relation r
+              JOIN movie m ON m.id = r.movie_id
+              WHERE r.related_movie_id = ? AND m.status = ?`
+
+	rows, err := db.Conn.QueryContext(ctx, query, id, domain.MovieStatusPublished, id, domain.MovieStatusPublished)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.MovieRelation
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.RelatedMovieID,
+			&i.Type,
+			&i.RelatedMovie.ID,
+			&i.RelatedMovie.Title,
+			&i.RelatedMovie.Rating,
+			&i.RelatedMovie.Image,
+		); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlRelationRepository) GetRelationTarget(ctx context.Context, id int, relationType string) (response []int, err error) {
+	query := `SELECT related_movie_id FROM movie_relation WHERE movie_id = ? AND type = ?`
+
+	rows, err := db.Conn.QueryContext(ctx, query, id, relationType)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var relatedID int
+		if err := rows.Scan(&relatedID); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, relatedID)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type relationUseCase struct {
+	relationMySQLRepo domain.RelationMySQLRepo
+	movieMySQLRepo    domain.MovieMySQLRepo
+}
+
+func NewRelationUsecase(RelationMySQLRepo domain.RelationMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.RelationUseCase {
+	return &relationUseCase{
+		relationMySQLRepo: RelationMySQLRepo,
+		movieMySQLRepo:    MovieMySQLRepo,
+	}
+}
+
+// normalize converts the request into the stored direction, prequel_of is kept as the inverse sequel_of
+func (rlu *relationUseCase) normalize(id int, request domain.RequestRelation) (relation domain.MovieRelation, err error) {
+	if id == request.RelatedMovieID {
+		return relation, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("movie cannot be related to itself")}
+	}
+
+	switch request.Type {
+	case domain.RelationSequelOf, domain.RelationRemakeOf, domain.RelationSpinOffOf:
+		relation = domain.MovieRelation{MovieID: id, RelatedMovieID: request.RelatedMovieID, Type: request.Type}
+	case domain.RelationPrequelOf:
+		relation = domain.MovieRelation{MovieID: request.RelatedMovieID, RelatedMovieID: id, Type: domain.RelationSequelOf}
+	default:
+		return relation, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("type must be sequel_of, prequel_of, remake_of or spin_off_of")}
+	}
+	return
+}
+
+// isSequelReachable walks the sequel_of chain from "from" and reports whether it reaches "to"
+func (rlu *relationUseCase) isSequelReachable(ctx context.Context, from int, to int) (bool, error) {
+	visited := map[int]bool{from: true}
+	queue := []int{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true, nil
+		}
+
+		targets, err := rlu.relationMySQLRepo.GetRelationTarget(ctx, current, domain.RelationSequelOf)
+		if err != nil {
+			return false, err
+		}
+		for _, target := range targets {
+			if !visited[target] {
+				visited[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	return false, nil
+}
+
+func (rlu *relationUseCase) PostRelation(ctx context.Context, id int, request domain.RequestRelation) (err error) {
+	relation, err := rlu.normalize(id, request)
+	if err != nil {
+		return err
+	}
+
+	for _, movieID := range []int{relation.MovieID, relation.RelatedMovieID} {
+		_, err = rlu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A sequel_of B is rejected when A is already reachable from B through sequel_of
+	if relation.Type == domain.RelationSequelOf {
+		cycle, err := rlu.isSequelReachable(ctx, relation.RelatedMovieID, relation.MovieID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("relation creates a cycle in the sequel chain")}
+		}
+	}
+
+	err = rlu.relationMySQLRepo.PostRelation(ctx, relation)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (rlu *relationUseCase) DeleteRelation(ctx context.Context, id int, request domain.RequestRelation) (err error) {
+	relation, err := rlu.normalize(id, request)
+	if err != nil {
+		return err
+	}
+
+	return rlu.relationMySQLRepo.DeleteRelation(ctx, relation)
+}
+
+// GetRelatedMovie groups the published movies related to a published movie by relation type
+func (rlu *relationUseCase) GetRelatedMovie(ctx context.Context, id int) (response domain.ResponseMovieRelation, err error) {
+	movie, err := rlu.movieMySQLRepo.GetDetailMovie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if movie.Status != domain.MovieStatusPublished {
+		return nil, errors.New("Not found")
+	}
+
+	relations, err := rlu.relationMySQLRepo.GetRelationByMovie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response = domain.ResponseMovieRelation{}
+	for _, relation := range relations {
+		relationType := relation.Type
+		if relation.MovieID != id {
+			relationType = domain.RelationInverse[relation.Type]
+		}
+		response[relationType] = append(response[relationType], relation.RelatedMovie)
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/relation/usecase"
+)
+
+type movieRepo struct {
+	domain.MovieMySQLRepo
+	movies map[int]domain.ResponseMovie
+}
+
+func (repo movieRepo) GetDetailMovie(ctx context.Context, id int) (domain.ResponseMovie, error) {
+	return repo.movies[id], nil
+}
+
+type relationRepo struct {
+	domain.RelationMySQLRepo
+	read bool
+}
+
+func (repo *relationRepo) GetRelationByMovie(ctx context.Context, id int) ([]domain.MovieRelation, error) {
+	repo.read = true
+	return nil, nil
+}
+
+func TestGetRelatedMovieOfDraft(t *testing.T) {
+	movies := movieRepo{movies: map[int]domain.ResponseMovie{
+		1: {ID: 1, Status: domain.MovieStatusDraft},
+		2: {ID: 2, Status: domain.MovieStatusPublished},
+	}}
+
+	tests := []struct {
+		name    string
+		id      int
+		wantErr string
+	}{
+		{name: "draft is not found", id: 1, wantErr: "Not found"},
+		{name: "published is listed", id: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relations := &relationRepo{}
+			_, err := usecase.NewRelationUsecase(relations, movies).GetRelatedMovie(context.Background(), tt.id)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || relations.read {
+					t.Fatalf("got %v with relations read %v, want %s", err, relations.read, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !relations.read {
+				t.Fatalf("got %v with relations read %v, want the relations", err, relations.read)
+			}
+		})
+	}
+}
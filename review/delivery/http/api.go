@@ -0,0 +1,31 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/review/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for user review REST API
+func RouterAPI(app *fiber.App, ReviewUseCase domain.ReviewUseCase) {
+	handlerReview := &handler.ReviewHandler{ReviewUseCase: ReviewUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	review := app.Group(basePath)
+
+	review.Get("/movie/:id/review", handlerReview.GetAllReview)
+	review.Get("/movie/:id/rating", handlerReview.GetRating)
+
+	// Authenticated user API Route
+	review.Post("/movie/:id/review", middleware.Auth, handlerReview.PostReview)
+	review.Patch("/movie/:id/review", middleware.Auth, handlerReview.UpdateReview)
+	review.Delete("/movie/:id/review", middleware.Auth, handlerReview.DeleteReview)
+	review.Post("/review/:id/report", middleware.Auth, handlerReview.ReportReview)
+
+	// Moderator API Route
+	review.Get("/review/moderation", middleware.Auth, middleware.Role(domain.RoleModerator, domain.RoleAdmin), handlerReview.GetModerationQueue)
+	review.Patch("/review/:id/moderation", middleware.Auth, middleware.Role(domain.RoleModerator, domain.RoleAdmin), handlerReview.ModerateReview)
+}
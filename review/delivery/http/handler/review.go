@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type ReviewHandler struct {
+	ReviewUseCase domain.ReviewUseCase
+}
+
+func (rh *ReviewHandler) GetAllReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input := domain.RequestParamReview{MovieID: int(id)}
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := rh.ReviewUseCase.GetAllReview(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (rh *ReviewHandler) GetRating(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := rh.ReviewUseCase.GetRating(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (rh *ReviewHandler) PostReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestReview
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = rh.ReviewUseCase.PostReview(c.Context(), int(id), user, input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.SendStatus(fasthttp.StatusCreated)
+}
+
+func (rh *ReviewHandler) UpdateReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestReview
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = rh.ReviewUseCase.UpdateReview(c.Context(), int(id), user, input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (rh *ReviewHandler) DeleteReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = rh.ReviewUseCase.DeleteReview(c.Context(), int(id), user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
+
+func (rh *ReviewHandler) GetModerationQueue(c *fiber.Ctx) (err error) {
+	var input domain.RequestParamReview
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	status := c.Query("status")
+	if status != "" {
+		input.Status = &status
+	}
+
+	res, err := rh.ReviewUseCase.GetModerationQueue(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (rh *ReviewHandler) ModerateReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestModerateReview
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = rh.ReviewUseCase.ModerateReview(c.Context(), int(id), user, input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (rh *ReviewHandler) ReportReview(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestReportReview
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	err = rh.ReviewUseCase.ReportReview(c.Context(), int(id), user, input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.SendStatus(fasthttp.StatusCreated)
+}
@@ -0,0 +1,459 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlReviewRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLReviewRepository(Conn *sql.DB) domain.ReviewMySQLRepo {
+	return &mysqlReviewRepository{Conn}
+}
+
+// applyRating adds delta reviews of the score into the movie rating aggregate
+func applyRating(ctx context.Context, tx *sql.Tx, movieID int, score int, delta int) (err error) {
+	if score < domain.ReviewScoreMin || score > domain.ReviewScoreMax {
+		return fmt.Errorf("score %d is out of range", score)
+	}
+
+	column := fmt.Sprintf("score_%d", score)
+	query := `INSERT INTO movie_rating (movie_id, review_count, score_sum, ` + column + `, dtm_upd)
+              VALUES (?, ?, ?, ?, NOW())
+              ON DUPLICATE KEY UPDATE
+                  review_count = review_count + VALUES(review_count),
+                  score_sum = score_sum + VALUES(score_sum),
+                  ` + column + ` = ` + column + ` + VALUES(` + column + `),
+                  dtm_upd = NOW()`
+
+	_, err = tx.ExecContext(ctx, query, movieID, delta, delta*score, delta)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+const reviewColumn = `id, movie_id, user_id, score, text, status, flag_reason, report_count, COALESCE(moderation_note, ''), dtm_crt, dtm_upd`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReview(row rowScanner) (response domain.ResponseReview, err error) {
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.MovieID,
+		&response.UserID,
+		&response.Score,
+		&response.Text,
+		&response.Status,
+		&response.FlagReason,
+		&response.ReportCount,
+		&response.ModerationNote,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// applyStatusChange keeps the aggregate in sync when a review leaves or enters the approved status
+func applyStatusChange(ctx context.Context, tx *sql.Tx, movieID int, oldStatus string, oldScore int, newStatus string, newScore int) (err error) {
+	if oldStatus == domain.ReviewStatusApproved {
+		err = applyRating(ctx, tx, movieID, oldScore, -1)
+		if err != nil {
+			return err
+		}
+	}
+	if newStatus == domain.ReviewStatusApproved {
+		err = applyRating(ctx, tx, movieID, newScore, 1)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// lockReview re-reads the status and score of the review under a row lock, the review read before the
+// transaction may have been moderated or reported since. moderated tells whether a moderator decided it
+func lockReview(ctx context.Context, tx *sql.Tx, id uint) (status string, score int, moderated bool, err error) {
+	err = tx.QueryRowContext(ctx, `SELECT status, score, moderated_by IS NOT NULL FROM review WHERE id = ? FOR UPDATE`, id).Scan(&status, &score, &moderated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status, score, moderated, errors.New("Not found")
+		}
+		log.Error(err)
+		return status, score, moderated, err
+	}
+	return status, score, moderated, nil
+}
+
+func (db *mysqlReviewRepository) PostReview(ctx context.Context, movieID int, userID int, request domain.RequestReview) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO review (movie_id, user_id, score, text, status, flag_reason, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`
+	_, err = tx.ExecContext(ctx, query, movieID, userID, request.Score, request.Text, request.Status, request.FlagReason)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = applyStatusChange(ctx, tx, movieID, "", 0, request.Status, request.Score)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlReviewRepository) UpdateReview(ctx context.Context, review domain.ResponseReview, request domain.RequestReview) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, score, moderated, err := lockReview(ctx, tx, review.ID)
+	if err != nil {
+		return err
+	}
+
+	// An edit never overrides a moderator, a rejected review stays rejected and a moderated one waits for a
+	// moderator again
+	if status == domain.ReviewStatusRejected {
+		request.Status = domain.ReviewStatusRejected
+	} else if moderated {
+		request.Status = domain.ReviewStatusPending
+	}
+
+	query := `UPDATE review SET score = ?, text = ?, status = ?, flag_reason = ?, dtm_upd = NOW() WHERE id = ?`
+	_, err = tx.ExecContext(ctx, query, request.Score, request.Text, request.Status, request.FlagReason, review.ID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = applyStatusChange(ctx, tx, int(review.MovieID), status, score, request.Status, request.Score)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlReviewRepository) DeleteReview(ctx context.Context, review domain.ResponseReview) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, score, _, err := lockReview(ctx, tx, review.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM review WHERE id = ?`, review.ID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = applyStatusChange(ctx, tx, int(review.MovieID), status, score, "", 0)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlReviewRepository) GetReviewByUser(ctx context.Context, movieID int, userID int) (response domain.ResponseReview, err error) {
+	query := `SELECT ` + reviewColumn + ` FROM review WHERE movie_id = ? AND user_id = ?`
+
+	response, err = scanReview(db.Conn.QueryRowContext(ctx, query, movieID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseReview{}, err
+		}
+		log.Error(err)
+		return domain.ResponseReview{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlReviewRepository) GetDetailReview(ctx context.Context, id int) (response domain.ResponseReview, err error) {
+	query := `SELECT ` + reviewColumn + ` FROM review WHERE id = ?`
+
+	response, err = scanReview(db.Conn.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseReview{}, err
+		}
+		log.Error(err)
+		return domain.ResponseReview{}, err
+	}
+
+	return response, nil
+}
+
+// UpdateReviewStatus applies a moderator decision, the report counter restarts from zero
+func (db *mysqlReviewRepository) UpdateReviewStatus(ctx context.Context, review domain.ResponseReview, moderatorID int, request domain.RequestModerateReview) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var score int
+	err = tx.QueryRowContext(ctx, `SELECT status, score FROM review WHERE id = ? FOR UPDATE`, review.ID).Scan(&status, &score)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	query := `UPDATE review
+              SET status = ?, report_count = 0, moderated_by = ?, moderation_note = ?, dtm_moderated = NOW()
+              WHERE id = ?`
+	_, err = tx.ExecContext(ctx, query, request.Status, moderatorID, request.Note, review.ID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = applyStatusChange(ctx, tx, int(review.MovieID), status, score, request.Status, score)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PostReviewReport records an abuse report, an approved review goes back to pending once it reaches the threshold
+func (db *mysqlReviewRepository) PostReviewReport(ctx context.Context, review domain.ResponseReview, userID int, request domain.RequestReportReview, threshold int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT IGNORE INTO review_report (review_id, user_id, reason, dtm_crt) VALUES (?, ?, ?, NOW())`, review.ID, userID, request.Reason)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Exists")
+	}
+
+	var status string
+	var score, reportCount int
+	err = tx.QueryRowContext(ctx, `SELECT status, score, report_count + 1 FROM review WHERE id = ? FOR UPDATE`, review.ID).Scan(&status, &score, &reportCount)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	newStatus := status
+	if status == domain.ReviewStatusApproved && threshold > 0 && reportCount >= threshold {
+		newStatus = domain.ReviewStatusPending
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE review SET report_count = ?, status = ? WHERE id = ?`, reportCount, newStatus, review.ID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if newStatus != status {
+		err = applyStatusChange(ctx, tx, int(review.MovieID), status, score, newStatus, score)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// filterReview builds the where clause shared by the review list and its count
+func filterReview(request domain.RequestParamReview) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.MovieID != 0 {
+		query += " AND movie_id = ?"
+		args = append(args, request.MovieID)
+	}
+
+	if request.Status != nil {
+		query += " AND status = ?"
+		args = append(args, *request.Status)
+	}
+
+	return query, args
+}
+
+func (db *mysqlReviewRepository) CountDataReview(ctx context.Context, request domain.RequestParamReview) (response domain.MetaData, err error) {
+	filter, args := filterReview(request)
+	query := "SELECT COUNT(id) as total FROM review" + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+// GetAllReview returns the newest reviews of a movie, the moderation queue shows the most reported and oldest first
+func (db *mysqlReviewRepository) GetAllReview(ctx context.Context, request domain.RequestParamReview) (response []domain.ResponseReview, err error) {
+	var limit, page int
+	filter, args := filterReview(request)
+	query := `SELECT ` + reviewColumn + ` FROM review` + filter
+
+	if request.MovieID != 0 {
+		query += " ORDER BY dtm_crt DESC, id DESC"
+	} else {
+		query += " ORDER BY report_count DESC, dtm_crt, id"
+	}
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanReview(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlReviewRepository) GetRatingAggregate(ctx context.Context, movieIDs []int) (response map[int]domain.RatingAggregate, err error) {
+	response = map[int]domain.RatingAggregate{}
+	if len(movieIDs) == 0 {
+		return response, nil
+	}
+
+	args := make([]interface{}, len(movieIDs))
+	for idx, movieID := range movieIDs {
+		args[idx] = movieID
+	}
+
+	query := `SELECT movie_id, review_count, score_sum, score_1, score_2, score_3, score_4, score_5, score_6, score_7, score_8, score_9, score_10
+              FROM movie_rating
+              WHERE movie_id IN (?` + strings.Repeat(", ?", len(movieIDs)-1) + `)`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.RatingAggregate
+		dest := []interface{}{&i.MovieID, &i.Count, &i.Sum}
+		for idx := range i.Histogram {
+			dest = append(dest, &i.Histogram[idx])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response[i.MovieID] = i
+	}
+
+	return response, nil
+}
+
+func (db *mysqlReviewRepository) GetGlobalMean(ctx context.Context) (mean float64, count uint, err error) {
+	query := `SELECT COALESCE(SUM(score_sum) / NULLIF(SUM(review_count), 0), 0), COALESCE(SUM(review_count), 0) FROM movie_rating`
+
+	err = db.Conn.QueryRowContext(ctx, query).Scan(&mean, &count)
+	if err != nil {
+		log.Error(err)
+		return 0, 0, err
+	}
+
+	return mean, count, nil
+}
@@ -0,0 +1,103 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper/sqltest"
+	"xsis-academy-test-service-movie/review/repository/mysql"
+)
+
+// lockedReview answers the locked re-read with the review as moderated since it was read
+func lockedReview(status string, moderated bool) func(query string, args []interface{}) sqltest.Result {
+	return func(query string, args []interface{}) sqltest.Result {
+		if strings.Contains(query, "FOR UPDATE") {
+			return sqltest.Result{Columns: []string{"status", "score", "moderated"}, Rows: [][]interface{}{{status, int64(3), moderated}}}
+		}
+		return sqltest.Result{}
+	}
+}
+
+func TestUpdateReviewUsesLockedStatus(t *testing.T) {
+	recorder := &sqltest.Recorder{Rows: lockedReview(domain.ReviewStatusPending, false)}
+	repo := mysql.NewMySQLReviewRepository(sqltest.Open(recorder))
+
+	review := domain.ResponseReview{ID: 4, MovieID: 7, Status: domain.ReviewStatusApproved, Score: 5}
+	err := repo.UpdateReview(context.Background(), review, domain.RequestReview{Score: 4, Status: domain.ReviewStatusApproved})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := recorder.Statements()
+	if len(statements) < 2 || statements[0].Query != "BEGIN" || !strings.Contains(statements[1].Query, "FOR UPDATE") {
+		t.Fatalf("got %+v, want the review locked first", statements)
+	}
+	// The pending review was never counted, only the new approved score is added
+	if len(recorder.Find("score_5")) != 0 || len(recorder.Find("score_4")) != 1 {
+		t.Fatalf("got %+v, want only score_4 added to the aggregate", statements)
+	}
+}
+
+func TestDeleteReviewUsesLockedStatus(t *testing.T) {
+	recorder := &sqltest.Recorder{Rows: lockedReview(domain.ReviewStatusPending, false)}
+	repo := mysql.NewMySQLReviewRepository(sqltest.Open(recorder))
+
+	review := domain.ResponseReview{ID: 4, MovieID: 7, Status: domain.ReviewStatusApproved, Score: 5}
+	err := repo.DeleteReview(context.Background(), review)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.Find("FOR UPDATE")) != 1 || len(recorder.Find("DELETE FROM review")) != 1 {
+		t.Fatalf("got %+v, want the review locked and deleted", recorder.Statements())
+	}
+	if len(recorder.Find("movie_rating")) != 0 {
+		t.Fatalf("got %+v, want the aggregate untouched by a pending review", recorder.Statements())
+	}
+}
+
+func TestDeleteReviewGone(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	repo := mysql.NewMySQLReviewRepository(sqltest.Open(recorder))
+
+	err := repo.DeleteReview(context.Background(), domain.ResponseReview{ID: 4, MovieID: 7, Status: domain.ReviewStatusApproved, Score: 5})
+	if err == nil || err.Error() != "Not found" {
+		t.Fatalf("got %v, want Not found", err)
+	}
+	if len(recorder.Find("DELETE FROM review")) != 0 {
+		t.Fatalf("got %+v, want no delete", recorder.Statements())
+	}
+}
+
+func TestUpdateReviewKeepsModeration(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		moderated bool
+		want      string
+	}{
+		{name: "auto approved review stays approved", status: domain.ReviewStatusApproved, want: domain.ReviewStatusApproved},
+		{name: "rejected review stays rejected", status: domain.ReviewStatusRejected, moderated: true, want: domain.ReviewStatusRejected},
+		{name: "review approved by a moderator waits again", status: domain.ReviewStatusApproved, moderated: true, want: domain.ReviewStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &sqltest.Recorder{Rows: lockedReview(tt.status, tt.moderated)}
+			repo := mysql.NewMySQLReviewRepository(sqltest.Open(recorder))
+
+			// The edit is clean and would be approved right away with moderation.auto_approve
+			review := domain.ResponseReview{ID: 4, MovieID: 7, Status: tt.status, Score: 3}
+			err := repo.UpdateReview(context.Background(), review, domain.RequestReview{Score: 4, Status: domain.ReviewStatusApproved})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			updates := recorder.Find("UPDATE review SET")
+			if len(updates) != 1 || updates[0].Count(tt.want) != 1 {
+				t.Fatalf("got %+v, want the review stored as %s", updates, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type reviewUseCase struct {
+	reviewMySQLRepo domain.ReviewMySQLRepo
+	movieMySQLRepo  domain.MovieMySQLRepo
+	contentFilter   *helper.ContentFilter
+}
+
+func NewReviewUsecase(ReviewMySQLRepo domain.ReviewMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo, ContentFilter *helper.ContentFilter) domain.ReviewUseCase {
+	return &reviewUseCase{
+		reviewMySQLRepo: ReviewMySQLRepo,
+		movieMySQLRepo:  MovieMySQLRepo,
+		contentFilter:   ContentFilter,
+	}
+}
+
+// validate checks the review and decides its status, flagged reviews always wait for a moderator
+func (rvu *reviewUseCase) validate(request *domain.RequestReview) (err error) {
+	request.Text = strings.TrimSpace(request.Text)
+	if request.Score < domain.ReviewScoreMin || request.Score > domain.ReviewScoreMax {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("score must be between 1 and 10")}
+	}
+
+	request.Status = domain.ReviewStatusPending
+	request.FlagReason = ""
+	if reason, flagged := rvu.contentFilter.Check(request.Text); flagged {
+		request.FlagReason = reason
+	} else if viper.GetBool("moderation.auto_approve") {
+		request.Status = domain.ReviewStatusApproved
+	}
+	return
+}
+
+func (rvu *reviewUseCase) PostReview(ctx context.Context, movieID int, user domain.AuthUser, request domain.RequestReview) (err error) {
+	err = rvu.validate(&request)
+	if err != nil {
+		return err
+	}
+
+	movie, err := rvu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		return err
+	}
+	// Unpublished movies are hidden as if they did not exist
+	if movie.Status != domain.MovieStatusPublished && !domain.CanManageMovie(&user) {
+		return errors.New("Not found")
+	}
+
+	_, err = rvu.reviewMySQLRepo.GetReviewByUser(ctx, movieID, user.ID)
+	if err == nil {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("user already reviewed this movie")}
+	}
+	if err.Error() != "Not found" {
+		return err
+	}
+
+	err = rvu.reviewMySQLRepo.PostReview(ctx, movieID, user.ID, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (rvu *reviewUseCase) UpdateReview(ctx context.Context, movieID int, user domain.AuthUser, request domain.RequestReview) (err error) {
+	err = rvu.validate(&request)
+	if err != nil {
+		return err
+	}
+
+	review, err := rvu.reviewMySQLRepo.GetReviewByUser(ctx, movieID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	err = rvu.reviewMySQLRepo.UpdateReview(ctx, review, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (rvu *reviewUseCase) DeleteReview(ctx context.Context, movieID int, user domain.AuthUser) (err error) {
+	review, err := rvu.reviewMySQLRepo.GetReviewByUser(ctx, movieID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return rvu.reviewMySQLRepo.DeleteReview(ctx, review)
+}
+
+// GetAllReview lists the public reviews of a movie, only approved reviews are shown
+func (rvu *reviewUseCase) GetAllReview(ctx context.Context, request domain.RequestParamReview) (response domain.ResponseGetAllReview, err error) {
+	status := domain.ReviewStatusApproved
+	request.Status = &status
+	response, err = rvu.getAll(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	for idx := range response.Data {
+		response.Data[idx].FlagReason = ""
+		response.Data[idx].ReportCount = 0
+		response.Data[idx].ModerationNote = ""
+	}
+	return
+}
+
+// GetModerationQueue lists reviews of every movie for moderators, pending reviews by default
+func (rvu *reviewUseCase) GetModerationQueue(ctx context.Context, request domain.RequestParamReview) (response domain.ResponseGetAllReview, err error) {
+	if request.Status == nil {
+		status := domain.ReviewStatusPending
+		request.Status = &status
+	}
+	return rvu.getAll(ctx, request)
+}
+
+func (rvu *reviewUseCase) getAll(ctx context.Context, request domain.RequestParamReview) (response domain.ResponseGetAllReview, err error) {
+	resCount, err := rvu.reviewMySQLRepo.CountDataReview(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllReview{}, err
+	}
+
+	resReview, err := rvu.reviewMySQLRepo.GetAllReview(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllReview{
+		MetaData: resCount,
+		Data:     resReview,
+	}
+	return
+}
+
+func (rvu *reviewUseCase) GetRating(ctx context.Context, movieID int) (response domain.ResponseRatingAggregate, err error) {
+	_, err = rvu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		return response, err
+	}
+
+	aggregates, err := rvu.reviewMySQLRepo.GetRatingAggregate(ctx, []int{movieID})
+	if err != nil {
+		return response, err
+	}
+
+	globalMean, globalCount, err := rvu.reviewMySQLRepo.GetGlobalMean(ctx)
+	if err != nil {
+		return response, err
+	}
+	if globalCount == 0 {
+		globalMean = viper.GetFloat64("review.prior_mean")
+	}
+
+	return helper.RatingSummary(aggregates[movieID], globalMean, viper.GetFloat64("review.bayesian_min_votes")), nil
+}
+
+func (rvu *reviewUseCase) ModerateReview(ctx context.Context, id int, moderator domain.AuthUser, request domain.RequestModerateReview) (err error) {
+	if request.Status != domain.ReviewStatusApproved && request.Status != domain.ReviewStatusRejected {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("status must be approved or rejected")}
+	}
+
+	review, err := rvu.reviewMySQLRepo.GetDetailReview(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = rvu.reviewMySQLRepo.UpdateReviewStatus(ctx, review, moderator.ID, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (rvu *reviewUseCase) ReportReview(ctx context.Context, id int, user domain.AuthUser, request domain.RequestReportReview) (err error) {
+	request.Reason = strings.TrimSpace(request.Reason)
+	if request.Reason == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("reason is required")}
+	}
+
+	review, err := rvu.reviewMySQLRepo.GetDetailReview(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if int(review.UserID) == user.ID {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("cannot report own review")}
+	}
+
+	err = rvu.reviewMySQLRepo.PostReviewReport(ctx, review, user.ID, request, viper.GetInt("moderation.report_threshold"))
+	if err != nil {
+		if err.Error() == "Exists" {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("user already reported this review")}
+		}
+		log.Error(err)
+		return err
+	}
+	return
+}
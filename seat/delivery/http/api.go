@@ -0,0 +1,26 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/seat/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for seat layout and seat hold REST API
+func RouterAPI(app *fiber.App, SeatUseCase domain.SeatUseCase) {
+	handlerSeat := &handler.SeatHandler{SeatUseCase: SeatUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	seat := app.Group(basePath)
+
+	seat.Get("/auditorium/:id/seat", handlerSeat.GetSeatLayout)
+	seat.Put("/auditorium/:id/seat", middleware.Auth, middleware.Role(domain.RoleAdmin), handlerSeat.SetSeatLayout)
+	seat.Get("/showtime/:id/seat", middleware.OptionalAuth, handlerSeat.GetShowtimeSeat)
+
+	// Authenticated user API Route
+	seat.Post("/showtime/:id/hold", middleware.Auth, handlerSeat.HoldSeat)
+	seat.Delete("/showtime/:id/hold", middleware.Auth, handlerSeat.ReleaseSeat)
+}
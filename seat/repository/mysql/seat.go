@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlSeatRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLSeatRepository(Conn *sql.DB) domain.SeatMySQLRepo {
+	return &mysqlSeatRepository{Conn}
+}
+
+// SetSeatLayout replaces the seats of the auditorium and keeps its capacity in sync
+func (db *mysqlSeatRepository) SetSeatLayout(ctx context.Context, auditoriumID int, request domain.RequestSeatLayout) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM seat WHERE auditorium_id = ?`, auditoriumID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	var capacity int
+	for _, row := range request.Rows {
+		for number := 1; number <= row.Count; number++ {
+			_, err = tx.ExecContext(ctx, `INSERT INTO seat (auditorium_id, row_label, number, type) VALUES (?, ?, ?, ?)`, auditoriumID, row.Row, number, row.Type)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+		capacity += row.Count
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE auditorium SET capacity = ?, dtm_upd = NOW() WHERE id = ?`, capacity, auditoriumID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlSeatRepository) GetSeatByAuditorium(ctx context.Context, auditoriumID int) (response []domain.ResponseSeat, err error) {
+	query := `SELECT id, auditorium_id, row_label, number, type FROM seat
+              WHERE auditorium_id = ?
+              ORDER BY LENGTH(row_label), row_label, number`
+
+	rows, err := db.Conn.QueryContext(ctx, query, auditoriumID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseSeat
+		if err := rows.Scan(&i.ID, &i.AuditoriumID, &i.Row, &i.Number, &i.Type); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlSeatRepository) CountBookedSeatByAuditorium(ctx context.Context, auditoriumID int) (total int, err error) {
+	query := `SELECT COUNT(*) FROM booking_seat bs
+              JOIN seat s ON s.id = bs.seat_id
+              WHERE s.auditorium_id = ?`
+
+	err = db.Conn.QueryRowContext(ctx, query, auditoriumID).Scan(&total)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetBookedSeat returns the seats of the showtime reserved by a pending or paid booking
+func (db *mysqlSeatRepository) GetBookedSeat(ctx context.Context, showtimeID int) (response []int, err error) {
+	rows, err := db.Conn.QueryContext(ctx, `SELECT seat_id FROM booking_seat WHERE showtime_id = ? AND active = 1`, showtimeID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seatID int
+		if err := rows.Scan(&seatID); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, seatID)
+	}
+
+	return response, nil
+}
+
+// GetShowtimeAuditorium returns the auditorium of the showtime, open is false once the showtime started
+func (db *mysqlSeatRepository) GetShowtimeAuditorium(ctx context.Context, showtimeID int) (auditoriumID int, open bool, err error) {
+	err = db.Conn.QueryRowContext(ctx, `SELECT auditorium_id, start_time > NOW() FROM showtime WHERE id = ?`, showtimeID).Scan(&auditoriumID, &open)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return 0, false, err
+		}
+		log.Error(err)
+		return 0, false, err
+	}
+
+	return auditoriumID, open, nil
+}
@@ -0,0 +1,166 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/labstack/gommon/log"
+)
+
+type redisSeatRepository struct {
+	Client *goredis.Client
+}
+
+func NewRedisSeatRepository(Client *goredis.Client) domain.SeatRedisRepo {
+	return &redisSeatRepository{Client}
+}
+
+// Keys of a showtime share a hash tag so the scripts stay on one slot
+func showtimeTag(showtimeID int) string {
+	return "{showtime:" + strconv.Itoa(showtimeID) + "}"
+}
+
+func seatKey(showtimeID int, seatID int) string {
+	return "seat:hold:" + showtimeTag(showtimeID) + ":" + strconv.Itoa(seatID)
+}
+
+func userKey(showtimeID int, owner string) string {
+	return "seat:user:" + showtimeTag(showtimeID) + ":" + owner
+}
+
+// holdScript holds every seat or none, KEYS are the seat keys followed by the user key,
+// ARGV are the owner, the TTL in milliseconds and the seat IDs, it returns the 1-based index
+// of the first seat held by someone else or 0 on success
+var holdScript = goredis.NewScript(`
+local n = #KEYS - 1
+for i = 1, n do
+    local holder = redis.call('GET', KEYS[i])
+    if holder and holder ~= ARGV[1] then
+        return i
+    end
+end
+for i = 1, n do
+    redis.call('SET', KEYS[i], ARGV[1], 'PX', ARGV[2])
+    redis.call('SADD', KEYS[n + 1], ARGV[i + 2])
+end
+redis.call('PEXPIRE', KEYS[n + 1], ARGV[2])
+return 0
+`)
+
+// releaseScript deletes the seats still held by the owner, KEYS are the seat keys followed by the user key
+var releaseScript = goredis.NewScript(`
+local n = #KEYS - 1
+for i = 1, n do
+    if redis.call('GET', KEYS[i]) == ARGV[1] then
+        redis.call('DEL', KEYS[i])
+    end
+    redis.call('SREM', KEYS[n + 1], ARGV[i + 1])
+end
+return 0
+`)
+
+func (db *redisSeatRepository) HoldSeat(ctx context.Context, showtimeID int, owner string, seatIDs []int, ttl time.Duration) (conflictSeatID int, err error) {
+	keys := make([]string, 0, len(seatIDs)+1)
+	args := []interface{}{owner, ttl.Milliseconds()}
+	for _, seatID := range seatIDs {
+		keys = append(keys, seatKey(showtimeID, seatID))
+		args = append(args, seatID)
+	}
+	keys = append(keys, userKey(showtimeID, owner))
+
+	index, err := holdScript.Run(ctx, db.Client, keys, args...).Int()
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	if index > 0 {
+		return seatIDs[index-1], nil
+	}
+	return 0, nil
+}
+
+// CheckHold returns the first seat not held by the owner, 0 when the owner holds every seat
+func (db *redisSeatRepository) CheckHold(ctx context.Context, showtimeID int, owner string, seatIDs []int) (missingSeatID int, err error) {
+	holders, err := db.GetHolder(ctx, showtimeID, seatIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, seatID := range seatIDs {
+		if holders[seatID] != owner {
+			return seatID, nil
+		}
+	}
+	return 0, nil
+}
+
+func (db *redisSeatRepository) ReleaseSeat(ctx context.Context, showtimeID int, owner string, seatIDs []int) (err error) {
+	if len(seatIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(seatIDs)+1)
+	args := []interface{}{owner}
+	for _, seatID := range seatIDs {
+		keys = append(keys, seatKey(showtimeID, seatID))
+		args = append(args, seatID)
+	}
+	keys = append(keys, userKey(showtimeID, owner))
+
+	err = releaseScript.Run(ctx, db.Client, keys, args...).Err()
+	if err != nil && err != goredis.Nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *redisSeatRepository) GetUserHold(ctx context.Context, showtimeID int, owner string) (response []int, err error) {
+	members, err := db.Client.SMembers(ctx, userKey(showtimeID, owner)).Result()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	for _, member := range members {
+		seatID, err := strconv.Atoi(member)
+		if err != nil {
+			continue
+		}
+		response = append(response, seatID)
+	}
+
+	return response, nil
+}
+
+// GetHolder returns the owner of every held seat, seats not held are left out
+func (db *redisSeatRepository) GetHolder(ctx context.Context, showtimeID int, seatIDs []int) (response map[int]string, err error) {
+	response = map[int]string{}
+	if len(seatIDs) == 0 {
+		return response, nil
+	}
+
+	keys := make([]string, len(seatIDs))
+	for idx, seatID := range seatIDs {
+		keys[idx] = seatKey(showtimeID, seatID)
+	}
+
+	values, err := db.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	for idx, value := range values {
+		if owner, ok := value.(string); ok {
+			response[seatIDs[idx]] = owner
+		}
+	}
+
+	return response, nil
+}
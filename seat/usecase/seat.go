@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+// maxSeatPerRow keeps a layout request from creating an unreasonable number of seats
+const maxSeatPerRow = 100
+
+type seatUseCase struct {
+	seatMySQLRepo     domain.SeatMySQLRepo
+	seatRedisRepo     domain.SeatRedisRepo
+	theaterMySQLRepo  domain.TheaterMySQLRepo
+	showtimeMySQLRepo domain.ShowtimeMySQLRepo
+}
+
+func NewSeatUsecase(SeatMySQLRepo domain.SeatMySQLRepo, SeatRedisRepo domain.SeatRedisRepo, TheaterMySQLRepo domain.TheaterMySQLRepo, ShowtimeMySQLRepo domain.ShowtimeMySQLRepo) domain.SeatUseCase {
+	return &seatUseCase{
+		seatMySQLRepo:     SeatMySQLRepo,
+		seatRedisRepo:     SeatRedisRepo,
+		theaterMySQLRepo:  TheaterMySQLRepo,
+		showtimeMySQLRepo: ShowtimeMySQLRepo,
+	}
+}
+
+func (stu *seatUseCase) validateLayout(request *domain.RequestSeatLayout) (err error) {
+	if len(request.Rows) == 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("rows is required")}
+	}
+
+	seen := map[string]bool{}
+	for idx := range request.Rows {
+		row := &request.Rows[idx]
+		row.Row = strings.ToUpper(strings.TrimSpace(row.Row))
+		if row.Row == "" || len(row.Row) > 5 {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("row must be 1 to 5 characters")}
+		}
+		if seen[row.Row] {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("row must be unique")}
+		}
+		seen[row.Row] = true
+
+		if row.Count < 1 || row.Count > maxSeatPerRow {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("count must be between 1 and %d", maxSeatPerRow)}
+		}
+
+		if row.Type == "" {
+			row.Type = domain.SeatTypeRegular
+		}
+		if row.Type != domain.SeatTypeRegular && row.Type != domain.SeatTypeVIP && row.Type != domain.SeatTypeWheelchair {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("type must be regular, vip or wheelchair")}
+		}
+	}
+	return
+}
+
+// SetSeatLayout replaces the seats of an auditorium, a layout with booked seats cannot be replaced
+func (stu *seatUseCase) SetSeatLayout(ctx context.Context, auditoriumID int, request domain.RequestSeatLayout) (err error) {
+	_, err = stu.theaterMySQLRepo.GetDetailAuditorium(ctx, auditoriumID)
+	if err != nil {
+		return err
+	}
+
+	err = stu.validateLayout(&request)
+	if err != nil {
+		return err
+	}
+
+	booked, err := stu.seatMySQLRepo.CountBookedSeatByAuditorium(ctx, auditoriumID)
+	if err != nil {
+		return err
+	}
+	if booked > 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("seat layout already has bookings")}
+	}
+
+	err = stu.seatMySQLRepo.SetSeatLayout(ctx, auditoriumID, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (stu *seatUseCase) GetSeatLayout(ctx context.Context, auditoriumID int) (response []domain.ResponseSeat, err error) {
+	_, err = stu.theaterMySQLRepo.GetDetailAuditorium(ctx, auditoriumID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err = stu.seatMySQLRepo.GetSeatByAuditorium(ctx, auditoriumID)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		response = []domain.ResponseSeat{}
+	}
+	return response, nil
+}
+
+// GetShowtimeSeat returns the seat map of a showtime with the price and status of every seat
+func (stu *seatUseCase) GetShowtimeSeat(ctx context.Context, showtimeID int, user *domain.AuthUser) (response []domain.ResponseShowtimeSeat, err error) {
+	showtime, err := stu.showtimeMySQLRepo.GetDetailShowtime(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := time.Parse(domain.ShowtimeLayout, showtime.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	seats, err := stu.seatMySQLRepo.GetSeatByAuditorium(ctx, int(showtime.AuditoriumID))
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := stu.seatMySQLRepo.GetBookedSeat(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	bookedSet := map[int]bool{}
+	for _, seatID := range booked {
+		bookedSet[seatID] = true
+	}
+
+	seatIDs := make([]int, len(seats))
+	for idx, seat := range seats {
+		seatIDs[idx] = int(seat.ID)
+	}
+	holders, err := stu.seatRedisRepo.GetHolder(ctx, showtimeID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner string
+	if user != nil {
+		owner = strconv.Itoa(user.ID)
+	}
+
+	response = make([]domain.ResponseShowtimeSeat, len(seats))
+	for idx, seat := range seats {
+		response[idx] = domain.ResponseShowtimeSeat{
+			ID:     seat.ID,
+			Row:    seat.Row,
+			Number: seat.Number,
+			Type:   seat.Type,
+			Price:  helper.SeatPrice(showtime.BasePrice, seat.Type, startTime),
+			Status: domain.SeatStatusAvailable,
+		}
+
+		if bookedSet[int(seat.ID)] {
+			response[idx].Status = domain.SeatStatusBooked
+		} else if holder, ok := holders[int(seat.ID)]; ok {
+			response[idx].Status = domain.SeatStatusHeld
+			response[idx].HeldByMe = holder == owner
+		}
+	}
+	return response, nil
+}
+
+// HoldSeat holds every requested seat for the user or none of them, holds expire after seat.hold_ttl seconds
+func (stu *seatUseCase) HoldSeat(ctx context.Context, showtimeID int, user domain.AuthUser, request domain.RequestSeatHold) (response domain.ResponseSeatHold, err error) {
+	if len(request.SeatIDs) == 0 {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("seat_ids is required")}
+	}
+
+	auditoriumID, open, err := stu.seatMySQLRepo.GetShowtimeAuditorium(ctx, showtimeID)
+	if err != nil {
+		return response, err
+	}
+	if !open {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("showtime already started")}
+	}
+
+	seats, err := stu.seatMySQLRepo.GetSeatByAuditorium(ctx, auditoriumID)
+	if err != nil {
+		return response, err
+	}
+	seatSet := map[int]bool{}
+	for _, seat := range seats {
+		seatSet[int(seat.ID)] = true
+	}
+
+	booked, err := stu.seatMySQLRepo.GetBookedSeat(ctx, showtimeID)
+	if err != nil {
+		return response, err
+	}
+	bookedSet := map[int]bool{}
+	for _, seatID := range booked {
+		bookedSet[seatID] = true
+	}
+
+	owner := strconv.Itoa(user.ID)
+	current, err := stu.seatRedisRepo.GetUserHold(ctx, showtimeID, owner)
+	if err != nil {
+		return response, err
+	}
+	total := map[int]bool{}
+	for _, seatID := range current {
+		total[seatID] = true
+	}
+
+	seen := map[int]bool{}
+	for _, seatID := range request.SeatIDs {
+		if seen[seatID] {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("seat_ids must be unique")}
+		}
+		seen[seatID] = true
+		total[seatID] = true
+
+		if !seatSet[seatID] {
+			return response, constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: fmt.Errorf("seat %d is not exists in this auditorium", seatID)}
+		}
+		if bookedSet[seatID] {
+			return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: fmt.Errorf("seat %d is already booked", seatID)}
+		}
+	}
+
+	if maxHold := viper.GetInt("seat.max_hold"); maxHold > 0 && len(total) > maxHold {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("cannot hold more than %d seats", maxHold)}
+	}
+
+	ttl := time.Duration(viper.GetInt("seat.hold_ttl")) * time.Second
+	conflict, err := stu.seatRedisRepo.HoldSeat(ctx, showtimeID, owner, request.SeatIDs, ttl)
+	if err != nil {
+		return response, err
+	}
+	if conflict != 0 {
+		return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: fmt.Errorf("seat %d is held by another user", conflict)}
+	}
+
+	response = domain.ResponseSeatHold{
+		ShowtimeID: showtimeID,
+		SeatIDs:    request.SeatIDs,
+		ExpiresAt:  time.Now().Add(ttl).Format("2006-01-02 15:04:05"),
+	}
+	return response, nil
+}
+
+// ReleaseSeat releases every seat the user holds on the showtime
+func (stu *seatUseCase) ReleaseSeat(ctx context.Context, showtimeID int, user domain.AuthUser) (err error) {
+	owner := strconv.Itoa(user.ID)
+	seatIDs, err := stu.seatRedisRepo.GetUserHold(ctx, showtimeID, owner)
+	if err != nil {
+		return err
+	}
+
+	return stu.seatRedisRepo.ReleaseSeat(ctx, showtimeID, owner, seatIDs)
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/showtime/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for showtime REST API
+func RouterAPI(app *fiber.App, ShowtimeUseCase domain.ShowtimeUseCase) {
+	handlerShowtime := &handler.ShowtimeHandler{ShowtimeUseCase: ShowtimeUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	showtime := app.Group(basePath)
+	auditShowtime := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		user, _ := middleware.GetAuthUser(c)
+		return ShowtimeUseCase.GetDetailShowtime(c.Context(), id, &user)
+	})
+
+	showtime.Get("/showtime", middleware.OptionalAuth, handlerShowtime.GetAllShowtime)
+	showtime.Post("/showtime", middleware.Auth, middleware.Role(domain.RoleAdmin), auditShowtime, handlerShowtime.PostShowtime)
+	showtime.Get("/showtime/nearby", middleware.OptionalAuth, handlerShowtime.GetNearbyShowtime)
+	showtime.Get("/showtime/:id", middleware.OptionalAuth, handlerShowtime.GetDetailShowtime)
+	showtime.Patch("/showtime/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), auditShowtime, handlerShowtime.UpdateShowtime)
+	showtime.Delete("/showtime/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), auditShowtime, handlerShowtime.DeleteShowtime)
+	showtime.Get("/movie/:id/showtime", middleware.OptionalAuth, handlerShowtime.GetMovieShowtime)
+	showtime.Get("/movie/:id/showtime/nearby", middleware.OptionalAuth, handlerShowtime.GetNearbyShowtime)
+	showtime.Get("/theater/:id/showtime", middleware.OptionalAuth, handlerShowtime.GetTheaterShowtime)
+}
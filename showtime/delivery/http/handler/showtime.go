@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type ShowtimeHandler struct {
+	ShowtimeUseCase domain.ShowtimeUseCase
+}
+
+// parseParam reads paging, date and the optional movie and theater filters from the query
+func parseParam(c *fiber.Ctx) (input domain.RequestParamShowtime, err error) {
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return input, err
+	}
+
+	date := c.Query("date")
+	if date != "" {
+		input.Date = &date
+	}
+
+	movieID := c.Query("movie_id")
+	if movieID != "" {
+		movieIDInt, err := strconv.Atoi(movieID)
+		if err != nil {
+			return input, err
+		}
+		input.MovieID = &movieIDInt
+	}
+
+	theaterID := c.Query("theater_id")
+	if theaterID != "" {
+		theaterIDInt, err := strconv.Atoi(theaterID)
+		if err != nil {
+			return input, err
+		}
+		input.TheaterID = &theaterIDInt
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+	return input, nil
+}
+
+func (sh *ShowtimeHandler) GetAllShowtime(c *fiber.Ctx) error {
+	input, err := parseParam(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := sh.ShowtimeUseCase.GetAllShowtime(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (sh *ShowtimeHandler) GetMovieShowtime(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input, err := parseParam(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+	input.MovieID = &id
+
+	res, err := sh.ShowtimeUseCase.GetAllShowtime(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (sh *ShowtimeHandler) GetTheaterShowtime(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input, err := parseParam(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+	input.TheaterID = &id
+
+	res, err := sh.ShowtimeUseCase.GetAllShowtime(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+// GetNearbyShowtime lists showtimes in the theaters near the point, GET /movie/:id/showtime/nearby
+// narrows them to the movie
+func (sh *ShowtimeHandler) GetNearbyShowtime(c *fiber.Ctx) error {
+	var input domain.RequestParamNearbyShowtime
+	var err error
+	input.RequestParamNearby, err = helper.ParseNearby(c)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	if c.Params("id") != "" {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			log.Error(err)
+			return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+		}
+		input.MovieID = &id
+	}
+
+	date := c.Query("date")
+	if date != "" {
+		input.Date = &date
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+
+	res, err := sh.ShowtimeUseCase.GetNearbyShowtime(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (sh *ShowtimeHandler) PostShowtime(c *fiber.Ctx) (err error) {
+	var input domain.RequestShowtime
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+	id, err := sh.ShowtimeUseCase.PostShowtime(c.Context(), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+func (sh *ShowtimeHandler) GetDetailShowtime(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var user *domain.AuthUser
+	if authUser, ok := middleware.GetAuthUser(c); ok {
+		user = &authUser
+	}
+
+	res, err := sh.ShowtimeUseCase.GetDetailShowtime(c.Context(), int(id), user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (sh *ShowtimeHandler) UpdateShowtime(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestShowtime
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	if user, ok := middleware.GetAuthUser(c); ok {
+		input.User = &user
+	}
+	err = sh.ShowtimeUseCase.UpdateShowtime(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (sh *ShowtimeHandler) DeleteShowtime(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = sh.ShowtimeUseCase.DeleteShowtime(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
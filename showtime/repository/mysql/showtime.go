@@ -0,0 +1,349 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlShowtimeRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLShowtimeRepository(Conn *sql.DB) domain.ShowtimeMySQLRepo {
+	return &mysqlShowtimeRepository{Conn}
+}
+
+const showtimeQuery = `SELECT s.id, s.movie_id, m.title, s.auditorium_id, a.name, a.theater_id, t.name,
+                  s.start_time, s.end_time, s.base_price, s.dtm_crt, s.dtm_upd, m.status
+              FROM showtime s
+              JOIN movie m ON m.id = s.movie_id
+              JOIN auditorium a ON a.id = s.auditorium_id
+              JOIN theater t ON t.id = a.theater_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShowtime(row rowScanner) (response domain.ResponseShowtime, err error) {
+	var startTime, endTime, dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.MovieID,
+		&response.MovieTitle,
+		&response.AuditoriumID,
+		&response.AuditoriumName,
+		&response.TheaterID,
+		&response.TheaterName,
+		&startTime,
+		&endTime,
+		&response.BasePrice,
+		&dtmCrt,
+		&dtmUpd,
+		&response.MovieStatus,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	response.StartTime = startTime.Format(domain.ShowtimeLayout)
+	response.EndTime = endTime.Format(domain.ShowtimeLayout)
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// checkOverlap locks the auditorium so concurrent schedules are serialized, then looks for
+// another showtime of the auditorium intersecting the requested time range
+func checkOverlap(ctx context.Context, tx *sql.Tx, id int, request domain.RequestShowtime) (err error) {
+	var auditoriumID int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM auditorium WHERE id = ? FOR UPDATE`, request.AuditoriumID).Scan(&auditoriumID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Not found")
+		}
+		log.Error(err)
+		return err
+	}
+
+	query := `SELECT COUNT(*) FROM showtime
+              WHERE auditorium_id = ? AND id <> ? AND start_time < ? AND end_time > ?`
+
+	var count int
+	err = tx.QueryRowContext(ctx, query, request.AuditoriumID, id, request.EndTime, request.StartTime).Scan(&count)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if count > 0 {
+		return errors.New("Overlap")
+	}
+	return nil
+}
+
+// checkBooking locks the showtime so no booking is made meanwhile, then looks for its bookings in one
+// of the statuses, any booking when none is given
+func checkBooking(ctx context.Context, tx *sql.Tx, id int, statuses ...string) (err error) {
+	var showtimeID int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM showtime WHERE id = ? FOR UPDATE`, id).Scan(&showtimeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Not found")
+		}
+		log.Error(err)
+		return err
+	}
+
+	query := `SELECT COUNT(*) FROM booking WHERE showtime_id = ?`
+	args := []interface{}{id}
+	if len(statuses) > 0 {
+		query += ` AND status IN (?` + strings.Repeat(", ?", len(statuses)-1) + `)`
+		for _, status := range statuses {
+			args = append(args, status)
+		}
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if count > 0 {
+		return errors.New("Booked")
+	}
+	return nil
+}
+
+func (db *mysqlShowtimeRepository) PostShowtime(ctx context.Context, request domain.RequestShowtime) (id int, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	err = checkOverlap(ctx, tx, 0, request)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO showtime (movie_id, auditorium_id, start_time, end_time, base_price, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := tx.ExecContext(ctx, query, request.MovieID, request.AuditoriumID, request.StartTime, request.EndTime, request.BasePrice)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), tx.Commit()
+}
+
+// filterShowtime builds the where clause shared by the showtime list and its count
+func filterShowtime(request domain.RequestParamShowtime) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.MovieID != nil {
+		query += " AND s.movie_id = ?"
+		args = append(args, *request.MovieID)
+	}
+
+	if request.TheaterID != nil {
+		query += " AND a.theater_id = ?"
+		args = append(args, *request.TheaterID)
+	}
+
+	if len(request.TheaterIDs) > 0 {
+		query += " AND a.theater_id IN (?" + strings.Repeat(", ?", len(request.TheaterIDs)-1) + ")"
+		for _, theaterID := range request.TheaterIDs {
+			args = append(args, theaterID)
+		}
+	}
+
+	if request.Date != nil {
+		query += " AND s.start_time >= ? AND s.start_time < ? + INTERVAL 1 DAY"
+		args = append(args, *request.Date, *request.Date)
+	}
+
+	if request.From != nil {
+		query += " AND s.start_time >= ?"
+		args = append(args, *request.From)
+	}
+
+	if request.To != nil {
+		query += " AND s.start_time < ?"
+		args = append(args, *request.To)
+	}
+
+	if request.Upcoming {
+		query += " AND s.start_time > NOW()"
+	}
+
+	if request.Published {
+		query += " AND m.status = ?"
+		args = append(args, domain.MovieStatusPublished)
+	}
+
+	return query, args
+}
+
+func (db *mysqlShowtimeRepository) CountDataShowtime(ctx context.Context, request domain.RequestParamShowtime) (response domain.MetaData, err error) {
+	filter, args := filterShowtime(request)
+	query := `SELECT COUNT(s.id) as total FROM showtime s
+              JOIN movie m ON m.id = s.movie_id
+              JOIN auditorium a ON a.id = s.auditorium_id` + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlShowtimeRepository) GetAllShowtime(ctx context.Context, request domain.RequestParamShowtime) (response []domain.ResponseShowtime, err error) {
+	filter, args := filterShowtime(request)
+	query := showtimeQuery + filter + ` ORDER BY s.start_time, t.name, a.name`
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanShowtime(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlShowtimeRepository) GetDetailShowtime(ctx context.Context, id int) (response domain.ResponseShowtime, err error) {
+	response, err = scanShowtime(db.Conn.QueryRowContext(ctx, showtimeQuery+` WHERE s.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseShowtime{}, err
+		}
+		log.Error(err)
+		return domain.ResponseShowtime{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlShowtimeRepository) UpdateShowtime(ctx context.Context, id int, request domain.RequestShowtime) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A held or confirmed booking was sold for the current movie, auditorium and time
+	err = checkBooking(ctx, tx, id, domain.BookingStatusPending, domain.BookingStatusPaid)
+	if err != nil {
+		return err
+	}
+
+	err = checkOverlap(ctx, tx, id, request)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE showtime
+              SET movie_id = ?, auditorium_id = ?, start_time = ?, end_time = ?, base_price = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = tx.ExecContext(ctx, query, request.MovieID, request.AuditoriumID, request.StartTime, request.EndTime, request.BasePrice, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteShowtime deletes a showtime without bookings, the cancelled and refunded ones are kept as the sales history
+func (db *mysqlShowtimeRepository) DeleteShowtime(ctx context.Context, id int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = checkBooking(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM showtime WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type showtimeUseCase struct {
+	showtimeMySQLRepo domain.ShowtimeMySQLRepo
+	movieMySQLRepo    domain.MovieMySQLRepo
+	theaterMySQLRepo  domain.TheaterMySQLRepo
+}
+
+func NewShowtimeUsecase(ShowtimeMySQLRepo domain.ShowtimeMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo, TheaterMySQLRepo domain.TheaterMySQLRepo) domain.ShowtimeUseCase {
+	return &showtimeUseCase{
+		showtimeMySQLRepo: ShowtimeMySQLRepo,
+		movieMySQLRepo:    MovieMySQLRepo,
+		theaterMySQLRepo:  TheaterMySQLRepo,
+	}
+}
+
+// validate checks the movie and auditorium and computes the end time from the movie runtime
+// plus the cleaning time between two shows. Only users managing movies schedule unpublished movies
+func (shu *showtimeUseCase) validate(ctx context.Context, request *domain.RequestShowtime) (err error) {
+	startTime, err := time.Parse(domain.ShowtimeLayout, request.StartTime)
+	if err != nil {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("start_time must be formatted as YYYY-MM-DD HH:MM:SS")}
+	}
+
+	if request.BasePrice < 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("base_price must not be negative")}
+	}
+
+	movie, err := shu.movieMySQLRepo.GetDetailMovie(ctx, request.MovieID)
+	if err != nil {
+		if err.Error() == "Not found" {
+			return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+		}
+		return err
+	}
+	if movie.Status != domain.MovieStatusPublished && !domain.CanManageMovie(request.User) {
+		return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+	}
+	if movie.Runtime <= 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("movie runtime is not set")}
+	}
+
+	_, err = shu.theaterMySQLRepo.GetDetailAuditorium(ctx, request.AuditoriumID)
+	if err != nil {
+		if err.Error() == "Not found" {
+			return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("auditorium is not exists")}
+		}
+		return err
+	}
+
+	duration := time.Duration(movie.Runtime+viper.GetInt("showtime.cleaning_time")) * time.Minute
+	request.StartTime = startTime.Format(domain.ShowtimeLayout)
+	request.EndTime = startTime.Add(duration).Format(domain.ShowtimeLayout)
+	return
+}
+
+func overlapError(err error) error {
+	if err.Error() == "Overlap" {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("auditorium already has a showtime at this time")}
+	}
+	return err
+}
+
+func (shu *showtimeUseCase) PostShowtime(ctx context.Context, request domain.RequestShowtime) (id int, err error) {
+	err = shu.validate(ctx, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = shu.showtimeMySQLRepo.PostShowtime(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return 0, overlapError(err)
+	}
+	return
+}
+
+func (shu *showtimeUseCase) GetAllShowtime(ctx context.Context, request domain.RequestParamShowtime) (response domain.ResponseGetAllShowtime, err error) {
+	if request.Date != nil {
+		if _, err := time.Parse("2006-01-02", *request.Date); err != nil {
+			return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("date must be formatted as YYYY-MM-DD")}
+		}
+	}
+
+	// Showtimes of unpublished movies are hidden like the movies themselves
+	if !domain.CanManageMovie(request.User) {
+		request.Published = true
+	}
+
+	resCount, err := shu.showtimeMySQLRepo.CountDataShowtime(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllShowtime{}, err
+	}
+
+	resShowtime, err := shu.showtimeMySQLRepo.GetAllShowtime(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllShowtime{
+		MetaData: resCount,
+		Data:     resShowtime,
+	}
+	return
+}
+
+func (shu *showtimeUseCase) GetDetailShowtime(ctx context.Context, id int, user *domain.AuthUser) (response domain.ResponseShowtime, err error) {
+	response, err = shu.showtimeMySQLRepo.GetDetailShowtime(ctx, id)
+	if err != nil {
+		return response, err
+	}
+	if response.MovieStatus != domain.MovieStatusPublished && !domain.CanManageMovie(user) {
+		return domain.ResponseShowtime{}, errors.New("Not found")
+	}
+	return response, nil
+}
+
+// GetNearbyShowtime returns the theaters near the point closest first with their upcoming
+// showtimes of the date, today when no date is given. Theaters without showtimes are left out
+func (shu *showtimeUseCase) GetNearbyShowtime(ctx context.Context, request domain.RequestParamNearbyShowtime) (response []domain.ResponseTheaterShowtime, err error) {
+	err = helper.ValidateNearby(request.RequestParamNearby)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Date == nil {
+		location, err := time.LoadLocation(constant.TimeLocation)
+		if err != nil {
+			return nil, err
+		}
+		today := time.Now().In(location).Format("2006-01-02")
+		request.Date = &today
+	} else if _, err := time.Parse("2006-01-02", *request.Date); err != nil {
+		return nil, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("date must be formatted as YYYY-MM-DD")}
+	}
+
+	// Theaters are searched without a limit, the limit applies to theaters having showtimes
+	nearby := request.RequestParamNearby
+	nearby.Limit = 0
+	theaters, err := shu.theaterMySQLRepo.GetNearbyTheater(ctx, nearby)
+	if err != nil {
+		return nil, err
+	}
+
+	response = []domain.ResponseTheaterShowtime{}
+	if len(theaters) == 0 {
+		return response, nil
+	}
+
+	theaterIDs := make([]int, len(theaters))
+	for idx, theater := range theaters {
+		theaterIDs[idx] = int(theater.ID)
+	}
+
+	showtimes, err := shu.showtimeMySQLRepo.GetAllShowtime(ctx, domain.RequestParamShowtime{
+		MovieID:    request.MovieID,
+		TheaterIDs: theaterIDs,
+		Date:       request.Date,
+		Upcoming:   true,
+		Published:  !domain.CanManageMovie(request.User),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byTheater := map[uint][]domain.ResponseShowtime{}
+	for _, showtime := range showtimes {
+		byTheater[showtime.TheaterID] = append(byTheater[showtime.TheaterID], showtime)
+	}
+
+	for _, theater := range theaters {
+		if len(byTheater[theater.ID]) == 0 {
+			continue
+		}
+		response = append(response, domain.ResponseTheaterShowtime{
+			Theater:   theater,
+			Showtimes: byTheater[theater.ID],
+		})
+		if request.Limit > 0 && len(response) == request.Limit {
+			break
+		}
+	}
+	return response, nil
+}
+
+func (shu *showtimeUseCase) UpdateShowtime(ctx context.Context, id int, request domain.RequestShowtime) (err error) {
+	_, err = shu.showtimeMySQLRepo.GetDetailShowtime(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = shu.validate(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	err = shu.showtimeMySQLRepo.UpdateShowtime(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		if err.Error() == "Booked" {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("showtime has held or confirmed bookings")}
+		}
+		return overlapError(err)
+	}
+	return
+}
+
+func (shu *showtimeUseCase) DeleteShowtime(ctx context.Context, id int) (err error) {
+	_, err = shu.showtimeMySQLRepo.GetDetailShowtime(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = shu.showtimeMySQLRepo.DeleteShowtime(ctx, id)
+	if err != nil {
+		if err.Error() == "Booked" {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("showtime has bookings")}
+		}
+		return err
+	}
+	return
+}
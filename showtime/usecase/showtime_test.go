@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/showtime/usecase"
+)
+
+// showtimeRepo returns one showtime of a draft movie and keeps the filter it was asked for
+type showtimeRepo struct {
+	domain.ShowtimeMySQLRepo
+	request domain.RequestParamShowtime
+}
+
+func (repo *showtimeRepo) CountDataShowtime(ctx context.Context, request domain.RequestParamShowtime) (domain.MetaData, error) {
+	repo.request = request
+	return domain.MetaData{TotalData: 1}, nil
+}
+
+func (repo *showtimeRepo) GetAllShowtime(ctx context.Context, request domain.RequestParamShowtime) ([]domain.ResponseShowtime, error) {
+	return nil, nil
+}
+
+func (repo *showtimeRepo) GetDetailShowtime(ctx context.Context, id int) (domain.ResponseShowtime, error) {
+	return domain.ResponseShowtime{ID: uint(id), MovieID: 7, MovieStatus: domain.MovieStatusDraft}, nil
+}
+
+type movieRepo struct {
+	domain.MovieMySQLRepo
+}
+
+func (repo *movieRepo) GetDetailMovie(ctx context.Context, id int) (domain.ResponseMovie, error) {
+	return domain.ResponseMovie{ID: uint(id), Status: domain.MovieStatusDraft, Runtime: 120}, nil
+}
+
+func TestShowtimeOfUnpublishedMovie(t *testing.T) {
+	editor := &domain.AuthUser{ID: 1, Role: domain.RoleEditor}
+	tests := []struct {
+		name      string
+		user      *domain.AuthUser
+		published bool
+		found     bool
+	}{
+		{name: "anonymous user", published: true},
+		{name: "customer", user: &domain.AuthUser{ID: 2, Role: domain.RoleUser}, published: true},
+		{name: "editor", user: editor, found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &showtimeRepo{}
+			showtimeUseCase := usecase.NewShowtimeUsecase(repo, &movieRepo{}, nil)
+
+			if _, err := showtimeUseCase.GetAllShowtime(context.Background(), domain.RequestParamShowtime{User: tt.user}); err != nil {
+				t.Fatal(err)
+			}
+			if repo.request.Published != tt.published {
+				t.Fatalf("got published filter %v, want %v", repo.request.Published, tt.published)
+			}
+
+			_, err := showtimeUseCase.GetDetailShowtime(context.Background(), 4, tt.user)
+			if found := err == nil; found != tt.found {
+				t.Fatalf("got %v, want found %v", err, tt.found)
+			}
+		})
+	}
+}
+
+func TestPostShowtimeOfUnpublishedMovie(t *testing.T) {
+	showtimeUseCase := usecase.NewShowtimeUsecase(&showtimeRepo{}, &movieRepo{}, nil)
+
+	_, err := showtimeUseCase.PostShowtime(context.Background(), domain.RequestShowtime{MovieID: 7, AuditoriumID: 1, StartTime: "2026-10-20 19:00:00"})
+	if resultError, ok := err.(constant.ResultError); !ok || resultError.Code != constant.StatusBadRequestNotExists {
+		t.Fatalf("got %v, want the draft movie rejected", err)
+	}
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/similar/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for similar movie REST API
+func RouterAPI(app *fiber.App, SimilarUseCase domain.SimilarUseCase) {
+	handlerSimilar := &handler.SimilarHandler{SimilarUseCase: SimilarUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	similar := app.Group(basePath)
+	similar.Get("/movie/:id/similar", handlerSimilar.GetSimilarMovie)
+}
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlSimilarRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLSimilarRepository(Conn *sql.DB) domain.SimilarMySQLRepo {
+	return &mysqlSimilarRepository{Conn}
+}
+
+// GetSimilarSource loads description, tags, credited people and collections of every movie
+func (db *mysqlSimilarRepository) GetSimilarSource(ctx context.Context) (response []domain.SimilarSource, err error) {
+	rows, err := db.Conn.QueryContext(ctx, `SELECT id, description, status FROM movie ORDER BY id`)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := map[int]int{}
+	for rows.Next() {
+		var i domain.SimilarSource
+		if err := rows.Scan(&i.MovieID, &i.Description, &i.Status); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		index[i.MovieID] = len(response)
+		response = append(response, i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = db.loadLink(ctx, `SELECT movie_id, tag_id FROM movie_tag`, func(movieID int, linkID int) {
+		if idx, ok := index[movieID]; ok {
+			response[idx].TagIDs = append(response[idx].TagIDs, linkID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.loadLink(ctx, `SELECT DISTINCT movie_id, person_id FROM movie_credit`, func(movieID int, linkID int) {
+		if idx, ok := index[movieID]; ok {
+			response[idx].PersonIDs = append(response[idx].PersonIDs, linkID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.loadLink(ctx, `SELECT movie_id, collection_id FROM collection_movie`, func(movieID int, linkID int) {
+		if idx, ok := index[movieID]; ok {
+			response[idx].CollectionIDs = append(response[idx].CollectionIDs, linkID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlSimilarRepository) loadLink(ctx context.Context, query string, fn func(movieID int, linkID int)) (err error) {
+	rows, err := db.Conn.QueryContext(ctx, query)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieID, linkID int
+		if err := rows.Scan(&movieID, &linkID); err != nil {
+			log.Error(err)
+			return err
+		}
+		fn(movieID, linkID)
+	}
+	return rows.Err()
+}
+
+func (db *mysqlSimilarRepository) ReplaceSimilar(ctx context.Context, movieID int, scores []domain.SimilarScore) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM movie_similar WHERE movie_id = ?`, movieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	for _, score := range scores {
+		_, err = tx.ExecContext(ctx, `INSERT INTO movie_similar (movie_id, similar_movie_id, score, dtm_crt) VALUES (?, ?, ?, NOW())`, movieID, score.SimilarMovieID, score.Score)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSimilarMovie returns the published similar movies by score
+func (db *mysqlSimilarRepository) GetSimilarMovie(ctx context.Context, id int, limit int) (response []domain.ResponseSimilarMovie, err error) {
+	query := `SELECT m.id, m.title, m.rating, m.image, s.score
+              FROM movie_similar s
+              JOIN movie m ON m.id = s.similar_movie_id
+              WHERE s.movie_id = ? AND m.status = ?
+              ORDER BY s.score DESC, m.id
+              LIMIT ?`
+
+	rows, err := db.Conn.QueryContext(ctx, query, id, domain.MovieStatusPublished, limit)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseSimilarMovie
+		if err := rows.Scan(&i.ID, &i.Title, &i.Rating, &i.Image, &i.Score); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type similarUseCase struct {
+	similarMySQLRepo domain.SimilarMySQLRepo
+	movieMySQLRepo   domain.MovieMySQLRepo
+}
+
+func NewSimilarUsecase(SimilarMySQLRepo domain.SimilarMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.SimilarUseCase {
+	return &similarUseCase{
+		similarMySQLRepo: SimilarMySQLRepo,
+		movieMySQLRepo:   MovieMySQLRepo,
+	}
+}
+
+// stopWords are common english and indonesian words ignored in the description
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "his": true, "her": true, "their": true,
+	"this": true, "that": true, "from": true, "into": true, "who": true, "are": true, "was": true,
+	"but": true, "not": true, "has": true, "have": true, "they": true, "them": true, "when": true,
+	"yang": true, "dan": true, "untuk": true, "dengan": true, "dari": true, "pada": true, "ini": true,
+	"itu": true, "dia": true, "mereka": true, "akan": true, "tidak": true, "dalam": true, "oleh": true,
+}
+
+func tokenize(text string) (tokens []string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// tfidf builds an L2 normalized TF-IDF vector for every description
+func tfidf(sources []domain.SimilarSource) (vectors []map[string]float64) {
+	docFreq := map[string]int{}
+	termFreq := make([]map[string]float64, len(sources))
+	for idx, source := range sources {
+		termFreq[idx] = map[string]float64{}
+		tokens := tokenize(source.Description)
+		for _, token := range tokens {
+			termFreq[idx][token]++
+		}
+		for token := range termFreq[idx] {
+			termFreq[idx][token] /= float64(len(tokens))
+			docFreq[token]++
+		}
+	}
+
+	total := float64(len(sources))
+	vectors = make([]map[string]float64, len(sources))
+	for idx, tf := range termFreq {
+		var norm float64
+		for token, freq := range tf {
+			tf[token] = freq * math.Log(1+total/float64(docFreq[token]))
+			norm += tf[token] * tf[token]
+		}
+		norm = math.Sqrt(norm)
+		for token := range tf {
+			if norm == 0 {
+				delete(tf, token)
+				continue
+			}
+			tf[token] /= norm
+		}
+		vectors[idx] = tf
+	}
+	return vectors
+}
+
+func cosine(a, b map[string]float64) (score float64) {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for token, weight := range a {
+		score += weight * b[token]
+	}
+	return score
+}
+
+// jaccard is the overlap of two id sets
+func jaccard(a, b []int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := map[int]bool{}
+	for _, id := range a {
+		set[id] = true
+	}
+	var intersect int
+	for _, id := range b {
+		if set[id] {
+			intersect++
+		}
+	}
+	return float64(intersect) / float64(len(set)+len(b)-intersect)
+}
+
+func shareAny(a, b []int) float64 {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// ComputeSimilar scores every movie pair and stores the top similar movies of each movie. A score weights the
+// description cosine, the genre overlap, the people credited in both and the shared collections. Only published
+// movies are candidates, so unpublished ones never take a place of the top similar movies
+func (smu *similarUseCase) ComputeSimilar(ctx context.Context) (err error) {
+	sources, err := smu.similarMySQLRepo.GetSimilarSource(ctx)
+	if err != nil {
+		return err
+	}
+
+	weightDescription := viper.GetFloat64("similar.weight_description")
+	weightTag := viper.GetFloat64("similar.weight_tag")
+	weightCredit := viper.GetFloat64("similar.weight_credit")
+	weightCollection := viper.GetFloat64("similar.weight_collection")
+	weightTotal := weightDescription + weightTag + weightCredit + weightCollection
+	if weightTotal <= 0 {
+		log.Warn("similar weights are all zero, skip computing similar movie")
+		return nil
+	}
+	topK := viper.GetInt("similar.top_k")
+
+	vectors := tfidf(sources)
+	for i, source := range sources {
+		var scores []domain.SimilarScore
+		for j, other := range sources {
+			if i == j || other.Status != domain.MovieStatusPublished {
+				continue
+			}
+
+			score := (weightDescription*cosine(vectors[i], vectors[j]) +
+				weightTag*jaccard(source.TagIDs, other.TagIDs) +
+				weightCredit*jaccard(source.PersonIDs, other.PersonIDs) +
+				weightCollection*shareAny(source.CollectionIDs, other.CollectionIDs)) / weightTotal
+			if score <= 0 {
+				continue
+			}
+			scores = append(scores, domain.SimilarScore{SimilarMovieID: other.MovieID, Score: math.Round(score*10000) / 10000})
+		}
+
+		sort.SliceStable(scores, func(a, b int) bool {
+			return scores[a].Score > scores[b].Score
+		})
+		if topK > 0 && len(scores) > topK {
+			scores = scores[:topK]
+		}
+
+		err = smu.similarMySQLRepo.ReplaceSimilar(ctx, source.MovieID, scores)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	log.Infof("similar movie computed for %d movies", len(sources))
+	return nil
+}
+
+// GetSimilarMovie returns the published movies similar to a published movie
+func (smu *similarUseCase) GetSimilarMovie(ctx context.Context, id int, limit int) (response []domain.ResponseSimilarMovie, err error) {
+	movie, err := smu.movieMySQLRepo.GetDetailMovie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if movie.Status != domain.MovieStatusPublished {
+		return nil, errors.New("Not found")
+	}
+
+	topK := viper.GetInt("similar.top_k")
+	if limit <= 0 || (topK > 0 && limit > topK) {
+		limit = topK
+	}
+	if limit <= 0 {
+		limit = viper.GetInt("database.default_limit_query")
+	}
+
+	response, err = smu.similarMySQLRepo.GetSimilarMovie(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		response = []domain.ResponseSimilarMovie{}
+	}
+	return response, nil
+}
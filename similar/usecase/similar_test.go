@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/similar/usecase"
+
+	"github.com/spf13/viper"
+)
+
+type movieRepo struct {
+	domain.MovieMySQLRepo
+	movies map[int]domain.ResponseMovie
+}
+
+func (repo movieRepo) GetDetailMovie(ctx context.Context, id int) (domain.ResponseMovie, error) {
+	return repo.movies[id], nil
+}
+
+type similarRepo struct {
+	domain.SimilarMySQLRepo
+	read    bool
+	sources []domain.SimilarSource
+	scores  map[int][]domain.SimilarScore
+}
+
+func (repo *similarRepo) GetSimilarSource(ctx context.Context) ([]domain.SimilarSource, error) {
+	return repo.sources, nil
+}
+
+func (repo *similarRepo) ReplaceSimilar(ctx context.Context, movieID int, scores []domain.SimilarScore) error {
+	repo.scores[movieID] = scores
+	return nil
+}
+
+func (repo *similarRepo) GetSimilarMovie(ctx context.Context, id int, limit int) ([]domain.ResponseSimilarMovie, error) {
+	repo.read = true
+	return nil, nil
+}
+
+func TestGetSimilarMovieOfDraft(t *testing.T) {
+	movies := movieRepo{movies: map[int]domain.ResponseMovie{
+		1: {ID: 1, Status: domain.MovieStatusDraft},
+		2: {ID: 2, Status: domain.MovieStatusPublished},
+	}}
+
+	similar := &similarRepo{}
+	_, err := usecase.NewSimilarUsecase(similar, movies).GetSimilarMovie(context.Background(), 1, 5)
+	if err == nil || err.Error() != "Not found" || similar.read {
+		t.Fatalf("got %v with similar read %v, want Not found", err, similar.read)
+	}
+
+	response, err := usecase.NewSimilarUsecase(similar, movies).GetSimilarMovie(context.Background(), 2, 5)
+	if err != nil || response == nil || !similar.read {
+		t.Fatalf("got %v, %v, want the similar movies", response, err)
+	}
+}
+
+func TestComputeSimilarRanking(t *testing.T) {
+	viper.Set("similar.weight_description", 0.3)
+	viper.Set("similar.weight_tag", 0.3)
+	viper.Set("similar.weight_credit", 0.2)
+	viper.Set("similar.weight_collection", 0.2)
+	t.Cleanup(viper.Reset)
+
+	// The descriptions share no word, tags 10 and 11 are genres and 100 and 101 are people
+	similar := &similarRepo{scores: map[int][]domain.SimilarScore{}, sources: []domain.SimilarSource{
+		{MovieID: 1, Status: domain.MovieStatusPublished, Description: "heist", TagIDs: []int{10, 11}, PersonIDs: []int{100, 101}},
+		{MovieID: 2, Status: domain.MovieStatusPublished, Description: "wedding", TagIDs: []int{10, 11}},
+		{MovieID: 3, Status: domain.MovieStatusPublished, Description: "volcano", PersonIDs: []int{100, 101}},
+		{MovieID: 4, Status: domain.MovieStatusPublished, Description: "spaceship", TagIDs: []int{12}, PersonIDs: []int{102}},
+		{MovieID: 5, Status: domain.MovieStatusPublished, Description: "pirate", TagIDs: []int{10}, PersonIDs: []int{100}},
+		{MovieID: 6, Status: domain.MovieStatusPublished, Description: "dragon", TagIDs: []int{10, 11}, PersonIDs: []int{100, 101}},
+	}}
+
+	err := usecase.NewSimilarUsecase(similar, nil).ComputeSimilar(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same genres and people first, then the genres alone, half of both and the people alone
+	want := []domain.SimilarScore{{SimilarMovieID: 6, Score: 0.5}, {SimilarMovieID: 2, Score: 0.3}, {SimilarMovieID: 5, Score: 0.25}, {SimilarMovieID: 3, Score: 0.2}}
+	if got := similar.scores[1]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestComputeSimilarLeavesOutUnpublished(t *testing.T) {
+	viper.Set("similar.weight_tag", 1)
+	viper.Set("similar.top_k", 1)
+	t.Cleanup(viper.Reset)
+
+	// The draft shares every genre with movie 1 but must not take the only place
+	similar := &similarRepo{scores: map[int][]domain.SimilarScore{}, sources: []domain.SimilarSource{
+		{MovieID: 1, Status: domain.MovieStatusPublished, TagIDs: []int{10, 11}},
+		{MovieID: 2, Status: domain.MovieStatusDraft, TagIDs: []int{10, 11}},
+		{MovieID: 3, Status: domain.MovieStatusArchived, TagIDs: []int{10, 11}},
+		{MovieID: 4, Status: domain.MovieStatusPublished, TagIDs: []int{10}},
+	}}
+
+	err := usecase.NewSimilarUsecase(similar, nil).ComputeSimilar(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []domain.SimilarScore{{SimilarMovieID: 4, Score: 0.5}}
+	if got := similar.scores[1]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got := similar.scores[2]; !reflect.DeepEqual(got, []domain.SimilarScore{{SimilarMovieID: 1, Score: 1}}) {
+		t.Fatalf("got %+v, want the draft to get published movies only", got)
+	}
+}
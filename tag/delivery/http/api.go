@@ -0,0 +1,27 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/tag/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for tag REST API
+func RouterAPI(app *fiber.App, TagUseCase domain.TagUseCase) {
+	handlerTag := &handler.TagHandler{TagUseCase: TagUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	tag := app.Group(basePath)
+
+	tag.Get("/tag", handlerTag.GetAllTag)
+	tag.Get("/tag/count", handlerTag.GetCountTag)
+	tag.Post("/tag", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.PostTag)
+	tag.Patch("/tag/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.RenameTag)
+	tag.Post("/tag/:id/merge", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.MergeTag)
+	tag.Delete("/tag/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.DeleteTag)
+	tag.Post("/movie/:id/tag", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.PostMovieTag)
+	tag.Delete("/movie/:id/tag/:tag_id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerTag.DeleteMovieTag)
+}
@@ -0,0 +1,273 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlTagRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLTagRepository(Conn *sql.DB) domain.TagMySQLRepo {
+	return &mysqlTagRepository{Conn}
+}
+
+func (db *mysqlTagRepository) PostTag(ctx context.Context, request domain.RequestTag) (id int, err error) {
+	query := `INSERT INTO tag (name, slug, dtm_crt, dtm_upd) VALUES (?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, request.Name, request.Slug)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+func (db *mysqlTagRepository) CountDataTag(ctx context.Context, request domain.RequestParamTag) (response domain.MetaData, err error) {
+	query := "SELECT COUNT(id) as total FROM tag WHERE 1=1"
+	var args []interface{}
+
+	if request.Search != nil {
+		query += " AND (name LIKE ? OR slug LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+// GetAllTag lists tags by name, withCount adds the number of published movies per tag and sorts by it
+func (db *mysqlTagRepository) GetAllTag(ctx context.Context, request domain.RequestParamTag, withCount bool) (response []domain.ResponseTag, err error) {
+	query := `SELECT tag.id, tag.name, tag.slug, COUNT(m.id) as total_movie
+              FROM tag
+              LEFT JOIN movie_tag ON movie_tag.tag_id = tag.id
+              LEFT JOIN movie m ON m.id = movie_tag.movie_id AND m.status = ?
+              WHERE 1=1`
+	var limit, page int
+	args := []interface{}{domain.MovieStatusPublished}
+
+	if request.Search != nil {
+		query += " AND (tag.name LIKE ? OR tag.slug LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	query += " GROUP BY tag.id, tag.name, tag.slug"
+	if withCount {
+		query += " ORDER BY total_movie DESC, tag.name"
+	} else {
+		query += " ORDER BY tag.name"
+	}
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseTag
+		var totalMovie uint
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug, &totalMovie); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		if withCount {
+			i.TotalMovie = &totalMovie
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTagRepository) GetDetailTag(ctx context.Context, id int) (response domain.ResponseTag, err error) {
+	query := `SELECT id, name, slug FROM tag WHERE id = ?`
+
+	err = db.Conn.QueryRowContext(ctx, query, id).Scan(&response.ID, &response.Name, &response.Slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseTag{}, err
+		}
+		log.Error(err)
+		return domain.ResponseTag{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTagRepository) GetTagBySlug(ctx context.Context, slug string) (response domain.ResponseTag, err error) {
+	query := `SELECT id, name, slug FROM tag WHERE slug = ?`
+
+	err = db.Conn.QueryRowContext(ctx, query, slug).Scan(&response.ID, &response.Name, &response.Slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseTag{}, err
+		}
+		log.Error(err)
+		return domain.ResponseTag{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTagRepository) UpdateTag(ctx context.Context, id int, request domain.RequestTag) (err error) {
+	query := `UPDATE tag SET name = ?, slug = ?, dtm_upd = NOW() WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.Name, request.Slug, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// MergeTag moves every movie of the source tag to the target tag then removes the source tag
+func (db *mysqlTagRepository) MergeTag(ctx context.Context, sourceID int, targetID int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT IGNORE INTO movie_tag (movie_id, tag_id)
+              SELECT movie_id, ? FROM movie_tag WHERE tag_id = ?`, targetID, sourceID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tag WHERE id = ?`, sourceID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlTagRepository) DeleteTag(ctx context.Context, id int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `DELETE FROM tag WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+func (db *mysqlTagRepository) PostMovieTag(ctx context.Context, movieID int, tagID int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `INSERT IGNORE INTO movie_tag (movie_id, tag_id) VALUES (?, ?)`, movieID, tagID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+func (db *mysqlTagRepository) DeleteMovieTag(ctx context.Context, movieID int, tagID int) (err error) {
+	res, err := db.Conn.ExecContext(ctx, `DELETE FROM movie_tag WHERE movie_id = ? AND tag_id = ?`, movieID, tagID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Not found")
+	}
+
+	return
+}
+
+func (db *mysqlTagRepository) GetTagByMovie(ctx context.Context, movieID int) (response []domain.ResponseTag, err error) {
+	query := `SELECT tag.id, tag.name, tag.slug
+              FROM movie_tag
+              JOIN tag ON tag.id = movie_tag.tag_id
+              WHERE movie_tag.movie_id = ?
+              ORDER BY tag.name`
+
+	rows, err := db.Conn.QueryContext(ctx, query, movieID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseTag
+		if err := rows.Scan(&i.ID, &i.Name, &i.Slug); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type tagUseCase struct {
+	tagMySQLRepo   domain.TagMySQLRepo
+	movieMySQLRepo domain.MovieMySQLRepo
+}
+
+func NewTagUsecase(TagMySQLRepo domain.TagMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.TagUseCase {
+	return &tagUseCase{
+		tagMySQLRepo:   TagMySQLRepo,
+		movieMySQLRepo: MovieMySQLRepo,
+	}
+}
+
+func (tgu *tagUseCase) normalize(request *domain.RequestTag) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	request.Slug = helper.Slugify(request.Name)
+	if request.Slug == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+	return
+}
+
+// findOrCreate returns the tag with the same slug, the tag is created when it does not exist yet
+func (tgu *tagUseCase) findOrCreate(ctx context.Context, request domain.RequestTag) (response domain.ResponseTag, err error) {
+	err = tgu.normalize(&request)
+	if err != nil {
+		return response, err
+	}
+
+	response, err = tgu.tagMySQLRepo.GetTagBySlug(ctx, request.Slug)
+	if err == nil {
+		return response, nil
+	}
+	if err.Error() != "Not found" {
+		return response, err
+	}
+
+	id, err := tgu.tagMySQLRepo.PostTag(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return response, err
+	}
+
+	return domain.ResponseTag{ID: uint(id), Name: request.Name, Slug: request.Slug}, nil
+}
+
+func (tgu *tagUseCase) PostTag(ctx context.Context, request domain.RequestTag) (response domain.ResponseTag, err error) {
+	return tgu.findOrCreate(ctx, request)
+}
+
+func (tgu *tagUseCase) GetAllTag(ctx context.Context, request domain.RequestParamTag) (response domain.ResponseGetAllTag, err error) {
+	return tgu.getAll(ctx, request, false)
+}
+
+func (tgu *tagUseCase) GetCountTag(ctx context.Context, request domain.RequestParamTag) (response domain.ResponseGetAllTag, err error) {
+	return tgu.getAll(ctx, request, true)
+}
+
+func (tgu *tagUseCase) getAll(ctx context.Context, request domain.RequestParamTag, withCount bool) (response domain.ResponseGetAllTag, err error) {
+	resCount, err := tgu.tagMySQLRepo.CountDataTag(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllTag{}, err
+	}
+
+	resTag, err := tgu.tagMySQLRepo.GetAllTag(ctx, request, withCount)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllTag{
+		MetaData: resCount,
+		Data:     resTag,
+	}
+	return
+}
+
+// RenameTag changes the tag name and slug, renaming into an existing slug must use merge instead
+func (tgu *tagUseCase) RenameTag(ctx context.Context, id int, request domain.RequestTag) (err error) {
+	_, err = tgu.tagMySQLRepo.GetDetailTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = tgu.normalize(&request)
+	if err != nil {
+		return err
+	}
+
+	existing, err := tgu.tagMySQLRepo.GetTagBySlug(ctx, request.Slug)
+	if err == nil && int(existing.ID) != id {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("tag slug already exists, merge the tags instead")}
+	}
+	if err != nil && err.Error() != "Not found" {
+		return err
+	}
+
+	return tgu.tagMySQLRepo.UpdateTag(ctx, id, request)
+}
+
+func (tgu *tagUseCase) MergeTag(ctx context.Context, id int, request domain.RequestMergeTag) (err error) {
+	if id == request.TargetID {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("cannot merge a tag into itself")}
+	}
+
+	for _, tagID := range []int{id, request.TargetID} {
+		_, err = tgu.tagMySQLRepo.GetDetailTag(ctx, tagID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tgu.tagMySQLRepo.MergeTag(ctx, id, request.TargetID)
+}
+
+func (tgu *tagUseCase) DeleteTag(ctx context.Context, id int) (err error) {
+	_, err = tgu.tagMySQLRepo.GetDetailTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return tgu.tagMySQLRepo.DeleteTag(ctx, id)
+}
+
+func (tgu *tagUseCase) PostMovieTag(ctx context.Context, movieID int, request domain.RequestMovieTag) (err error) {
+	_, err = tgu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		return err
+	}
+
+	if len(request.Tags) == 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("tags is required")}
+	}
+
+	for _, name := range request.Tags {
+		tag, err := tgu.findOrCreate(ctx, domain.RequestTag{Name: name})
+		if err != nil {
+			return err
+		}
+
+		err = tgu.tagMySQLRepo.PostMovieTag(ctx, movieID, int(tag.ID))
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func (tgu *tagUseCase) DeleteMovieTag(ctx context.Context, movieID int, tagID int) (err error) {
+	return tgu.tagMySQLRepo.DeleteMovieTag(ctx, movieID, tagID)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/theater/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for theater and auditorium REST API
+func RouterAPI(app *fiber.App, TheaterUseCase domain.TheaterUseCase) {
+	handlerTheater := &handler.TheaterHandler{TheaterUseCase: TheaterUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	theater := app.Group(basePath)
+	auditTheater := middleware.AuditEntity(func(c *fiber.Ctx, id int) (interface{}, error) {
+		return TheaterUseCase.GetDetailTheater(c.Context(), id)
+	})
+
+	theater.Get("/theater", handlerTheater.GetAllTheater)
+	theater.Post("/theater", middleware.Auth, middleware.Role(domain.RoleAdmin), auditTheater, handlerTheater.PostTheater)
+	theater.Get("/theater/nearby", handlerTheater.GetNearbyTheater)
+	theater.Get("/theater/:id", handlerTheater.GetDetailTheater)
+	theater.Patch("/theater/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), auditTheater, handlerTheater.UpdateTheater)
+	theater.Delete("/theater/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), auditTheater, handlerTheater.DeleteTheater)
+	theater.Post("/theater/:id/auditorium", middleware.Auth, middleware.Role(domain.RoleAdmin), handlerTheater.PostAuditorium)
+	theater.Patch("/auditorium/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), handlerTheater.UpdateAuditorium)
+	theater.Delete("/auditorium/:id", middleware.Auth, middleware.Role(domain.RoleAdmin), handlerTheater.DeleteAuditorium)
+}
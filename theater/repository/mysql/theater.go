@@ -0,0 +1,381 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlTheaterRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLTheaterRepository(Conn *sql.DB) domain.TheaterMySQLRepo {
+	return &mysqlTheaterRepository{Conn}
+}
+
+func (db *mysqlTheaterRepository) PostTheater(ctx context.Context, request domain.RequestTheater) (id int, err error) {
+	query := `INSERT INTO theater (name, address, city, latitude, longitude, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, request.Name, request.Address, request.City, request.Latitude, request.Longitude)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+const theaterQuery = `SELECT id, name, address, city, latitude, longitude, dtm_crt, dtm_upd FROM theater`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTheater(row rowScanner, extra ...interface{}) (response domain.ResponseTheater, err error) {
+	var latitude, longitude sql.NullFloat64
+	var dtmCrt, dtmUpd time.Time
+	dest := []interface{}{&response.ID, &response.Name, &response.Address, &response.City, &latitude, &longitude, &dtmCrt, &dtmUpd}
+	err = row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return response, err
+	}
+
+	if latitude.Valid && longitude.Valid {
+		response.Latitude = &latitude.Float64
+		response.Longitude = &longitude.Float64
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+func filterTheater(request domain.RequestParamTheater) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.City != nil {
+		query += " AND city = ?"
+		args = append(args, *request.City)
+	}
+
+	if request.Search != nil {
+		query += " AND (name LIKE ? OR address LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	return query, args
+}
+
+func (db *mysqlTheaterRepository) CountDataTheater(ctx context.Context, request domain.RequestParamTheater) (response domain.MetaData, err error) {
+	filter, args := filterTheater(request)
+	query := "SELECT COUNT(id) as total FROM theater" + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) GetAllTheater(ctx context.Context, request domain.RequestParamTheater) (response []domain.ResponseTheater, err error) {
+	filter, args := filterTheater(request)
+	query := theaterQuery + filter + ` ORDER BY city, name`
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanTheater(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) GetDetailTheater(ctx context.Context, id int) (response domain.ResponseTheater, err error) {
+	response, err = scanTheater(db.Conn.QueryRowContext(ctx, theaterQuery+` WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseTheater{}, err
+		}
+		log.Error(err)
+		return domain.ResponseTheater{}, err
+	}
+
+	return response, nil
+}
+
+// kmPerDegree is the length of one degree of latitude
+const kmPerDegree = 111.045
+
+// GetNearbyTheater returns the theaters within the radius closest first. A bounding box on the
+// indexed coordinates narrows the rows before the great-circle distance is computed
+func (db *mysqlTheaterRepository) GetNearbyTheater(ctx context.Context, request domain.RequestParamNearby) (response []domain.ResponseTheater, err error) {
+	deltaLat := request.RadiusKm / kmPerDegree
+	query := `SELECT * FROM (
+                  SELECT id, name, address, city, latitude, longitude, dtm_crt, dtm_upd,
+                      6371 * 2 * ASIN(SQRT(
+                          POWER(SIN(RADIANS(latitude - ?) / 2), 2) +
+                          COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2)
+                      )) AS distance
+                  FROM theater
+                  WHERE latitude BETWEEN ? AND ?`
+	args := []interface{}{request.Lat, request.Lat, request.Lng, request.Lat - deltaLat, request.Lat + deltaLat}
+
+	// Near the poles or the antimeridian the longitude range wraps, those searches skip it
+	if cos := math.Cos(request.Lat * math.Pi / 180); cos > 0.01 {
+		deltaLng := request.RadiusKm / (kmPerDegree * cos)
+		if request.Lng-deltaLng >= -180 && request.Lng+deltaLng <= 180 {
+			query += ` AND longitude BETWEEN ? AND ?`
+			args = append(args, request.Lng-deltaLng, request.Lng+deltaLng)
+		}
+	}
+
+	query += `) nearby WHERE distance <= ? ORDER BY distance, id`
+	args = append(args, request.RadiusKm)
+	if request.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, request.Limit)
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var distance float64
+		i, err := scanTheater(rows, &distance)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		distance = math.Round(distance*100) / 100
+		i.DistanceKm = &distance
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) UpdateTheater(ctx context.Context, id int, request domain.RequestTheater) (err error) {
+	query := `UPDATE theater
+              SET name = ?, address = ?, city = ?, latitude = ?, longitude = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.Name, request.Address, request.City, request.Latitude, request.Longitude, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// deleteBooked deletes the rows unless one of the showtimes deleted with them by cascade has bookings,
+// the bookings are kept as the sales history
+func deleteBooked(ctx context.Context, conn *sql.DB, query string, bookingQuery string, id int) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRowContext(ctx, bookingQuery, id).Scan(&count)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if count > 0 {
+		return errors.New("Booked")
+	}
+
+	_, err = tx.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlTheaterRepository) DeleteTheater(ctx context.Context, id int) (err error) {
+	bookingQuery := `SELECT COUNT(*) FROM booking b
+              JOIN showtime s ON s.id = b.showtime_id
+              JOIN auditorium a ON a.id = s.auditorium_id
+              WHERE a.theater_id = ? FOR UPDATE`
+
+	return deleteBooked(ctx, db.Conn, `DELETE FROM theater WHERE id = ?`, bookingQuery, id)
+}
+
+const auditoriumQuery = `SELECT id, theater_id, name, capacity, dtm_crt, dtm_upd FROM auditorium`
+
+func scanAuditorium(row rowScanner) (response domain.ResponseAuditorium, err error) {
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(&response.ID, &response.TheaterID, &response.Name, &response.Capacity, &dtmCrt, &dtmUpd)
+	if err != nil {
+		return response, err
+	}
+
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) GetAuditoriumByTheater(ctx context.Context, theaterID int) (response []domain.ResponseAuditorium, err error) {
+	rows, err := db.Conn.QueryContext(ctx, auditoriumQuery+` WHERE theater_id = ? ORDER BY name`, theaterID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanAuditorium(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) GetDetailAuditorium(ctx context.Context, id int) (response domain.ResponseAuditorium, err error) {
+	response, err = scanAuditorium(db.Conn.QueryRowContext(ctx, auditoriumQuery+` WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseAuditorium{}, err
+		}
+		log.Error(err)
+		return domain.ResponseAuditorium{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) GetAuditoriumByName(ctx context.Context, theaterID int, name string) (response domain.ResponseAuditorium, err error) {
+	response, err = scanAuditorium(db.Conn.QueryRowContext(ctx, auditoriumQuery+` WHERE theater_id = ? AND name = ?`, theaterID, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseAuditorium{}, err
+		}
+		log.Error(err)
+		return domain.ResponseAuditorium{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlTheaterRepository) PostAuditorium(ctx context.Context, theaterID int, request domain.RequestAuditorium) (id int, err error) {
+	query := `INSERT INTO auditorium (theater_id, name, capacity, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, theaterID, request.Name, request.Capacity)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+func (db *mysqlTheaterRepository) UpdateAuditorium(ctx context.Context, id int, request domain.RequestAuditorium) (err error) {
+	query := `UPDATE auditorium
+              SET name = ?, capacity = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.Name, request.Capacity, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *mysqlTheaterRepository) DeleteAuditorium(ctx context.Context, id int) (err error) {
+	bookingQuery := `SELECT COUNT(*) FROM booking b
+              JOIN showtime s ON s.id = b.showtime_id
+              WHERE s.auditorium_id = ? FOR UPDATE`
+
+	return deleteBooked(ctx, db.Conn, `DELETE FROM auditorium WHERE id = ?`, bookingQuery, id)
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type theaterUseCase struct {
+	theaterMySQLRepo domain.TheaterMySQLRepo
+}
+
+func NewTheaterUsecase(TheaterMySQLRepo domain.TheaterMySQLRepo) domain.TheaterUseCase {
+	return &theaterUseCase{
+		theaterMySQLRepo: TheaterMySQLRepo,
+	}
+}
+
+func (thu *theaterUseCase) validate(request *domain.RequestTheater) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	request.Address = strings.TrimSpace(request.Address)
+	request.City = strings.TrimSpace(request.City)
+	if request.Name == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+	if request.City == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("city is required")}
+	}
+	if (request.Latitude == nil) != (request.Longitude == nil) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("latitude and longitude must be set together")}
+	}
+	if request.Latitude != nil && (*request.Latitude < -90 || *request.Latitude > 90) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("latitude must be between -90 and 90")}
+	}
+	if request.Longitude != nil && (*request.Longitude < -180 || *request.Longitude > 180) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("longitude must be between -180 and 180")}
+	}
+	return
+}
+
+func (thu *theaterUseCase) PostTheater(ctx context.Context, request domain.RequestTheater) (id int, err error) {
+	err = thu.validate(&request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = thu.theaterMySQLRepo.PostTheater(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (thu *theaterUseCase) GetAllTheater(ctx context.Context, request domain.RequestParamTheater) (response domain.ResponseGetAllTheater, err error) {
+	resCount, err := thu.theaterMySQLRepo.CountDataTheater(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllTheater{}, err
+	}
+
+	resTheater, err := thu.theaterMySQLRepo.GetAllTheater(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllTheater{
+		MetaData: resCount,
+		Data:     resTheater,
+	}
+	return
+}
+
+func (thu *theaterUseCase) GetDetailTheater(ctx context.Context, id int) (response domain.ResponseTheater, err error) {
+	response, err = thu.theaterMySQLRepo.GetDetailTheater(ctx, id)
+	if err != nil {
+		return response, err
+	}
+
+	response.Auditoriums, err = thu.theaterMySQLRepo.GetAuditoriumByTheater(ctx, id)
+	if err != nil {
+		return domain.ResponseTheater{}, err
+	}
+	return
+}
+
+// GetNearbyTheater returns the theaters with coordinates within the radius of the point, closest first
+func (thu *theaterUseCase) GetNearbyTheater(ctx context.Context, request domain.RequestParamNearby) (response []domain.ResponseTheater, err error) {
+	err = helper.ValidateNearby(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err = thu.theaterMySQLRepo.GetNearbyTheater(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		response = []domain.ResponseTheater{}
+	}
+	return response, nil
+}
+
+// UpdateTheater replaces the theater, the coordinates are kept when the request leaves them out
+func (thu *theaterUseCase) UpdateTheater(ctx context.Context, id int, request domain.RequestTheater) (err error) {
+	theater, err := thu.theaterMySQLRepo.GetDetailTheater(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if request.Latitude == nil && request.Longitude == nil {
+		request.Latitude = theater.Latitude
+		request.Longitude = theater.Longitude
+	}
+
+	err = thu.validate(&request)
+	if err != nil {
+		return err
+	}
+
+	err = thu.theaterMySQLRepo.UpdateTheater(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+// bookedError rejects the delete of a theater or an auditorium having showtimes with bookings
+func bookedError(name string, err error) error {
+	if err.Error() == "Booked" {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New(name + " has showtimes with bookings")}
+	}
+	return err
+}
+
+// DeleteTheater deletes the theater, its auditoriums and showtimes are deleted by cascade
+func (thu *theaterUseCase) DeleteTheater(ctx context.Context, id int) (err error) {
+	_, err = thu.theaterMySQLRepo.GetDetailTheater(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = thu.theaterMySQLRepo.DeleteTheater(ctx, id)
+	if err != nil {
+		return bookedError("theater", err)
+	}
+	return
+}
+
+// validateAuditorium checks the auditorium, the name is unique in its theater
+func (thu *theaterUseCase) validateAuditorium(ctx context.Context, theaterID int, id int, request *domain.RequestAuditorium) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+	if request.Capacity < 0 {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("capacity must not be negative")}
+	}
+
+	existing, err := thu.theaterMySQLRepo.GetAuditoriumByName(ctx, theaterID, request.Name)
+	if err == nil && int(existing.ID) != id {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("auditorium name already exists in this theater")}
+	}
+	if err != nil && err.Error() != "Not found" {
+		return err
+	}
+	return nil
+}
+
+func (thu *theaterUseCase) PostAuditorium(ctx context.Context, theaterID int, request domain.RequestAuditorium) (id int, err error) {
+	_, err = thu.theaterMySQLRepo.GetDetailTheater(ctx, theaterID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = thu.validateAuditorium(ctx, theaterID, 0, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = thu.theaterMySQLRepo.PostAuditorium(ctx, theaterID, request)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (thu *theaterUseCase) UpdateAuditorium(ctx context.Context, id int, request domain.RequestAuditorium) (err error) {
+	auditorium, err := thu.theaterMySQLRepo.GetDetailAuditorium(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = thu.validateAuditorium(ctx, int(auditorium.TheaterID), id, &request)
+	if err != nil {
+		return err
+	}
+
+	err = thu.theaterMySQLRepo.UpdateAuditorium(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (thu *theaterUseCase) DeleteAuditorium(ctx context.Context, id int) (err error) {
+	_, err = thu.theaterMySQLRepo.GetDetailAuditorium(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = thu.theaterMySQLRepo.DeleteAuditorium(ctx, id)
+	if err != nil {
+		return bookedError("auditorium", err)
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/ticket/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for booking tickets and the entrance scanner REST API
+func RouterAPI(app *fiber.App, TicketUseCase domain.TicketUseCase) {
+	handlerTicket := &handler.TicketHandler{TicketUseCase: TicketUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	ticket := app.Group(basePath)
+
+	// Authenticated user API Route
+	ticket.Get("/booking/:id/ticket", middleware.Auth, handlerTicket.GetAllTicket)
+	ticket.Get("/booking/:id/ticket.pdf", middleware.Auth, handlerTicket.GetTicketPDF)
+
+	// Entrance scanner API Route
+	ticket.Post("/ticket/verify", middleware.Auth, middleware.Role(domain.RoleStaff, domain.RoleAdmin), handlerTicket.VerifyTicket)
+}
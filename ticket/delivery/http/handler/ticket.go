@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type TicketHandler struct {
+	TicketUseCase domain.TicketUseCase
+}
+
+func (th *TicketHandler) GetAllTicket(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := th.TicketUseCase.GetAllTicket(c.Context(), int(id), user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (th *TicketHandler) GetTicketPDF(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := th.TicketUseCase.GetTicketPDF(c.Context(), int(id), user)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="ticket-`+strconv.Itoa(int(id))+`.pdf"`)
+	return c.Status(fasthttp.StatusOK).Send(res)
+}
+
+func (th *TicketHandler) VerifyTicket(c *fiber.Ctx) (err error) {
+	var input domain.RequestVerifyTicket
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	user, _ := middleware.GetAuthUser(c)
+	res, err := th.TicketUseCase.VerifyTicket(c.Context(), input, user)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlTicketRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLTicketRepository(Conn *sql.DB) domain.TicketMySQLRepo {
+	return &mysqlTicketRepository{Conn}
+}
+
+const ticketQuery = `SELECT bs.booking_id, bs.seat_id, s.row_label, s.number, s.type, b.user_id, b.status,
+                  m.title, m.image, t.name, a.name, st.start_time, st.end_time, bs.used_at
+              FROM booking_seat bs
+              JOIN booking b ON b.id = bs.booking_id
+              JOIN seat s ON s.id = bs.seat_id
+              JOIN showtime st ON st.id = bs.showtime_id
+              JOIN movie m ON m.id = st.movie_id
+              JOIN auditorium a ON a.id = st.auditorium_id
+              JOIN theater t ON t.id = a.theater_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTicket(row rowScanner) (response domain.ResponseTicket, err error) {
+	var startTime, endTime time.Time
+	var usedAt sql.NullTime
+	err = row.Scan(
+		&response.BookingID,
+		&response.SeatID,
+		&response.Row,
+		&response.Number,
+		&response.SeatType,
+		&response.UserID,
+		&response.Status,
+		&response.MovieTitle,
+		&response.MovieImage,
+		&response.TheaterName,
+		&response.AuditoriumName,
+		&startTime,
+		&endTime,
+		&usedAt,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	response.StartTime = startTime.Format(domain.ShowtimeLayout)
+	response.EndTime = endTime.Format(domain.ShowtimeLayout)
+	if usedAt.Valid {
+		used := usedAt.Time.Format("2006-01-02 15:04:05")
+		response.UsedAt = &used
+	}
+	return response, nil
+}
+
+func (db *mysqlTicketRepository) GetTicketByBooking(ctx context.Context, bookingID int) (response []domain.ResponseTicket, err error) {
+	query := ticketQuery + ` WHERE bs.booking_id = ? ORDER BY LENGTH(s.row_label), s.row_label, s.number`
+
+	rows, err := db.Conn.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanTicket(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	if len(response) == 0 {
+		return nil, errors.New("Not found")
+	}
+	return response, nil
+}
+
+func (db *mysqlTicketRepository) GetDetailTicket(ctx context.Context, bookingID int, seatID int) (response domain.ResponseTicket, err error) {
+	response, err = scanTicket(db.Conn.QueryRowContext(ctx, ticketQuery+` WHERE bs.booking_id = ? AND bs.seat_id = ?`, bookingID, seatID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseTicket{}, err
+		}
+		log.Error(err)
+		return domain.ResponseTicket{}, err
+	}
+
+	return response, nil
+}
+
+// UseTicket marks the ticket as used once, a ticket already used returns "Exists"
+func (db *mysqlTicketRepository) UseTicket(ctx context.Context, bookingID int, seatID int, userID int) (err error) {
+	query := `UPDATE booking_seat SET used_at = NOW(), used_by = ?
+              WHERE booking_id = ? AND seat_id = ? AND active = 1 AND used_at IS NULL`
+
+	res, err := db.Conn.ExecContext(ctx, query, userID, bookingID, seatID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Exists")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// registerImage loads the movie image from the assets directory, it returns false when the image
+// is missing or in a format the PDF cannot embed so the ticket is printed without it
+func registerImage(pdf *fpdf.Fpdf, assetPath string, image string) bool {
+	if image == "" {
+		return false
+	}
+
+	imageType := strings.TrimPrefix(strings.ToLower(filepath.Ext(image)), ".")
+	if imageType != "jpg" && imageType != "jpeg" && imageType != "png" && imageType != "gif" {
+		return false
+	}
+
+	content, err := os.ReadFile(filepath.Join(assetPath, image))
+	if err != nil {
+		return false
+	}
+
+	pdf.RegisterImageOptionsReader(image, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(content))
+	if pdf.Err() {
+		pdf.ClearError()
+		return false
+	}
+	return true
+}
+
+func renderTicketPDF(tickets []domain.ResponseTicket, assetPath string) (response []byte, err error) {
+	pdf := fpdf.New("P", "mm", "A5", "")
+	pdf.SetTitle("Tickets", true)
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageWidth, _ := pdf.GetPageSize()
+	hasImage := registerImage(pdf, assetPath, tickets[0].MovieImage)
+
+	for _, ticket := range tickets {
+		pdf.AddPage()
+
+		textX := 12.0
+		if hasImage {
+			// Fit the image in a 40x80 mm box keeping its ratio
+			info := pdf.GetImageInfo(ticket.MovieImage)
+			width, height := 40.0, 40.0*info.Height()/info.Width()
+			if height > 80 {
+				width, height = 80*info.Width()/info.Height(), 80
+			}
+			pdf.ImageOptions(ticket.MovieImage, 12, 12, width, height, false, fpdf.ImageOptions{}, 0, "")
+			textX = 58
+		}
+
+		pdf.SetXY(textX, 12)
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.MultiCell(pageWidth-textX-12, 7, tr(ticket.MovieTitle), "", "L", false)
+
+		details := [][2]string{
+			{"Theater", ticket.TheaterName},
+			{"Auditorium", ticket.AuditoriumName},
+			{"Showtime", ticket.StartTime},
+			{"Seat", fmt.Sprintf("%s%d (%s)", ticket.Row, ticket.Number, ticket.SeatType)},
+			{"Booking", fmt.Sprintf("#%d", ticket.BookingID)},
+		}
+		for _, detail := range details {
+			pdf.SetX(textX)
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.CellFormat(25, 6, detail[0], "", 0, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(0, 6, tr(detail[1]), "", 1, "L", false, 0, "")
+		}
+
+		qr, err := qrcode.Encode(ticket.Token, qrcode.Medium, 512)
+		if err != nil {
+			return nil, err
+		}
+		qrName := fmt.Sprintf("qr-%d-%d", ticket.BookingID, ticket.SeatID)
+		pdf.RegisterImageOptionsReader(qrName, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+
+		qrSize := 70.0
+		pdf.ImageOptions(qrName, (pageWidth-qrSize)/2, 100, qrSize, qrSize, false, fpdf.ImageOptions{}, 0, "")
+
+		pdf.SetXY(12, 172)
+		pdf.SetFont("Courier", "", 7)
+		pdf.MultiCell(0, 4, ticket.Token, "", "C", false)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.MultiCell(0, 5, "Show this code at the entrance, it is valid for one admission", "", "C", false)
+	}
+
+	var buf bytes.Buffer
+	err = pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/spf13/viper"
+)
+
+type ticketUseCase struct {
+	ticketMySQLRepo domain.TicketMySQLRepo
+	location        *time.Location
+}
+
+func NewTicketUsecase(TicketMySQLRepo domain.TicketMySQLRepo) domain.TicketUseCase {
+	location, err := time.LoadLocation(constant.TimeLocation)
+	if err != nil {
+		location = time.Local
+	}
+
+	return &ticketUseCase{
+		ticketMySQLRepo: TicketMySQLRepo,
+		location:        location,
+	}
+}
+
+// GetAllTicket returns the tickets of a booking of the user, tokens are only given once the booking is paid
+func (tku *ticketUseCase) GetAllTicket(ctx context.Context, bookingID int, user domain.AuthUser) (response []domain.ResponseTicket, err error) {
+	response, err = tku.ticketMySQLRepo.GetTicketByBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if int(response[0].UserID) != user.ID && user.Role != domain.RoleAdmin {
+		return nil, errors.New("Not found")
+	}
+
+	if response[0].Status == domain.BookingStatusPaid {
+		secret := viper.GetString("ticket.secret")
+		for idx := range response {
+			response[idx].Token, err = helper.SignTicket(int(response[idx].BookingID), int(response[idx].SeatID), secret)
+			if err != nil {
+				log.Error(err)
+				return nil, err
+			}
+		}
+	}
+	return response, nil
+}
+
+// GetTicketPDF renders one printable page per seat of a paid booking
+func (tku *ticketUseCase) GetTicketPDF(ctx context.Context, bookingID int, user domain.AuthUser) (response []byte, err error) {
+	tickets, err := tku.GetAllTicket(ctx, bookingID, user)
+	if err != nil {
+		return nil, err
+	}
+	if tickets[0].Status != domain.BookingStatusPaid {
+		return nil, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("booking is %s", tickets[0].Status)}
+	}
+
+	response, err = renderTicketPDF(tickets, viper.GetString("server.url_assets"))
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// VerifyTicket checks a scanned token and admits the ticket once, from ticket.entry_before minutes
+// before the showtime until it ends
+func (tku *ticketUseCase) VerifyTicket(ctx context.Context, request domain.RequestVerifyTicket, user domain.AuthUser) (response domain.ResponseTicket, err error) {
+	bookingID, seatID, err := helper.ParseTicket(request.Token, viper.GetString("ticket.secret"))
+	if err != nil {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("ticket is invalid")}
+	}
+
+	response, err = tku.ticketMySQLRepo.GetDetailTicket(ctx, bookingID, seatID)
+	if err != nil {
+		return response, err
+	}
+	if response.Status != domain.BookingStatusPaid {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("booking is %s", response.Status)}
+	}
+	if response.UsedAt != nil {
+		return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: fmt.Errorf("ticket already used at %s", *response.UsedAt)}
+	}
+
+	startTime, err := time.ParseInLocation(domain.ShowtimeLayout, response.StartTime, tku.location)
+	if err != nil {
+		return response, err
+	}
+	endTime, err := time.ParseInLocation(domain.ShowtimeLayout, response.EndTime, tku.location)
+	if err != nil {
+		return response, err
+	}
+
+	now := time.Now().In(tku.location)
+	if now.After(endTime) {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("showtime is over")}
+	}
+	entryTime := startTime.Add(-time.Duration(viper.GetInt("ticket.entry_before")) * time.Minute)
+	if now.Before(entryTime) {
+		return response, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: fmt.Errorf("entry opens at %s", entryTime.Format(domain.ShowtimeLayout))}
+	}
+
+	err = tku.ticketMySQLRepo.UseTicket(ctx, bookingID, seatID, user.ID)
+	if err != nil {
+		if err.Error() == "Exists" {
+			return response, constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("ticket already used")}
+		}
+		return response, err
+	}
+
+	usedAt := now.Format("2006-01-02 15:04:05")
+	response.UsedAt = &usedAt
+	return response, nil
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/userlist/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for user curated list REST API
+func RouterAPI(app *fiber.App, UserListUseCase domain.UserListUseCase) {
+	handlerUserList := &handler.UserListHandler{UserListUseCase: UserListUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	// Public API Route, shared by slug
+	list := app.Group(basePath)
+	list.Get("/list/:slug", middleware.OptionalAuth, handlerUserList.GetPublicUserList)
+
+	// Authenticated user API Route
+	me := app.Group(basePath+"/me", middleware.Auth)
+	me.Get("/list", handlerUserList.GetAllUserList)
+	me.Post("/list", handlerUserList.PostUserList)
+	me.Get("/list/:id", handlerUserList.GetDetailUserList)
+	me.Patch("/list/:id", handlerUserList.UpdateUserList)
+	me.Delete("/list/:id", handlerUserList.DeleteUserList)
+	me.Post("/list/:id/movie", handlerUserList.AddUserListMovie)
+	me.Delete("/list/:id/movie/:movie_id", handlerUserList.DeleteUserListMovie)
+	me.Put("/list/:id/order", handlerUserList.ReorderUserList)
+}
@@ -0,0 +1,374 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlUserListRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLUserListRepository(Conn *sql.DB) domain.UserListMySQLRepo {
+	return &mysqlUserListRepository{Conn}
+}
+
+const userListQuery = `SELECT l.id, l.user_id, l.name, l.description, l.slug, l.is_public,
+                  (SELECT COUNT(*) FROM user_list_movie lm WHERE lm.list_id = l.id) as total_movie, l.dtm_crt, l.dtm_upd
+              FROM user_list l`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUserList(row rowScanner) (response domain.ResponseUserList, err error) {
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.UserID,
+		&response.Name,
+		&response.Description,
+		&response.Slug,
+		&response.IsPublic,
+		&response.TotalMovie,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+const userListMovieInsert = `INSERT INTO user_list_movie (list_id, movie_id, position, dtm_crt) VALUES (?, ?, ?, NOW())`
+
+// PostUserList creates the list with its movies in one transaction
+func (db *mysqlUserListRepository) PostUserList(ctx context.Context, userID int, request domain.RequestUserList) (id int, err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO user_list (user_id, name, description, slug, is_public, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := tx.ExecContext(ctx, query, userID, request.Name, request.Description, request.Slug, request.IsPublic)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertUserListMovie(ctx, tx, int(lastID), request.MovieIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), tx.Commit()
+}
+
+// insertUserListMovie adds the movies to an empty list, the order of movieIDs becomes the position
+func insertUserListMovie(ctx context.Context, tx *sql.Tx, id int, movieIDs []int) (err error) {
+	for idx, movieID := range movieIDs {
+		_, err = tx.ExecContext(ctx, userListMovieInsert, id, movieID, idx+1)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *mysqlUserListRepository) CountDataUserList(ctx context.Context, request domain.RequestParamUserList) (response domain.MetaData, err error) {
+	query := "SELECT COUNT(id) as total FROM user_list WHERE user_id = ?"
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, request.UserID).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlUserListRepository) GetAllUserList(ctx context.Context, request domain.RequestParamUserList) (response []domain.ResponseUserList, err error) {
+	query := userListQuery + ` WHERE l.user_id = ? ORDER BY l.dtm_upd DESC, l.id DESC`
+	var limit, page int
+	args := []interface{}{request.UserID}
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanUserList(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlUserListRepository) GetDetailUserList(ctx context.Context, id int) (response domain.ResponseUserList, err error) {
+	response, err = scanUserList(db.Conn.QueryRowContext(ctx, userListQuery+` WHERE l.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseUserList{}, err
+		}
+		log.Error(err)
+		return domain.ResponseUserList{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlUserListRepository) GetUserListBySlug(ctx context.Context, slug string) (response domain.ResponseUserList, err error) {
+	response, err = scanUserList(db.Conn.QueryRowContext(ctx, userListQuery+` WHERE l.slug = ?`, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseUserList{}, err
+		}
+		log.Error(err)
+		return domain.ResponseUserList{}, err
+	}
+
+	return response, nil
+}
+
+// UpdateUserList updates the list and replaces its items when movie ids are sent, in one transaction
+func (db *mysqlUserListRepository) UpdateUserList(ctx context.Context, id int, request domain.RequestUserList) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user_list
+              SET name = ?, description = ?, is_public = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = tx.ExecContext(ctx, query, request.Name, request.Description, request.IsPublic, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if request.MovieIDs != nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM user_list_movie WHERE list_id = ?`, id)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+
+		err = insertUserListMovie(ctx, tx, id, request.MovieIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlUserListRepository) DeleteUserList(ctx context.Context, id int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `DELETE FROM user_list WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+// GetUserListMovie returns the movies of the list by position, only the published ones when published is set
+func (db *mysqlUserListRepository) GetUserListMovie(ctx context.Context, id int, published bool) (response []domain.ResponseUserListMovie, err error) {
+	query := `SELECT m.id, m.title, m.rating, m.image, lm.position
+              FROM user_list_movie lm
+              JOIN movie m ON m.id = lm.movie_id
+              WHERE lm.list_id = ?`
+	args := []interface{}{id}
+
+	if published {
+		query += " AND m.status = ?"
+		args = append(args, domain.MovieStatusPublished)
+	}
+	query += " ORDER BY lm.position, lm.dtm_crt"
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.ResponseUserListMovie
+		if err := rows.Scan(&i.ID, &i.Title, &i.Rating, &i.Image, &i.Position); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+// SetUserListMovie replaces the list items, the order of movieIDs becomes the position
+func (db *mysqlUserListRepository) SetUserListMovie(ctx context.Context, id int, movieIDs []int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_list_movie WHERE list_id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = insertUserListMovie(ctx, tx, id, movieIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE user_list SET dtm_upd = NOW() WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddUserListMovie adds or moves a movie to the given position, the movies are renumbered without gaps
+func (db *mysqlUserListRepository) AddUserListMovie(ctx context.Context, id int, movieID int, position int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = helper.PlaceMovie(ctx, tx, "user_list_movie", "list_id", id, movieID, position, userListMovieInsert)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE user_list SET dtm_upd = NOW() WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteUserListMovie removes a movie, the movies after it move up
+func (db *mysqlUserListRepository) DeleteUserListMovie(ctx context.Context, id int, movieID int) (err error) {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_list_movie WHERE list_id = ? AND movie_id = ?`, id, movieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Not found")
+	}
+
+	err = helper.CompactMovie(ctx, tx, "user_list_movie", "list_id", id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *mysqlUserListRepository) CountPublicUserListByMovie(ctx context.Context, movieID int) (total uint, err error) {
+	query := `SELECT COUNT(*) FROM user_list_movie lm
+              JOIN user_list l ON l.id = lm.list_id
+              WHERE lm.movie_id = ? AND l.is_public = TRUE`
+
+	err = db.Conn.QueryRowContext(ctx, query, movieID).Scan(&total)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return total, nil
+}
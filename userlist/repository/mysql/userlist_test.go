@@ -0,0 +1,41 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper/sqltest"
+	"xsis-academy-test-service-movie/userlist/repository/mysql"
+)
+
+func TestPostUserListWithMovies(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	repo := mysql.NewMySQLUserListRepository(sqltest.Open(recorder))
+
+	id, err := repo.PostUserList(context.Background(), 5, domain.RequestUserList{Name: "Weekend", MovieIDs: []int{8, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inserts := recorder.Find("INSERT INTO user_list_movie")
+	if id != 1 || len(inserts) != 2 || inserts[0].Count(8) != 1 || inserts[1].Count(4) != 1 || inserts[1].Count(2) != 1 {
+		t.Fatalf("got %d and %+v, want the movies inserted in order with the list", id, inserts)
+	}
+}
+
+func TestUpdateUserListWithMovies(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	repo := mysql.NewMySQLUserListRepository(sqltest.Open(recorder))
+
+	err := repo.UpdateUserList(context.Background(), 3, domain.RequestUserList{Name: "Weekend", MovieIDs: []int{8, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The list and its movies are written in a single transaction
+	queries := recorder.Queries()
+	if len(queries) != 6 || queries[0] != "BEGIN" || !strings.Contains(queries[1], "UPDATE user_list") || !strings.Contains(queries[2], "DELETE FROM user_list_movie") || queries[5] != "COMMIT" {
+		t.Fatalf("got %q, want the update and the movies in one transaction", queries)
+	}
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type userListUseCase struct {
+	userListMySQLRepo domain.UserListMySQLRepo
+	movieMySQLRepo    domain.MovieMySQLRepo
+}
+
+func NewUserListUsecase(UserListMySQLRepo domain.UserListMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.UserListUseCase {
+	return &userListUseCase{
+		userListMySQLRepo: UserListMySQLRepo,
+		movieMySQLRepo:    MovieMySQLRepo,
+	}
+}
+
+func (ulu *userListUseCase) validate(ctx context.Context, request *domain.RequestUserList) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+
+	seen := map[int]bool{}
+	for _, movieID := range request.MovieIDs {
+		if seen[movieID] {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("movie_ids must be unique")}
+		}
+		seen[movieID] = true
+
+		err = ulu.checkMovie(ctx, movieID)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// checkMovie accepts published movies only, users do not see the others
+func (ulu *userListUseCase) checkMovie(ctx context.Context, movieID int) (err error) {
+	movie, err := ulu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil && err.Error() != "Not found" {
+		return err
+	}
+	if err != nil || movie.Status != domain.MovieStatusPublished {
+		return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+	}
+	return
+}
+
+// getOwned returns the list only when it belongs to the user, other user lists are reported as not found
+func (ulu *userListUseCase) getOwned(ctx context.Context, user domain.AuthUser, id int) (response domain.ResponseUserList, err error) {
+	response, err = ulu.userListMySQLRepo.GetDetailUserList(ctx, id)
+	if err != nil {
+		return response, err
+	}
+
+	if int(response.UserID) != user.ID {
+		return domain.ResponseUserList{}, errors.New("Not found")
+	}
+	return
+}
+
+func (ulu *userListUseCase) PostUserList(ctx context.Context, user domain.AuthUser, request domain.RequestUserList) (response domain.ResponseUserList, err error) {
+	err = ulu.validate(ctx, &request)
+	if err != nil {
+		return response, err
+	}
+
+	// The random suffix keeps the slug unique and unguessable for private lists
+	request.Slug = strings.Trim(helper.Slugify(request.Name)+"-"+helper.RandomHex(4), "-")
+	id, err := ulu.userListMySQLRepo.PostUserList(ctx, user.ID, request)
+	if err != nil {
+		log.Error(err)
+		return response, err
+	}
+
+	return ulu.GetDetailUserList(ctx, user, id)
+}
+
+func (ulu *userListUseCase) GetAllUserList(ctx context.Context, request domain.RequestParamUserList) (response domain.ResponseGetAllUserList, err error) {
+	resCount, err := ulu.userListMySQLRepo.CountDataUserList(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllUserList{}, err
+	}
+
+	resUserList, err := ulu.userListMySQLRepo.GetAllUserList(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllUserList{
+		MetaData: resCount,
+		Data:     resUserList,
+	}
+	return
+}
+
+func (ulu *userListUseCase) GetDetailUserList(ctx context.Context, user domain.AuthUser, id int) (response domain.ResponseUserList, err error) {
+	response, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return response, err
+	}
+
+	response.Movies, err = ulu.userListMySQLRepo.GetUserListMovie(ctx, id, false)
+	if err != nil {
+		return domain.ResponseUserList{}, err
+	}
+	return
+}
+
+// GetPublicUserList reads a shared list by slug, a private list is only visible to its owner
+func (ulu *userListUseCase) GetPublicUserList(ctx context.Context, user *domain.AuthUser, slug string) (response domain.ResponseUserList, err error) {
+	response, err = ulu.userListMySQLRepo.GetUserListBySlug(ctx, slug)
+	if err != nil {
+		return response, err
+	}
+
+	if !response.IsPublic && (user == nil || int(response.UserID) != user.ID) {
+		return domain.ResponseUserList{}, errors.New("Not found")
+	}
+
+	// A shared list is read by anyone, unpublished movies are left out
+	response.Movies, err = ulu.userListMySQLRepo.GetUserListMovie(ctx, int(response.ID), true)
+	if err != nil {
+		return domain.ResponseUserList{}, err
+	}
+	return
+}
+
+// UpdateUserList updates the list, items are only replaced when movie_ids is sent
+func (ulu *userListUseCase) UpdateUserList(ctx context.Context, user domain.AuthUser, id int, request domain.RequestUserList) (err error) {
+	_, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	err = ulu.validate(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	err = ulu.userListMySQLRepo.UpdateUserList(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (ulu *userListUseCase) DeleteUserList(ctx context.Context, user domain.AuthUser, id int) (err error) {
+	_, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	return ulu.userListMySQLRepo.DeleteUserList(ctx, id)
+}
+
+func (ulu *userListUseCase) AddUserListMovie(ctx context.Context, user domain.AuthUser, id int, request domain.RequestUserListMovie) (err error) {
+	_, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	err = ulu.checkMovie(ctx, request.MovieID)
+	if err != nil {
+		return err
+	}
+
+	movies, err := ulu.userListMySQLRepo.GetUserListMovie(ctx, id, false)
+	if err != nil {
+		return err
+	}
+
+	movieIDs := make([]int, len(movies))
+	for idx, movie := range movies {
+		movieIDs[idx] = int(movie.ID)
+	}
+	position, err := helper.MoviePosition(movieIDs, request.MovieID, request.Position)
+	if err != nil {
+		return err
+	}
+
+	return ulu.userListMySQLRepo.AddUserListMovie(ctx, id, request.MovieID, position)
+}
+
+func (ulu *userListUseCase) DeleteUserListMovie(ctx context.Context, user domain.AuthUser, id int, movieID int) (err error) {
+	_, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	return ulu.userListMySQLRepo.DeleteUserListMovie(ctx, id, movieID)
+}
+
+// ReorderUserList sets a new order, movie_ids must contain exactly the movies already in the list
+func (ulu *userListUseCase) ReorderUserList(ctx context.Context, user domain.AuthUser, id int, request domain.RequestUserListOrder) (err error) {
+	_, err = ulu.getOwned(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	movies, err := ulu.userListMySQLRepo.GetUserListMovie(ctx, id, false)
+	if err != nil {
+		return err
+	}
+
+	current := make([]int, len(movies))
+	for idx, movie := range movies {
+		current[idx] = int(movie.ID)
+	}
+	requested := append([]int{}, request.MovieIDs...)
+	sort.Ints(current)
+	sort.Ints(requested)
+
+	invalid := len(current) != len(requested)
+	for idx := 0; !invalid && idx < len(current); idx++ {
+		invalid = current[idx] != requested[idx]
+	}
+	if invalid {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("movie_ids must contain every movie of the list exactly once")}
+	}
+
+	return ulu.userListMySQLRepo.SetUserListMovie(ctx, id, request.MovieIDs)
+}
@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/userlist/usecase"
+)
+
+type movieRepo struct {
+	domain.MovieMySQLRepo
+	movies map[int]domain.ResponseMovie
+}
+
+func (repo movieRepo) GetDetailMovie(ctx context.Context, id int) (domain.ResponseMovie, error) {
+	return repo.movies[id], nil
+}
+
+type userListRepo struct {
+	domain.UserListMySQLRepo
+	published []bool
+	added     []int
+}
+
+func (repo *userListRepo) GetUserListBySlug(ctx context.Context, slug string) (domain.ResponseUserList, error) {
+	return domain.ResponseUserList{ID: 4, UserID: 9, Slug: slug, IsPublic: true}, nil
+}
+
+func (repo *userListRepo) GetDetailUserList(ctx context.Context, id int) (domain.ResponseUserList, error) {
+	return domain.ResponseUserList{ID: uint(id), UserID: 9}, nil
+}
+
+func (repo *userListRepo) GetUserListMovie(ctx context.Context, id int, published bool) ([]domain.ResponseUserListMovie, error) {
+	repo.published = append(repo.published, published)
+	return nil, nil
+}
+
+func (repo *userListRepo) AddUserListMovie(ctx context.Context, id int, movieID int, position int) error {
+	repo.added = append(repo.added, movieID)
+	return nil
+}
+
+func TestGetPublicUserListLeavesOutUnpublished(t *testing.T) {
+	lists := &userListRepo{}
+	_, err := usecase.NewUserListUsecase(lists, movieRepo{}).GetPublicUserList(context.Background(), nil, "best-4f2a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists.published) != 1 || !lists.published[0] {
+		t.Fatalf("got published %v, want the movies of an anonymous read filtered", lists.published)
+	}
+}
+
+func TestAddUserListMovieOfDraft(t *testing.T) {
+	movies := movieRepo{movies: map[int]domain.ResponseMovie{
+		1: {ID: 1, Status: domain.MovieStatusDraft},
+		2: {ID: 2, Status: domain.MovieStatusPublished},
+	}}
+	lists := &userListRepo{}
+	userListUseCase := usecase.NewUserListUsecase(lists, movies)
+	user := domain.AuthUser{ID: 9}
+
+	err := userListUseCase.AddUserListMovie(context.Background(), user, 4, domain.RequestUserListMovie{MovieID: 1})
+	if err == nil || err.Error() != "movie is not exists" {
+		t.Fatalf("got %v, want the draft reported as not existing", err)
+	}
+
+	err = userListUseCase.AddUserListMovie(context.Background(), user, 4, domain.RequestUserListMovie{MovieID: 2})
+	if err != nil || len(lists.added) != 1 || lists.added[0] != 2 {
+		t.Fatalf("got %v, added %v, want the published movie added", err, lists.added)
+	}
+}
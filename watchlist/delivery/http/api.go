@@ -0,0 +1,26 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/watchlist/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for the user watchlist and favorites REST API
+func RouterAPI(app *fiber.App, WatchlistUseCase domain.WatchlistUseCase) {
+	handlerWatchlist := &handler.WatchlistHandler{WatchlistUseCase: WatchlistUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	me := app.Group(basePath+"/me", middleware.Auth)
+
+	me.Get("/watchlist", handlerWatchlist.GetAllUserMovie(domain.UserMovieWatchlist))
+	me.Post("/watchlist", handlerWatchlist.PostUserMovie(domain.UserMovieWatchlist))
+	me.Delete("/watchlist/:movie_id", handlerWatchlist.DeleteUserMovie(domain.UserMovieWatchlist))
+
+	me.Get("/favorite", handlerWatchlist.GetAllUserMovie(domain.UserMovieFavorite))
+	me.Post("/favorite", handlerWatchlist.PostUserMovie(domain.UserMovieFavorite))
+	me.Delete("/favorite/:movie_id", handlerWatchlist.DeleteUserMovie(domain.UserMovieFavorite))
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+	"xsis-academy-test-service-movie/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type WatchlistHandler struct {
+	WatchlistUseCase domain.WatchlistUseCase
+}
+
+// GetAllUserMovie returns a handler listing the user movies of listType
+func (wh *WatchlistHandler) GetAllUserMovie(listType string) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		var input domain.RequestParamMovie
+		input.Page, input.Limit, err = helper.ParsePaging(c)
+		if err != nil {
+			return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+		}
+
+		user, _ := middleware.GetAuthUser(c)
+		res, err := wh.WatchlistUseCase.GetAllUserMovie(c.Context(), user, listType, input)
+		if err != nil {
+			return helper.HttpResponseFromError(c, err)
+		}
+		return c.Status(fasthttp.StatusOK).JSON(res)
+	}
+}
+
+// PostUserMovie returns a handler adding a movie to listType
+func (wh *WatchlistHandler) PostUserMovie(listType string) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		var input domain.RequestUserMovie
+		err = c.BodyParser(&input)
+		if err != nil {
+			log.Error(err.Error())
+			return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+		}
+
+		user, _ := middleware.GetAuthUser(c)
+		err = wh.WatchlistUseCase.PostUserMovie(c.Context(), user, listType, input)
+		if err != nil {
+			return helper.HttpResponseFromError(c, err)
+		}
+		return c.SendStatus(fasthttp.StatusCreated)
+	}
+}
+
+// DeleteUserMovie returns a handler removing a movie from listType
+func (wh *WatchlistHandler) DeleteUserMovie(listType string) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		movieID, err := strconv.ParseInt(c.Params("movie_id"), 10, 64)
+		if err != nil {
+			log.Error(err)
+			return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+		}
+
+		user, _ := middleware.GetAuthUser(c)
+		err = wh.WatchlistUseCase.DeleteUserMovie(c.Context(), user, listType, int(movieID))
+		if err != nil {
+			return helper.HttpResponseFromError(c, err)
+		}
+		return c.Status(fasthttp.StatusOK).SendString("Deleted")
+	}
+}
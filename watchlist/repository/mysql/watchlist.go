@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlWatchlistRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLWatchlistRepository(Conn *sql.DB) domain.WatchlistMySQLRepo {
+	return &mysqlWatchlistRepository{Conn}
+}
+
+func (db *mysqlWatchlistRepository) PostUserMovie(ctx context.Context, userID int, listType string, movieID int) (err error) {
+	query := `INSERT IGNORE INTO user_movie (user_id, movie_id, type, dtm_crt) VALUES (?, ?, ?, NOW())`
+
+	_, err = db.Conn.ExecContext(ctx, query, userID, movieID, listType)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+func (db *mysqlWatchlistRepository) DeleteUserMovie(ctx context.Context, userID int, listType string, movieID int) (err error) {
+	query := `DELETE FROM user_movie WHERE user_id = ? AND type = ? AND movie_id = ?`
+
+	res, err := db.Conn.ExecContext(ctx, query, userID, listType, movieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("Not found")
+	}
+
+	return
+}
+
+func (db *mysqlWatchlistRepository) GetUserMovieFlag(ctx context.Context, userID int, movieIDs []int) (response map[int]domain.UserMovieFlag, err error) {
+	response = map[int]domain.UserMovieFlag{}
+	if len(movieIDs) == 0 {
+		return response, nil
+	}
+
+	args := []interface{}{userID}
+	for _, movieID := range movieIDs {
+		args = append(args, movieID)
+	}
+
+	query := `SELECT movie_id, type FROM user_movie
+              WHERE user_id = ? AND movie_id IN (?` + strings.Repeat(", ?", len(movieIDs)-1) + `)`
+
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieID int
+		var listType string
+		if err := rows.Scan(&movieID, &listType); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+
+		flag := response[movieID]
+		switch listType {
+		case domain.UserMovieWatchlist:
+			flag.InWatchlist = true
+		case domain.UserMovieFavorite:
+			flag.IsFavorite = true
+		}
+		response[movieID] = flag
+	}
+
+	return response, nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type watchlistUseCase struct {
+	watchlistMySQLRepo domain.WatchlistMySQLRepo
+	movieMySQLRepo     domain.MovieMySQLRepo
+	movieUseCase       domain.MovieUseCase
+}
+
+func NewWatchlistUsecase(WatchlistMySQLRepo domain.WatchlistMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo, MovieUseCase domain.MovieUseCase) domain.WatchlistUseCase {
+	return &watchlistUseCase{
+		watchlistMySQLRepo: WatchlistMySQLRepo,
+		movieMySQLRepo:     MovieMySQLRepo,
+		movieUseCase:       MovieUseCase,
+	}
+}
+
+// PostUserMovie adds a published movie to the watchlist or favorites, the others are reported as not existing
+func (wlu *watchlistUseCase) PostUserMovie(ctx context.Context, user domain.AuthUser, listType string, request domain.RequestUserMovie) (err error) {
+	movie, err := wlu.movieMySQLRepo.GetDetailMovie(ctx, request.MovieID)
+	if err != nil && err.Error() != "Not found" {
+		return err
+	}
+	if err != nil || movie.Status != domain.MovieStatusPublished {
+		return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("movie is not exists")}
+	}
+
+	err = wlu.watchlistMySQLRepo.PostUserMovie(ctx, user.ID, listType, request.MovieID)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (wlu *watchlistUseCase) DeleteUserMovie(ctx context.Context, user domain.AuthUser, listType string, movieID int) (err error) {
+	return wlu.watchlistMySQLRepo.DeleteUserMovie(ctx, user.ID, listType, movieID)
+}
+
+// GetAllUserMovie lists the watchlist or favorites with the same paging and response as the movie list. A movie
+// unpublished since it was added is left out, whatever the role of the user
+func (wlu *watchlistUseCase) GetAllUserMovie(ctx context.Context, user domain.AuthUser, listType string, request domain.RequestParamMovie) (response domain.ResponseGetAllMovie, err error) {
+	published := domain.MovieStatusPublished
+	request.User = &user
+	request.Status = &published
+	request.UserMovie = &domain.UserMovieFilter{UserID: user.ID, Type: listType}
+
+	response, err = wlu.movieUseCase.GetAllMovie(ctx, request)
+	if err != nil && err.Error() == "Not found" {
+		response = domain.ResponseGetAllMovie{
+			MetaData: domain.MetaData{Page: uint(*request.Page), Limit: uint(*request.Limit)},
+			Data:     []domain.ResponseMovie{},
+		}
+		return response, nil
+	}
+	return
+}
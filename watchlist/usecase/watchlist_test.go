@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/watchlist/usecase"
+)
+
+type movieRepo struct {
+	domain.MovieMySQLRepo
+	movies map[int]domain.ResponseMovie
+}
+
+func (repo movieRepo) GetDetailMovie(ctx context.Context, id int) (domain.ResponseMovie, error) {
+	return repo.movies[id], nil
+}
+
+type movieUseCase struct {
+	domain.MovieUseCase
+	request domain.RequestParamMovie
+}
+
+func (mvu *movieUseCase) GetAllMovie(ctx context.Context, request domain.RequestParamMovie) (domain.ResponseGetAllMovie, error) {
+	mvu.request = request
+	return domain.ResponseGetAllMovie{}, nil
+}
+
+type watchlistRepo struct {
+	domain.WatchlistMySQLRepo
+	added []int
+}
+
+func (repo *watchlistRepo) PostUserMovie(ctx context.Context, userID int, listType string, movieID int) error {
+	repo.added = append(repo.added, movieID)
+	return nil
+}
+
+func TestPostUserMovieOfDraft(t *testing.T) {
+	movies := movieRepo{movies: map[int]domain.ResponseMovie{
+		1: {ID: 1, Status: domain.MovieStatusDraft},
+		2: {ID: 2, Status: domain.MovieStatusPublished},
+	}}
+	watchlist := &watchlistRepo{}
+	watchlistUseCase := usecase.NewWatchlistUsecase(watchlist, movies, &movieUseCase{})
+	user := domain.AuthUser{ID: 9, Role: domain.RoleUser}
+
+	err := watchlistUseCase.PostUserMovie(context.Background(), user, domain.UserMovieWatchlist, domain.RequestUserMovie{MovieID: 1})
+	if err == nil || err.Error() != "movie is not exists" {
+		t.Fatalf("got %v, want the draft reported as not existing", err)
+	}
+
+	err = watchlistUseCase.PostUserMovie(context.Background(), user, domain.UserMovieWatchlist, domain.RequestUserMovie{MovieID: 2})
+	if err != nil || len(watchlist.added) != 1 || watchlist.added[0] != 2 {
+		t.Fatalf("got %v, added %v, want the published movie added", err, watchlist.added)
+	}
+}
+
+func TestGetAllUserMovieListsPublished(t *testing.T) {
+	movies := &movieUseCase{}
+	watchlistUseCase := usecase.NewWatchlistUsecase(&watchlistRepo{}, movieRepo{}, movies)
+
+	// Editors list drafts in the movie list, not in their watchlist
+	user := domain.AuthUser{ID: 9, Role: domain.RoleEditor}
+	_, err := watchlistUseCase.GetAllUserMovie(context.Background(), user, domain.UserMovieWatchlist, domain.RequestParamMovie{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if movies.request.Status == nil || *movies.request.Status != domain.MovieStatusPublished {
+		t.Fatalf("got status %v, want the watchlist filtered on published", movies.request.Status)
+	}
+}
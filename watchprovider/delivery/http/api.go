@@ -0,0 +1,28 @@
+package http
+
+import (
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/middleware"
+	"xsis-academy-test-service-movie/watchprovider/delivery/http/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RouterAPI is the router for watch provider and movie availability REST API
+func RouterAPI(app *fiber.App, WatchProviderUseCase domain.WatchProviderUseCase) {
+	handlerWatchProvider := &handler.WatchProviderHandler{WatchProviderUseCase: WatchProviderUseCase}
+	basePath := viper.GetString("server.base_path")
+
+	watchProvider := app.Group(basePath)
+
+	watchProvider.Get("/watch-provider", handlerWatchProvider.GetAllWatchProvider)
+	watchProvider.Post("/watch-provider", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.PostWatchProvider)
+	watchProvider.Get("/watch-provider/:id", handlerWatchProvider.GetDetailWatchProvider)
+	watchProvider.Patch("/watch-provider/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.UpdateWatchProvider)
+	watchProvider.Delete("/watch-provider/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.DeleteWatchProvider)
+	watchProvider.Get("/movie/:id/availability", handlerWatchProvider.GetAvailability)
+	watchProvider.Post("/movie/:id/availability", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.PostAvailability)
+	watchProvider.Patch("/availability/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.UpdateAvailability)
+	watchProvider.Delete("/availability/:id", middleware.Auth, middleware.Role(domain.RoleEditor, domain.RoleAdmin), handlerWatchProvider.DeleteAvailability)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"strconv"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/labstack/gommon/log"
+	"github.com/valyala/fasthttp"
+)
+
+type WatchProviderHandler struct {
+	WatchProviderUseCase domain.WatchProviderUseCase
+}
+
+func (wh *WatchProviderHandler) GetAllWatchProvider(c *fiber.Ctx) error {
+	var input domain.RequestParamWatchProvider
+	var err error
+	input.Page, input.Limit, err = helper.ParsePaging(c)
+	if err != nil {
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	search := c.Query("search")
+	if search != "" {
+		input.Search = &search
+	}
+
+	res, err := wh.WatchProviderUseCase.GetAllWatchProvider(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (wh *WatchProviderHandler) PostWatchProvider(c *fiber.Ctx) (err error) {
+	var input domain.RequestWatchProvider
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	id, err := wh.WatchProviderUseCase.PostWatchProvider(c.Context(), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+func (wh *WatchProviderHandler) GetDetailWatchProvider(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	res, err := wh.WatchProviderUseCase.GetDetailWatchProvider(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (wh *WatchProviderHandler) UpdateWatchProvider(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestWatchProvider
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = wh.WatchProviderUseCase.UpdateWatchProvider(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (wh *WatchProviderHandler) DeleteWatchProvider(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = wh.WatchProviderUseCase.DeleteWatchProvider(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
+
+// GetAvailability lists where the movie is watched, records outside of their validity window
+// are only listed with current=false
+func (wh *WatchProviderHandler) GetAvailability(c *fiber.Ctx) (err error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	input := domain.RequestParamAvailability{MovieID: id, Current: true}
+	region := c.Query("region")
+	if region != "" {
+		input.Region = &region
+	}
+
+	typ := c.Query("type")
+	if typ != "" {
+		input.Type = &typ
+	}
+
+	if c.Query("current") != "" {
+		input.Current, err = strconv.ParseBool(c.Query("current"))
+		if err != nil {
+			return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+		}
+	}
+
+	res, err := wh.WatchProviderUseCase.GetAvailability(c.Context(), input)
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).JSON(res)
+}
+
+func (wh *WatchProviderHandler) PostAvailability(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestAvailability
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	availabilityID, err := wh.WatchProviderUseCase.PostAvailability(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusCreated).JSON(fiber.Map{"id": availabilityID})
+}
+
+func (wh *WatchProviderHandler) UpdateAvailability(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	var input domain.RequestAvailability
+	err = c.BodyParser(&input)
+	if err != nil {
+		log.Error(err.Error())
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = wh.WatchProviderUseCase.UpdateAvailability(c.Context(), int(id), input)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Updated")
+}
+
+func (wh *WatchProviderHandler) DeleteAvailability(c *fiber.Ctx) (err error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		log.Error(err)
+		return helper.HttpSimpleResponse(c, fasthttp.StatusBadRequest)
+	}
+
+	err = wh.WatchProviderUseCase.DeleteAvailability(c.Context(), int(id))
+	if err != nil {
+		return helper.HttpResponseFromError(c, err)
+	}
+	return c.Status(fasthttp.StatusOK).SendString("Deleted")
+}
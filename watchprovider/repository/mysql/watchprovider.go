@@ -0,0 +1,357 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"xsis-academy-test-service-movie/domain"
+
+	"github.com/labstack/gommon/log"
+)
+
+type mysqlWatchProviderRepository struct {
+	Conn *sql.DB
+}
+
+func NewMySQLWatchProviderRepository(Conn *sql.DB) domain.WatchProviderMySQLRepo {
+	return &mysqlWatchProviderRepository{Conn}
+}
+
+const watchProviderQuery = `SELECT id, name, slug, logo_url, website, dtm_crt, dtm_upd FROM watch_provider`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWatchProvider(row rowScanner) (response domain.ResponseWatchProvider, err error) {
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(&response.ID, &response.Name, &response.Slug, &response.LogoURL, &response.Website, &dtmCrt, &dtmUpd)
+	if err != nil {
+		return response, err
+	}
+
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) PostWatchProvider(ctx context.Context, request domain.RequestWatchProvider) (id int, err error) {
+	query := `INSERT INTO watch_provider (name, slug, logo_url, website, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, request.Name, request.Slug, request.LogoURL, request.Website)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+func filterWatchProvider(request domain.RequestParamWatchProvider) (query string, args []interface{}) {
+	query = " WHERE 1=1"
+
+	if request.Search != nil {
+		query += " AND (name LIKE ? OR slug LIKE ?)"
+		args = append(args, "%"+*request.Search+"%", "%"+*request.Search+"%")
+	}
+
+	return query, args
+}
+
+func (db *mysqlWatchProviderRepository) CountDataWatchProvider(ctx context.Context, request domain.RequestParamWatchProvider) (response domain.MetaData, err error) {
+	filter, args := filterWatchProvider(request)
+	query := "SELECT COUNT(id) as total FROM watch_provider" + filter
+
+	log.Debug(query)
+
+	var count int
+	err = db.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return response, err
+	}
+
+	if count == 0 {
+		err = errors.New("Not found")
+		return domain.MetaData{}, err
+	}
+
+	var limit, page int
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	totalPage := uint(1)
+	if limit > 0 {
+		totalPage = uint(count) / uint(limit)
+		if uint(count)%uint(limit) != 0 {
+			totalPage++
+		}
+	}
+
+	response = domain.MetaData{
+		TotalData: uint(count),
+		TotalPage: totalPage,
+		Page:      uint(page),
+		Limit:     uint(limit),
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) GetAllWatchProvider(ctx context.Context, request domain.RequestParamWatchProvider) (response []domain.ResponseWatchProvider, err error) {
+	filter, args := filterWatchProvider(request)
+	query := watchProviderQuery + filter + ` ORDER BY name`
+	var limit, page int
+
+	if request.Page != nil {
+		page = *request.Page
+	}
+
+	if request.Limit != nil {
+		limit = *request.Limit
+		if limit > 0 {
+			query += " LIMIT ? OFFSET ?"
+			args = append(args, limit, (page-1)*limit)
+		}
+	}
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanWatchProvider(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) GetDetailWatchProvider(ctx context.Context, id int) (response domain.ResponseWatchProvider, err error) {
+	response, err = scanWatchProvider(db.Conn.QueryRowContext(ctx, watchProviderQuery+` WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseWatchProvider{}, err
+		}
+		log.Error(err)
+		return domain.ResponseWatchProvider{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) GetWatchProviderBySlug(ctx context.Context, slug string) (response domain.ResponseWatchProvider, err error) {
+	response, err = scanWatchProvider(db.Conn.QueryRowContext(ctx, watchProviderQuery+` WHERE slug = ?`, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseWatchProvider{}, err
+		}
+		log.Error(err)
+		return domain.ResponseWatchProvider{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) UpdateWatchProvider(ctx context.Context, id int, request domain.RequestWatchProvider) (err error) {
+	query := `UPDATE watch_provider
+              SET name = ?, slug = ?, logo_url = ?, website = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.Name, request.Slug, request.LogoURL, request.Website, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWatchProvider deletes the provider, its availability records are deleted by cascade
+func (db *mysqlWatchProviderRepository) DeleteWatchProvider(ctx context.Context, id int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `DELETE FROM watch_provider WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
+
+const availabilityQuery = `SELECT ma.id, ma.movie_id, wp.id, wp.name, wp.slug, wp.logo_url, wp.website,
+                  ma.region, ma.type, ma.price, ma.currency, ma.link, ma.valid_from, ma.valid_to, ma.dtm_crt, ma.dtm_upd
+              FROM movie_availability ma
+              JOIN watch_provider wp ON wp.id = ma.provider_id`
+
+func scanAvailability(row rowScanner) (response domain.ResponseAvailability, err error) {
+	var price sql.NullInt64
+	var currency sql.NullString
+	var validFrom, validTo sql.NullTime
+	var dtmCrt, dtmUpd time.Time
+	err = row.Scan(
+		&response.ID,
+		&response.MovieID,
+		&response.Provider.ID,
+		&response.Provider.Name,
+		&response.Provider.Slug,
+		&response.Provider.LogoURL,
+		&response.Provider.Website,
+		&response.Region,
+		&response.Type,
+		&price,
+		&currency,
+		&response.Link,
+		&validFrom,
+		&validTo,
+		&dtmCrt,
+		&dtmUpd,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	if price.Valid {
+		response.Price = &price.Int64
+	}
+	if currency.Valid {
+		response.Currency = &currency.String
+	}
+	if validFrom.Valid {
+		value := validFrom.Time.Format(domain.ReleaseDateLayout)
+		response.ValidFrom = &value
+	}
+	if validTo.Valid {
+		value := validTo.Time.Format(domain.ReleaseDateLayout)
+		response.ValidTo = &value
+	}
+	response.DtmCrt = dtmCrt.Format("2006-01-02 15:04:05")
+	response.DtmUpd = dtmUpd.Format("2006-01-02 15:04:05")
+	return response, nil
+}
+
+// PostAvailability adds an availability, a movie has one record per provider, region and type
+func (db *mysqlWatchProviderRepository) PostAvailability(ctx context.Context, movieID int, request domain.RequestAvailability) (id int, err error) {
+	query := `INSERT IGNORE INTO movie_availability (movie_id, provider_id, region, type, price, currency, link, valid_from, valid_to, dtm_crt, dtm_upd)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
+
+	res, err := db.Conn.ExecContext(ctx, query, movieID, request.ProviderID, request.Region, request.Type, request.Price,
+		request.Currency, request.Link, request.ValidFrom, request.ValidTo)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, errors.New("Exists")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(lastID), nil
+}
+
+func (db *mysqlWatchProviderRepository) GetAvailability(ctx context.Context, request domain.RequestParamAvailability) (response []domain.ResponseAvailability, err error) {
+	query := availabilityQuery + ` WHERE ma.movie_id = ?`
+	args := []interface{}{request.MovieID}
+
+	if request.Region != nil {
+		query += " AND ma.region = ?"
+		args = append(args, *request.Region)
+	}
+
+	if request.Type != nil {
+		query += " AND ma.type = ?"
+		args = append(args, *request.Type)
+	}
+
+	if request.Current {
+		query += " AND (ma.valid_from IS NULL OR ma.valid_from <= CURDATE()) AND (ma.valid_to IS NULL OR ma.valid_to >= CURDATE())"
+	}
+
+	query += " ORDER BY ma.region, FIELD(ma.type, 'free', 'stream', 'rent', 'buy'), wp.name"
+
+	log.Debug(query)
+	rows, err := db.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanAvailability(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		response = append(response, i)
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) GetDetailAvailability(ctx context.Context, id int) (response domain.ResponseAvailability, err error) {
+	response, err = scanAvailability(db.Conn.QueryRowContext(ctx, availabilityQuery+` WHERE ma.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.New("Not found")
+			return domain.ResponseAvailability{}, err
+		}
+		log.Error(err)
+		return domain.ResponseAvailability{}, err
+	}
+
+	return response, nil
+}
+
+func (db *mysqlWatchProviderRepository) UpdateAvailability(ctx context.Context, id int, request domain.RequestAvailability) (err error) {
+	query := `UPDATE movie_availability
+              SET provider_id = ?, region = ?, type = ?, price = ?, currency = ?, link = ?, valid_from = ?, valid_to = ?, dtm_upd = NOW()
+              WHERE id = ?`
+
+	_, err = db.Conn.ExecContext(ctx, query, request.ProviderID, request.Region, request.Type, request.Price,
+		request.Currency, request.Link, request.ValidFrom, request.ValidTo, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *mysqlWatchProviderRepository) DeleteAvailability(ctx context.Context, id int) (err error) {
+	_, err = db.Conn.ExecContext(ctx, `DELETE FROM movie_availability WHERE id = ?`, id)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return
+}
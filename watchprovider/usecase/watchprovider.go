@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"xsis-academy-test-service-movie/constant"
+	"xsis-academy-test-service-movie/domain"
+	"xsis-academy-test-service-movie/helper"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	regionPattern   = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+type watchProviderUseCase struct {
+	watchProviderMySQLRepo domain.WatchProviderMySQLRepo
+	movieMySQLRepo         domain.MovieMySQLRepo
+}
+
+func NewWatchProviderUsecase(WatchProviderMySQLRepo domain.WatchProviderMySQLRepo, MovieMySQLRepo domain.MovieMySQLRepo) domain.WatchProviderUseCase {
+	return &watchProviderUseCase{
+		watchProviderMySQLRepo: WatchProviderMySQLRepo,
+		movieMySQLRepo:         MovieMySQLRepo,
+	}
+}
+
+func validLink(link string) bool {
+	parsed, err := url.Parse(link)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// validate checks the provider, the slug defaults to the slug of the name and is unique
+func (wpu *watchProviderUseCase) validate(ctx context.Context, id int, request *domain.RequestWatchProvider) (err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	request.LogoURL = strings.TrimSpace(request.LogoURL)
+	request.Website = strings.TrimSpace(request.Website)
+	if request.Name == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("name is required")}
+	}
+
+	if request.Slug == "" {
+		request.Slug = request.Name
+	}
+	request.Slug = helper.Slugify(request.Slug)
+	if request.Slug == "" {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("slug is required")}
+	}
+
+	if request.LogoURL != "" && !validLink(request.LogoURL) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("logo_url must be an http or https URL")}
+	}
+	if request.Website != "" && !validLink(request.Website) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("website must be an http or https URL")}
+	}
+
+	existing, err := wpu.watchProviderMySQLRepo.GetWatchProviderBySlug(ctx, request.Slug)
+	if err == nil && int(existing.ID) != id {
+		return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("slug already exists")}
+	}
+	if err != nil && err.Error() != "Not found" {
+		return err
+	}
+	return nil
+}
+
+func (wpu *watchProviderUseCase) PostWatchProvider(ctx context.Context, request domain.RequestWatchProvider) (id int, err error) {
+	err = wpu.validate(ctx, 0, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = wpu.watchProviderMySQLRepo.PostWatchProvider(ctx, request)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (wpu *watchProviderUseCase) GetAllWatchProvider(ctx context.Context, request domain.RequestParamWatchProvider) (response domain.ResponseGetAllWatchProvider, err error) {
+	resCount, err := wpu.watchProviderMySQLRepo.CountDataWatchProvider(ctx, request)
+	if err != nil {
+		return domain.ResponseGetAllWatchProvider{}, err
+	}
+
+	resProvider, err := wpu.watchProviderMySQLRepo.GetAllWatchProvider(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response = domain.ResponseGetAllWatchProvider{
+		MetaData: resCount,
+		Data:     resProvider,
+	}
+	return
+}
+
+func (wpu *watchProviderUseCase) GetDetailWatchProvider(ctx context.Context, id int) (response domain.ResponseWatchProvider, err error) {
+	return wpu.watchProviderMySQLRepo.GetDetailWatchProvider(ctx, id)
+}
+
+func (wpu *watchProviderUseCase) UpdateWatchProvider(ctx context.Context, id int, request domain.RequestWatchProvider) (err error) {
+	_, err = wpu.watchProviderMySQLRepo.GetDetailWatchProvider(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = wpu.validate(ctx, id, &request)
+	if err != nil {
+		return err
+	}
+
+	err = wpu.watchProviderMySQLRepo.UpdateWatchProvider(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+// DeleteWatchProvider deletes the provider with its availability records
+func (wpu *watchProviderUseCase) DeleteWatchProvider(ctx context.Context, id int) (err error) {
+	_, err = wpu.watchProviderMySQLRepo.GetDetailWatchProvider(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return wpu.watchProviderMySQLRepo.DeleteWatchProvider(ctx, id)
+}
+
+// validateAvailability checks the availability, a movie has one record per provider, region and type
+func (wpu *watchProviderUseCase) validateAvailability(ctx context.Context, movieID int, id int, request *domain.RequestAvailability) (err error) {
+	_, err = wpu.watchProviderMySQLRepo.GetDetailWatchProvider(ctx, request.ProviderID)
+	if err != nil {
+		if err.Error() == "Not found" {
+			return constant.ResultError{Code: constant.StatusBadRequestNotExists, Err: errors.New("provider is not exists")}
+		}
+		return err
+	}
+
+	request.Region = strings.ToUpper(strings.TrimSpace(request.Region))
+	if !regionPattern.MatchString(request.Region) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("region must be an ISO 3166-1 alpha-2 country code")}
+	}
+
+	switch request.Type {
+	case domain.AvailabilityTypeStream, domain.AvailabilityTypeRent, domain.AvailabilityTypeBuy, domain.AvailabilityTypeFree:
+	default:
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("type must be stream, rent, buy or free")}
+	}
+
+	if request.Price != nil {
+		if *request.Price < 0 {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("price must not be negative")}
+		}
+		if request.Currency == nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("currency is required with price")}
+		}
+	}
+	if request.Currency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*request.Currency))
+		if !currencyPattern.MatchString(currency) {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("currency must be an ISO 4217 code")}
+		}
+		request.Currency = &currency
+	}
+
+	request.Link = strings.TrimSpace(request.Link)
+	if !validLink(request.Link) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("link must be an http or https URL")}
+	}
+
+	var validFrom, validTo time.Time
+	if request.ValidFrom != nil {
+		validFrom, err = time.Parse(domain.ReleaseDateLayout, *request.ValidFrom)
+		if err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("valid_from must be formatted as YYYY-MM-DD")}
+		}
+	}
+	if request.ValidTo != nil {
+		validTo, err = time.Parse(domain.ReleaseDateLayout, *request.ValidTo)
+		if err != nil {
+			return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("valid_to must be formatted as YYYY-MM-DD")}
+		}
+	}
+	if request.ValidFrom != nil && request.ValidTo != nil && validTo.Before(validFrom) {
+		return constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("valid_to must not be before valid_from")}
+	}
+
+	existing, err := wpu.watchProviderMySQLRepo.GetAvailability(ctx, domain.RequestParamAvailability{
+		MovieID: movieID,
+		Region:  &request.Region,
+		Type:    &request.Type,
+	})
+	if err != nil {
+		return err
+	}
+	for _, availability := range existing {
+		if int(availability.Provider.ID) == request.ProviderID && int(availability.ID) != id {
+			return constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("availability already exists for this provider, region and type")}
+		}
+	}
+	return nil
+}
+
+func (wpu *watchProviderUseCase) PostAvailability(ctx context.Context, movieID int, request domain.RequestAvailability) (id int, err error) {
+	_, err = wpu.movieMySQLRepo.GetDetailMovie(ctx, movieID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = wpu.validateAvailability(ctx, movieID, 0, &request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err = wpu.watchProviderMySQLRepo.PostAvailability(ctx, movieID, request)
+	if err != nil {
+		log.Error(err)
+		if err.Error() == "Exists" {
+			return 0, constant.ResultError{Code: constant.StatusBadRequestExists, Err: errors.New("availability already exists for this provider, region and type")}
+		}
+		return 0, err
+	}
+	return
+}
+
+// GetAvailability returns where the movie is watched, by region and type
+func (wpu *watchProviderUseCase) GetAvailability(ctx context.Context, request domain.RequestParamAvailability) (response []domain.ResponseAvailability, err error) {
+	_, err = wpu.movieMySQLRepo.GetDetailMovie(ctx, request.MovieID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Region != nil {
+		region := strings.ToUpper(*request.Region)
+		if !regionPattern.MatchString(region) {
+			return nil, constant.ResultError{Code: constant.StatusBadRequestErrorValidation, Err: errors.New("region must be an ISO 3166-1 alpha-2 country code")}
+		}
+		request.Region = &region
+	}
+
+	response, err = wpu.watchProviderMySQLRepo.GetAvailability(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		response = []domain.ResponseAvailability{}
+	}
+	return response, nil
+}
+
+func (wpu *watchProviderUseCase) UpdateAvailability(ctx context.Context, id int, request domain.RequestAvailability) (err error) {
+	availability, err := wpu.watchProviderMySQLRepo.GetDetailAvailability(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = wpu.validateAvailability(ctx, int(availability.MovieID), id, &request)
+	if err != nil {
+		return err
+	}
+
+	err = wpu.watchProviderMySQLRepo.UpdateAvailability(ctx, id, request)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	return
+}
+
+func (wpu *watchProviderUseCase) DeleteAvailability(ctx context.Context, id int) (err error) {
+	_, err = wpu.watchProviderMySQLRepo.GetDetailAvailability(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return wpu.watchProviderMySQLRepo.DeleteAvailability(ctx, id)
+}